@@ -0,0 +1,101 @@
+package satellite
+
+import (
+	"fmt"
+	"time"
+)
+
+// Precision of the start and end of an access interval
+const accessTolerance = time.Millisecond
+
+// AccessInterval is a span during which a satellite is above a station's minimum elevation
+type AccessInterval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Returns the length of the interval
+func (a AccessInterval) Duration() time.Duration {
+	return a.End.Sub(a.Start)
+}
+
+// Calculates the intervals from start to end during which a propagator is at or above a minimum elevation seen from a
+// station. Elevations are sampled every step and the crossings bisected, passes shorter than the step can be missed.
+// Intervals are clipped to the window.
+func AccessIntervals(p Propagator, station Coordinates, minElevation Angle, start, end time.Time, step time.Duration, gravConst Gravity) ([]AccessInterval, error) {
+	grav, err := getGravConst(gravConst)
+	if err != nil {
+		return nil, fmt.Errorf("getGravConst: %w", err)
+	}
+	times, err := sampleTimes(start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	visible := func(t time.Time) (bool, error) {
+		look, err := PropagatorLookAngles(p, station, t, grav)
+		if err != nil {
+			return false, fmt.Errorf("look angles at %v: %w", t, err)
+		}
+		return look.Elevation.Radians() >= minElevation.Radians(), nil
+	}
+	sampled := func(i int) (bool, error) {
+		return visible(times[i])
+	}
+	return findIntervals(times, sampled, visible)
+}
+
+// findIntervals walks the visibility at the sample times, known by index, and bisects each change with visible.
+// Intervals still open at the last sample end there.
+func findIntervals(times []time.Time, sampled func(i int) (bool, error), visible func(time.Time) (bool, error)) ([]AccessInterval, error) {
+	var intervals []AccessInterval
+	var current *AccessInterval
+	previous := times[0]
+	for i, t := range times {
+		up, err := sampled(i)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case up && current == nil:
+			current = &AccessInterval{Start: t}
+			if i > 0 {
+				if current.Start, err = bisectVisibility(visible, previous, t); err != nil {
+					return nil, err
+				}
+			}
+		case !up && current != nil:
+			if current.End, err = bisectVisibility(visible, previous, t); err != nil {
+				return nil, err
+			}
+			intervals = append(intervals, *current)
+			current = nil
+		}
+		previous = t
+	}
+	if current != nil {
+		current.End = times[len(times)-1]
+		intervals = append(intervals, *current)
+	}
+	return intervals, nil
+}
+
+// bisectVisibility returns the time at which visibility changes between a and b
+func bisectVisibility(visible func(time.Time) (bool, error), a, b time.Time) (time.Time, error) {
+	upAtA, err := visible(a)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for b.Sub(a) > accessTolerance {
+		middle := a.Add(b.Sub(a) / 2)
+		up, err := visible(middle)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if up == upAtA {
+			a = middle
+		} else {
+			b = middle
+		}
+	}
+	return b, nil
+}
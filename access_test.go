@@ -0,0 +1,64 @@
+package satellite
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite/internal/testsat"
+)
+
+func TestAccessIntervals(t *testing.T) {
+	sat := testsat.ISS(t, TLEToSat, GravityWGS72)
+	station := Geodetic(55.6167, 12.65, 0.005)
+	start := time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	intervals, err := AccessIntervals(sat, station, Degrees(10), start, end, time.Minute, GravityWGS72)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(intervals) == 0 {
+		t.Fatalf("expected passes over a day")
+	}
+	for i, interval := range intervals {
+		if interval.Duration() <= 0 || interval.Duration() > 15*time.Minute {
+			t.Errorf("interval %d lasts %v", i, interval.Duration())
+		}
+		if i > 0 && !interval.Start.After(intervals[i-1].End) {
+			t.Errorf("interval %d overlaps the previous one", i)
+		}
+		for _, edge := range []time.Time{interval.Start, interval.End} {
+			if edge.Equal(start) || edge.Equal(end) {
+				continue
+			}
+			look, err := PropagatorLookAngles(sat, station, edge, sat.GravityConst)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !closeFloatWithin(look.Elevation.Degrees(), 10, 1e-3) {
+				t.Errorf("interval %d edge at %v has elevation %f", i, edge, look.Elevation.Degrees())
+			}
+		}
+		middle := interval.Start.Add(interval.Duration() / 2)
+		look, err := PropagatorLookAngles(sat, station, middle, sat.GravityConst)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if look.Elevation.Degrees() < 10 {
+			t.Errorf("interval %d is below the minimum elevation in the middle", i)
+		}
+	}
+
+	none, err := AccessIntervals(sat, station, Degrees(89.99), start, start.Add(time.Hour), time.Minute, GravityWGS72)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(none) != 0 {
+		t.Errorf("expected no access near the zenith, got %v", none)
+	}
+
+	if _, err := AccessIntervals(sat, station, Angle{}, start, start, 0, GravityWGS72); !errors.Is(err, ErrInvalidSampling) {
+		t.Errorf("expected ErrInvalidSampling, got %v", err)
+	}
+}
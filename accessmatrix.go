@@ -0,0 +1,281 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+var ErrInvalidAccess = errors.New("invalid access request")
+var ErrInvalidHorizonMask = errors.New("invalid horizon mask")
+
+// HorizonPoint is the lowest visible elevation in one direction
+type HorizonPoint struct {
+	Azimuth   Angle
+	Elevation Angle
+}
+
+// HorizonMask is the lowest visible elevation around a station, linearly interpolated between points ordered by
+// azimuth from 0 to 2 pi, wrapping from the last point to the first
+type HorizonMask []HorizonPoint
+
+// Returns the lowest visible elevation at the azimuth, zero for an empty mask
+func (m HorizonMask) Elevation(azimuth Angle) Angle {
+	switch len(m) {
+	case 0:
+		return Angle{}
+	case 1:
+		return m[0].Elevation
+	}
+	az := wrapTwoPi(azimuth.Radians())
+	// the first point after the azimuth, wrapping to the first point of the next turn
+	i := sort.Search(len(m), func(i int) bool { return m[i].Azimuth.Radians() > az })
+	before, after := m[(i+len(m)-1)%len(m)], m[i%len(m)]
+	span := after.Azimuth.Radians() - before.Azimuth.Radians()
+	offset := az - before.Azimuth.Radians()
+	if span <= 0 {
+		span += 2 * math.Pi
+	}
+	if offset < 0 {
+		offset += 2 * math.Pi
+	}
+	low, high := before.Elevation.Radians(), after.Elevation.Radians()
+	return Radians(low + (high-low)*offset/span)
+}
+
+func (m HorizonMask) validate() error {
+	for i, point := range m {
+		az := point.Azimuth.Radians()
+		if az < 0 || az >= 2*math.Pi || (i > 0 && az <= m[i-1].Azimuth.Radians()) {
+			return fmt.Errorf("%w: azimuth %v of point %d is not increasing from 0 to 360 degrees", ErrInvalidHorizonMask, point.Azimuth, i)
+		}
+	}
+	return nil
+}
+
+// AccessSatellite is a propagator to compute access for. It is called from several goroutines at once, which the
+// propagators of this package allow.
+type AccessSatellite struct {
+	ID         string
+	Propagator Propagator
+}
+
+// AccessStation is a ground station seeing satellites at or above both its minimum elevation and its horizon mask
+type AccessStation struct {
+	ID           string
+	Coordinates  Coordinates
+	MinElevation Angle
+	// Optional
+	Mask HorizonMask
+}
+
+// Returns the lowest visible elevation at the azimuth
+func (s AccessStation) minElevation(azimuth Angle) Angle {
+	if len(s.Mask) == 0 {
+		return s.MinElevation
+	}
+	return Radians(math.Max(s.MinElevation.Radians(), s.Mask.Elevation(azimuth).Radians()))
+}
+
+type AccessSettings struct {
+	Start time.Time
+	End   time.Time
+	// Sample step of the elevations, passes shorter than the step can be missed
+	Step    time.Duration
+	Gravity Gravity
+	// Number of goroutines, defaults to GOMAXPROCS
+	Workers int
+}
+
+// Access is an interval during which a satellite is visible from a station
+type Access struct {
+	Satellite string
+	Station   string
+	AccessInterval
+}
+
+// AccessMatrix holds the access intervals of every satellite and station pair
+type AccessMatrix struct {
+	Satellites []string
+	Stations   []string
+
+	// indexed [satellite][station]
+	intervals  [][][]AccessInterval
+	satellites map[string]int
+	stations   map[string]int
+}
+
+// Computes the access intervals of every satellite from every station in parallel, as AccessIntervals does for one
+// pair. Each satellite is propagated once per sample and shared between the stations, only the bisection of the
+// crossings propagates again.
+func ComputeAccess(satellites []AccessSatellite, stations []AccessStation, settings AccessSettings) (AccessMatrix, error) {
+	grav, err := getGravConst(settings.Gravity)
+	if err != nil {
+		return AccessMatrix{}, fmt.Errorf("getGravConst: %w", err)
+	}
+	times, err := sampleTimes(settings.Start, settings.End, settings.Step)
+	if err != nil {
+		return AccessMatrix{}, err
+	}
+	m := AccessMatrix{satellites: map[string]int{}, stations: map[string]int{}}
+	for i, sat := range satellites {
+		if _, ok := m.satellites[sat.ID]; ok || sat.ID == "" {
+			return AccessMatrix{}, fmt.Errorf("%w: satellite id %q", ErrInvalidAccess, sat.ID)
+		}
+		m.satellites[sat.ID] = i
+		m.Satellites = append(m.Satellites, sat.ID)
+	}
+	for i, station := range stations {
+		if _, ok := m.stations[station.ID]; ok || station.ID == "" {
+			return AccessMatrix{}, fmt.Errorf("%w: station id %q", ErrInvalidAccess, station.ID)
+		}
+		if err := station.Mask.validate(); err != nil {
+			return AccessMatrix{}, fmt.Errorf("station %s: %w", station.ID, err)
+		}
+		m.stations[station.ID] = i
+		m.Stations = append(m.Stations, station.ID)
+	}
+	workers := settings.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	jdays := make([]float64, len(times))
+	for i, t := range times {
+		jdays[i] = JDayTime(t)
+	}
+	positions := make([][]Vector3, len(satellites))
+	err = parallel(len(satellites), workers, func(i int) error {
+		positions[i] = make([]Vector3, len(times))
+		for j, t := range times {
+			pos, _, err := satellites[i].Propagator.PositionVelocity(t)
+			if err != nil {
+				return fmt.Errorf("satellite %s: position at %v: %w", satellites[i].ID, t, err)
+			}
+			positions[i][j] = pos
+		}
+		return nil
+	})
+	if err != nil {
+		return AccessMatrix{}, err
+	}
+
+	m.intervals = make([][][]AccessInterval, len(satellites))
+	for i := range m.intervals {
+		m.intervals[i] = make([][]AccessInterval, len(stations))
+	}
+	err = parallel(len(satellites)*len(stations), workers, func(job int) error {
+		sat, station := satellites[job/len(stations)], stations[job%len(stations)]
+		satPositions := positions[job/len(stations)]
+		sampled := func(i int) (bool, error) {
+			look := ECIToLookAngles(satPositions[i], station.Coordinates, jdays[i], grav)
+			return look.Elevation.Radians() >= station.minElevation(look.Azimuth).Radians(), nil
+		}
+		visible := func(t time.Time) (bool, error) {
+			look, err := PropagatorLookAngles(sat.Propagator, station.Coordinates, t, grav)
+			if err != nil {
+				return false, fmt.Errorf("satellite %s: look angles at %v: %w", sat.ID, t, err)
+			}
+			return look.Elevation.Radians() >= station.minElevation(look.Azimuth).Radians(), nil
+		}
+		intervals, err := findIntervals(times, sampled, visible)
+		if err != nil {
+			return fmt.Errorf("station %s: %w", station.ID, err)
+		}
+		m.intervals[job/len(stations)][job%len(stations)] = intervals
+		return nil
+	})
+	if err != nil {
+		return AccessMatrix{}, err
+	}
+	return m, nil
+}
+
+// parallel runs jobs 0 to n-1 on workers goroutines and returns the error of the lowest failed job
+func parallel(n, workers int, job func(i int) error) error {
+	errs := make([]error, n)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = job(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the access intervals of a satellite from a station, nil for unknown IDs
+func (m AccessMatrix) Intervals(satellite, station string) []AccessInterval {
+	i, ok := m.satellites[satellite]
+	if !ok {
+		return nil
+	}
+	j, ok := m.stations[station]
+	if !ok {
+		return nil
+	}
+	return m.intervals[i][j]
+}
+
+// Returns the accesses of a satellite from every station ordered by start
+func (m AccessMatrix) BySatellite(satellite string) []Access {
+	return m.filter(func(sat, _ string, _ AccessInterval) bool { return sat == satellite })
+}
+
+// Returns the accesses of every satellite from a station ordered by start
+func (m AccessMatrix) ByStation(station string) []Access {
+	return m.filter(func(_, s string, _ AccessInterval) bool { return s == station })
+}
+
+// Returns the accesses in progress at a time ordered by start
+func (m AccessMatrix) At(t time.Time) []Access {
+	return m.filter(func(_, _ string, interval AccessInterval) bool {
+		return !t.Before(interval.Start) && !t.After(interval.End)
+	})
+}
+
+// Returns the accesses overlapping the window from start to end ordered by start
+func (m AccessMatrix) Between(start, end time.Time) []Access {
+	return m.filter(func(_, _ string, interval AccessInterval) bool {
+		return !interval.End.Before(start) && !interval.Start.After(end)
+	})
+}
+
+// Returns every access ordered by start
+func (m AccessMatrix) All() []Access {
+	return m.filter(func(string, string, AccessInterval) bool { return true })
+}
+
+// filter returns the matching accesses ordered by start, then satellite and station order
+func (m AccessMatrix) filter(match func(satellite, station string, interval AccessInterval) bool) []Access {
+	var accesses []Access
+	for i, sat := range m.Satellites {
+		for j, station := range m.Stations {
+			for _, interval := range m.intervals[i][j] {
+				if match(sat, station, interval) {
+					accesses = append(accesses, Access{Satellite: sat, Station: station, AccessInterval: interval})
+				}
+			}
+		}
+	}
+	sort.SliceStable(accesses, func(a, b int) bool { return accesses[a].Start.Before(accesses[b].Start) })
+	return accesses
+}
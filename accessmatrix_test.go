@@ -0,0 +1,195 @@
+package satellite
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite/internal/testsat"
+)
+
+func TestHorizonMaskElevation(t *testing.T) {
+	mask := HorizonMask{
+		{Azimuth: Degrees(0), Elevation: Degrees(10)},
+		{Azimuth: Degrees(90), Elevation: Degrees(30)},
+		{Azimuth: Degrees(270), Elevation: Degrees(10)},
+	}
+	tests := []struct {
+		azimuth float64
+		want    float64
+	}{
+		{0, 10},
+		{45, 20},
+		{90, 30},
+		{180, 20},
+		{315, 10},
+		{-90, 10},
+		{405, 20},
+	}
+	for _, test := range tests {
+		if got := mask.Elevation(Degrees(test.azimuth)).Degrees(); !closeFloatWithin(got, test.want, 1e-9) {
+			t.Errorf("elevation at azimuth %v is %v, want %v", test.azimuth, got, test.want)
+		}
+	}
+
+	wrapping := HorizonMask{{Azimuth: Degrees(90), Elevation: Degrees(0)}, {Azimuth: Degrees(270), Elevation: Degrees(20)}}
+	for azimuth, want := range map[float64]float64{0: 10, 315: 15, 45: 5, 180: 10} {
+		if got := wrapping.Elevation(Degrees(azimuth)).Degrees(); !closeFloatWithin(got, want, 1e-9) {
+			t.Errorf("elevation at azimuth %v is %v, want %v", azimuth, got, want)
+		}
+	}
+	if got := (HorizonMask{{Azimuth: Degrees(10), Elevation: Degrees(5)}}).Elevation(Degrees(200)).Degrees(); !closeFloat(got, 5) {
+		t.Errorf("expected a single point mask to be constant, got %v", got)
+	}
+}
+
+func TestComputeAccess(t *testing.T) {
+	iss := testsat.ISS(t, TLEToSat, GravityWGS72)
+	start := time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	pos, vel, err := Propagate(iss, start)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// a second orbit 20 minutes behind
+	trailing, err := NewJ2Propagator(start.Add(20*time.Minute), pos, vel, GravityWGS72)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	satellites := []AccessSatellite{{ID: "iss", Propagator: iss}, {ID: "trailing", Propagator: trailing}}
+
+	copenhagen := Geodetic(55.6167, 12.65, 0.005)
+	// the passes are to the south of a station north of the inclination
+	south := HorizonMask{
+		{Azimuth: Degrees(90), Elevation: Degrees(0)},
+		{Azimuth: Degrees(150), Elevation: Degrees(30)},
+		{Azimuth: Degrees(210), Elevation: Degrees(30)},
+		{Azimuth: Degrees(270), Elevation: Degrees(0)},
+	}
+	stations := []AccessStation{
+		{ID: "copenhagen", Coordinates: copenhagen, MinElevation: Degrees(10)},
+		{ID: "tokyo", Coordinates: Geodetic(35.6895, 139.6917, 0.04), MinElevation: Degrees(5)},
+		{ID: "copenhagen-masked", Coordinates: copenhagen, MinElevation: Degrees(10), Mask: south},
+	}
+	settings := AccessSettings{Start: start, End: end, Step: time.Minute, Gravity: GravityWGS72}
+	matrix, err := ComputeAccess(satellites, stations, settings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// without a mask every pair matches a single pair computation
+	for _, sat := range satellites {
+		for _, station := range stations[:2] {
+			want, err := AccessIntervals(sat.Propagator, station.Coordinates, station.MinElevation, start, end, time.Minute, GravityWGS72)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := matrix.Intervals(sat.ID, station.ID); len(want) == 0 || !reflect.DeepEqual(got, want) {
+				t.Errorf("%s from %s: got %v, want %v", sat.ID, station.ID, got, want)
+			}
+		}
+	}
+
+	// the mask shortens the passes and the edges follow it
+	grav, _ := getGravConst(GravityWGS72)
+	masked := matrix.Intervals("iss", "copenhagen-masked")
+	total := func(intervals []AccessInterval) (d time.Duration) {
+		for _, interval := range intervals {
+			d += interval.Duration()
+		}
+		return d
+	}
+	if len(masked) == 0 || total(masked) >= total(matrix.Intervals("iss", "copenhagen")) {
+		t.Errorf("expected the mask to shorten the access, got %v of %v", total(masked), total(matrix.Intervals("iss", "copenhagen")))
+	}
+	for _, interval := range masked {
+		for _, edge := range []time.Time{interval.Start, interval.End} {
+			look, err := PropagatorLookAngles(iss, copenhagen, edge, grav)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want := stations[2].minElevation(look.Azimuth); !closeFloatWithin(look.Elevation.Degrees(), want.Degrees(), 0.01) {
+				t.Errorf("edge at %v has elevation %v, want %v at azimuth %v", edge, look.Elevation, want, look.Azimuth)
+			}
+		}
+	}
+
+	// queries
+	all := matrix.All()
+	count := 0
+	for _, sat := range satellites {
+		for _, station := range stations {
+			count += len(matrix.Intervals(sat.ID, station.ID))
+		}
+	}
+	if len(all) != count {
+		t.Errorf("expected %d accesses, got %d", count, len(all))
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Start.Before(all[i-1].Start) {
+			t.Errorf("access %d is out of order", i)
+		}
+	}
+	for _, access := range matrix.BySatellite("trailing") {
+		if access.Satellite != "trailing" {
+			t.Errorf("unexpected access %+v", access)
+		}
+	}
+	if got := matrix.ByStation("tokyo"); len(got) != len(matrix.Intervals("iss", "tokyo"))+len(matrix.Intervals("trailing", "tokyo")) {
+		t.Errorf("unexpected accesses from tokyo %v", got)
+	}
+	first := matrix.Intervals("iss", "copenhagen")[0]
+	at := matrix.At(first.Start.Add(first.Duration() / 2))
+	found := false
+	for _, access := range at {
+		if access.Satellite == "iss" && access.Station == "copenhagen" && access.AccessInterval == first {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected the first pass in progress, got %v", at)
+	}
+	if got := matrix.Between(end.Add(time.Hour), end.Add(2*time.Hour)); len(got) != 0 {
+		t.Errorf("expected no accesses after the window, got %v", got)
+	}
+	if matrix.Intervals("iss", "unknown") != nil || matrix.BySatellite("unknown") != nil {
+		t.Errorf("expected nothing for unknown ids")
+	}
+
+	settings.Workers = 1
+	serial, err := ComputeAccess(satellites, stations, settings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(serial.All(), all) {
+		t.Errorf("expected the same accesses with one worker")
+	}
+}
+
+func TestComputeAccessInvalid(t *testing.T) {
+	sat := AccessSatellite{ID: "a", Propagator: KeplerPropagator{Mu: 398600.4418}}
+	station := AccessStation{ID: "s"}
+	start := time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+	settings := AccessSettings{Start: start, End: start.Add(time.Hour), Step: time.Minute, Gravity: GravityWGS84}
+	tests := []struct {
+		satellites []AccessSatellite
+		stations   []AccessStation
+		want       error
+	}{
+		{[]AccessSatellite{sat, sat}, []AccessStation{station}, ErrInvalidAccess},
+		{[]AccessSatellite{sat}, []AccessStation{station, station}, ErrInvalidAccess},
+		{[]AccessSatellite{{Propagator: sat.Propagator}}, []AccessStation{station}, ErrInvalidAccess},
+		{[]AccessSatellite{sat}, []AccessStation{{ID: "s", Mask: HorizonMask{{Azimuth: Degrees(90)}, {Azimuth: Degrees(45)}}}}, ErrInvalidHorizonMask},
+		{[]AccessSatellite{sat}, []AccessStation{{ID: "s", Mask: HorizonMask{{Azimuth: Degrees(360)}}}}, ErrInvalidHorizonMask},
+	}
+	for i, test := range tests {
+		if _, err := ComputeAccess(test.satellites, test.stations, settings); !errors.Is(err, test.want) {
+			t.Errorf("test %d: expected %v, got %v", i, test.want, err)
+		}
+	}
+	settings.Step = 0
+	if _, err := ComputeAccess([]AccessSatellite{sat}, []AccessStation{station}, settings); !errors.Is(err, ErrInvalidSampling) {
+		t.Errorf("expected ErrInvalidSampling, got %v", err)
+	}
+}
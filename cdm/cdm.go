@@ -0,0 +1,415 @@
+// Package cdm reads and writes CCSDS Conjunction Data Messages (CCSDS 508.0-B-1) in KVN and XML.
+//
+// Values keep the units of the message: relative and covariance quantities in m and m/s, states in km and km/s.
+// Keywords without a typed field are kept in order in Other so messages round trip.
+package cdm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+)
+
+var ErrInvalidCDM = errors.New("invalid conjunction data message")
+
+// Field is a keyword with its value and unit as written in the message
+type Field struct {
+	Key   string
+	Value string
+	Unit  string
+}
+
+type CDM struct {
+	Version      string
+	CreationDate time.Time
+	Originator   string
+	MessageFor   string
+	MessageID    string
+	Comments     []string
+
+	Relative RelativeMetadata
+	Objects  [2]Object
+}
+
+// RelativeMetadata describes the encounter between the two objects
+type RelativeMetadata struct {
+	Comments []string
+
+	TCA time.Time
+	// m
+	MissDistance float64
+	// m/s
+	RelativeSpeed float64
+	// Object 2 relative to object 1 in object 1's RTN frame, m and m/s
+	RelativePosition satellite.Vector3
+	RelativeVelocity satellite.Vector3
+
+	StartScreenPeriod time.Time
+	StopScreenPeriod  time.Time
+
+	CollisionProbability       float64
+	CollisionProbabilityMethod string
+
+	Other []Field
+}
+
+// Object is the metadata, state and covariance of one object at TCA
+type Object struct {
+	Comments []string
+
+	// OBJECT1 or OBJECT2
+	Object                  string
+	ObjectDesignator        string
+	CatalogName             string
+	ObjectName              string
+	InternationalDesignator string
+	ObjectType              string
+	EphemerisName           string
+	CovarianceMethod        string
+	Maneuverable            string
+	RefFrame                string
+
+	// km and km/s in RefFrame
+	Position satellite.Vector3
+	Velocity satellite.Vector3
+
+	// Position and velocity covariance in the object's RTN frame, m^2, m^2/s and m^2/s^2.
+	// Rows and columns are R, T, N, RDOT, TDOT, NDOT.
+	Covariance [6][6]float64
+
+	Other []Field
+}
+
+// Returns the value of a keyword kept in Other
+func (o Object) Get(key string) (string, bool) {
+	return getField(o.Other, key)
+}
+
+// Returns the value of a keyword kept in Other
+func (r RelativeMetadata) Get(key string) (string, bool) {
+	return getField(r.Other, key)
+}
+
+func getField(fields []Field, key string) (string, bool) {
+	for _, field := range fields {
+		if field.Key == key {
+			return field.Value, true
+		}
+	}
+	return "", false
+}
+
+// covarianceKeys are the lower triangle keywords of the 6x6 covariance in message order
+var covarianceKeys = []struct {
+	key      string
+	row, col int
+}{
+	{"CR_R", 0, 0},
+	{"CT_R", 1, 0}, {"CT_T", 1, 1},
+	{"CN_R", 2, 0}, {"CN_T", 2, 1}, {"CN_N", 2, 2},
+	{"CRDOT_R", 3, 0}, {"CRDOT_T", 3, 1}, {"CRDOT_N", 3, 2}, {"CRDOT_RDOT", 3, 3},
+	{"CTDOT_R", 4, 0}, {"CTDOT_T", 4, 1}, {"CTDOT_N", 4, 2}, {"CTDOT_RDOT", 4, 3}, {"CTDOT_TDOT", 4, 4},
+	{"CNDOT_R", 5, 0}, {"CNDOT_T", 5, 1}, {"CNDOT_N", 5, 2}, {"CNDOT_RDOT", 5, 3}, {"CNDOT_TDOT", 5, 4}, {"CNDOT_NDOT", 5, 5},
+}
+
+// covarianceUnit returns the unit of a covariance element
+func covarianceUnit(row, col int) string {
+	switch {
+	case row < 3 && col < 3:
+		return "m**2"
+	case row >= 3 && col >= 3:
+		return "m**2/s**2"
+	}
+	return "m**2/s"
+}
+
+// Keywords of the object metadata, OD parameters and additional parameters, used to place Other fields in XML
+var (
+	metadataKeys = []string{"OBJECT", "OBJECT_DESIGNATOR", "CATALOG_NAME", "OBJECT_NAME", "INTERNATIONAL_DESIGNATOR",
+		"OBJECT_TYPE", "OPERATOR_CONTACT_POSITION", "OPERATOR_ORGANIZATION", "OPERATOR_PHONE", "OPERATOR_EMAIL",
+		"EPHEMERIS_NAME", "COVARIANCE_METHOD", "MANEUVERABLE", "ORBIT_CENTER", "REF_FRAME", "GRAVITY_MODEL",
+		"ATMOSPHERIC_MODEL", "N_BODY_PERTURBATIONS", "SOLAR_RAD_PRESSURE", "EARTH_TIDES", "INTRACK_THRUST"}
+	odParameterKeys = []string{"TIME_LASTOB_START", "TIME_LASTOB_END", "RECOMMENDED_OD_SPAN", "ACTUAL_OD_SPAN",
+		"OBS_AVAILABLE", "OBS_USED", "TRACKS_AVAILABLE", "TRACKS_USED", "RESIDUALS_ACCEPTED", "WEIGHTED_RMS"}
+	additionalParameterKeys = []string{"AREA_PC", "AREA_DRG", "AREA_SRP", "MASS", "CD_AREA_OVER_MASS",
+		"CR_AREA_OVER_MASS", "THRUST_ACCELERATION", "SEDR"}
+)
+
+func contains(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Time layouts of CCSDS ASCII time codes A and B
+var timeLayouts = []string{"2006-01-02T15:04:05.999999999", "2006-002T15:04:05.999999999"}
+
+func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "Z")
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: time %q", ErrInvalidCDM, s)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000")
+}
+
+func parseFloat(s string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%w: %q", satellite.ErrNonFiniteValue, s)
+	}
+	return f, nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// set assigns a keyword to the header and relative metadata when object is -1, otherwise to that object
+func (c *CDM) set(object int, key, value, unit string) error {
+	var err error
+	float := func(dst *float64) {
+		*dst, err = parseFloat(value)
+	}
+	timestamp := func(dst *time.Time) {
+		*dst, err = parseTime(value)
+	}
+
+	if object < 0 {
+		r := &c.Relative
+		switch key {
+		case "CCSDS_CDM_VERS":
+			c.Version = value
+		case "CREATION_DATE":
+			timestamp(&c.CreationDate)
+		case "ORIGINATOR":
+			c.Originator = value
+		case "MESSAGE_FOR":
+			c.MessageFor = value
+		case "MESSAGE_ID":
+			c.MessageID = value
+		case "TCA":
+			timestamp(&r.TCA)
+		case "MISS_DISTANCE":
+			float(&r.MissDistance)
+		case "RELATIVE_SPEED":
+			float(&r.RelativeSpeed)
+		case "RELATIVE_POSITION_R":
+			float(&r.RelativePosition.X)
+		case "RELATIVE_POSITION_T":
+			float(&r.RelativePosition.Y)
+		case "RELATIVE_POSITION_N":
+			float(&r.RelativePosition.Z)
+		case "RELATIVE_VELOCITY_R":
+			float(&r.RelativeVelocity.X)
+		case "RELATIVE_VELOCITY_T":
+			float(&r.RelativeVelocity.Y)
+		case "RELATIVE_VELOCITY_N":
+			float(&r.RelativeVelocity.Z)
+		case "START_SCREEN_PERIOD":
+			timestamp(&r.StartScreenPeriod)
+		case "STOP_SCREEN_PERIOD":
+			timestamp(&r.StopScreenPeriod)
+		case "COLLISION_PROBABILITY":
+			float(&r.CollisionProbability)
+		case "COLLISION_PROBABILITY_METHOD":
+			r.CollisionProbabilityMethod = value
+		default:
+			r.Other = append(r.Other, Field{Key: key, Value: value, Unit: unit})
+		}
+	} else {
+		o := &c.Objects[object]
+		switch key {
+		case "OBJECT":
+			o.Object = value
+		case "OBJECT_DESIGNATOR":
+			o.ObjectDesignator = value
+		case "CATALOG_NAME":
+			o.CatalogName = value
+		case "OBJECT_NAME":
+			o.ObjectName = value
+		case "INTERNATIONAL_DESIGNATOR":
+			o.InternationalDesignator = value
+		case "OBJECT_TYPE":
+			o.ObjectType = value
+		case "EPHEMERIS_NAME":
+			o.EphemerisName = value
+		case "COVARIANCE_METHOD":
+			o.CovarianceMethod = value
+		case "MANEUVERABLE":
+			o.Maneuverable = value
+		case "REF_FRAME":
+			o.RefFrame = value
+		case "X":
+			float(&o.Position.X)
+		case "Y":
+			float(&o.Position.Y)
+		case "Z":
+			float(&o.Position.Z)
+		case "X_DOT":
+			float(&o.Velocity.X)
+		case "Y_DOT":
+			float(&o.Velocity.Y)
+		case "Z_DOT":
+			float(&o.Velocity.Z)
+		default:
+			for _, element := range covarianceKeys {
+				if element.key == key {
+					var v float64
+					float(&v)
+					o.Covariance[element.row][element.col] = v
+					o.Covariance[element.col][element.row] = v
+					return wrapField(key, err)
+				}
+			}
+			o.Other = append(o.Other, Field{Key: key, Value: value, Unit: unit})
+		}
+	}
+	return wrapField(key, err)
+}
+
+func wrapField(key string, err error) error {
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// validate checks the mandatory keywords are present
+func (c *CDM) validate() error {
+	if c.Relative.TCA.IsZero() {
+		return fmt.Errorf("%w: missing TCA", ErrInvalidCDM)
+	}
+	for i, o := range c.Objects {
+		if o.Object == "" {
+			return fmt.Errorf("%w: missing object %d", ErrInvalidCDM, i+1)
+		}
+	}
+	return nil
+}
+
+// keyValue is a keyword with its formatted value and unit for writing
+type keyValue struct {
+	key, value, unit string
+}
+
+// headerFields returns the header keywords in message order
+func (c CDM) headerFields() []keyValue {
+	version := c.Version
+	if version == "" {
+		version = "1.0"
+	}
+	return []keyValue{
+		{"CCSDS_CDM_VERS", version, ""},
+		{"CREATION_DATE", formatTime(c.CreationDate), ""},
+		{"ORIGINATOR", c.Originator, ""},
+		{"MESSAGE_FOR", c.MessageFor, ""},
+		{"MESSAGE_ID", c.MessageID, ""},
+	}
+}
+
+// relativeFields returns the relative metadata keywords in message order, optional zero values are left out
+func (r RelativeMetadata) relativeFields() (encounter, state, screening []keyValue) {
+	encounter = []keyValue{
+		{"TCA", formatTime(r.TCA), ""},
+		{"MISS_DISTANCE", formatFloat(r.MissDistance), "m"},
+	}
+	if r.RelativeSpeed != 0 {
+		encounter = append(encounter, keyValue{"RELATIVE_SPEED", formatFloat(r.RelativeSpeed), "m/s"})
+	}
+	if r.RelativePosition != (satellite.Vector3{}) || r.RelativeVelocity != (satellite.Vector3{}) {
+		state = []keyValue{
+			{"RELATIVE_POSITION_R", formatFloat(r.RelativePosition.X), "m"},
+			{"RELATIVE_POSITION_T", formatFloat(r.RelativePosition.Y), "m"},
+			{"RELATIVE_POSITION_N", formatFloat(r.RelativePosition.Z), "m"},
+			{"RELATIVE_VELOCITY_R", formatFloat(r.RelativeVelocity.X), "m/s"},
+			{"RELATIVE_VELOCITY_T", formatFloat(r.RelativeVelocity.Y), "m/s"},
+			{"RELATIVE_VELOCITY_N", formatFloat(r.RelativeVelocity.Z), "m/s"},
+		}
+	}
+	if !r.StartScreenPeriod.IsZero() {
+		screening = append(screening, keyValue{"START_SCREEN_PERIOD", formatTime(r.StartScreenPeriod), ""})
+	}
+	if !r.StopScreenPeriod.IsZero() {
+		screening = append(screening, keyValue{"STOP_SCREEN_PERIOD", formatTime(r.StopScreenPeriod), ""})
+	}
+	for _, field := range r.Other {
+		screening = append(screening, keyValue{field.Key, field.Value, field.Unit})
+	}
+	if r.CollisionProbability != 0 || r.CollisionProbabilityMethod != "" {
+		screening = append(screening, keyValue{"COLLISION_PROBABILITY", formatFloat(r.CollisionProbability), ""})
+	}
+	if r.CollisionProbabilityMethod != "" {
+		screening = append(screening, keyValue{"COLLISION_PROBABILITY_METHOD", r.CollisionProbabilityMethod, ""})
+	}
+	return encounter, state, screening
+}
+
+// objectFields returns the object keywords grouped by section in message order, empty metadata is left out
+func (o Object) objectFields() (metadata, odParameters, additionalParameters, state, covariance []keyValue) {
+	typed := []keyValue{
+		{"OBJECT", o.Object, ""},
+		{"OBJECT_DESIGNATOR", o.ObjectDesignator, ""},
+		{"CATALOG_NAME", o.CatalogName, ""},
+		{"OBJECT_NAME", o.ObjectName, ""},
+		{"INTERNATIONAL_DESIGNATOR", o.InternationalDesignator, ""},
+		{"OBJECT_TYPE", o.ObjectType, ""},
+		{"EPHEMERIS_NAME", o.EphemerisName, ""},
+		{"COVARIANCE_METHOD", o.CovarianceMethod, ""},
+		{"MANEUVERABLE", o.Maneuverable, ""},
+		{"REF_FRAME", o.RefFrame, ""},
+	}
+	// typed metadata and Other metadata keywords in the order of the standard
+	for _, key := range metadataKeys {
+		for _, field := range typed {
+			if field.key == key && field.value != "" {
+				metadata = append(metadata, field)
+			}
+		}
+		for _, field := range o.Other {
+			if field.Key == key {
+				metadata = append(metadata, keyValue{field.Key, field.Value, field.Unit})
+			}
+		}
+	}
+	for _, field := range o.Other {
+		switch {
+		case contains(metadataKeys, field.Key):
+		case contains(odParameterKeys, field.Key):
+			odParameters = append(odParameters, keyValue{field.Key, field.Value, field.Unit})
+		case contains(additionalParameterKeys, field.Key):
+			additionalParameters = append(additionalParameters, keyValue{field.Key, field.Value, field.Unit})
+		default:
+			metadata = append(metadata, keyValue{field.Key, field.Value, field.Unit})
+		}
+	}
+
+	state = []keyValue{
+		{"X", formatFloat(o.Position.X), "km"},
+		{"Y", formatFloat(o.Position.Y), "km"},
+		{"Z", formatFloat(o.Position.Z), "km"},
+		{"X_DOT", formatFloat(o.Velocity.X), "km/s"},
+		{"Y_DOT", formatFloat(o.Velocity.Y), "km/s"},
+		{"Z_DOT", formatFloat(o.Velocity.Z), "km/s"},
+	}
+	for _, element := range covarianceKeys {
+		covariance = append(covariance, keyValue{element.key, formatFloat(o.Covariance[element.row][element.col]), covarianceUnit(element.row, element.col)})
+	}
+	return metadata, odParameters, additionalParameters, state, covariance
+}
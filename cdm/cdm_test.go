@@ -0,0 +1,130 @@
+package cdm
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+	"github.com/infostellarinc/go-satellite/internal/testsat"
+)
+
+func readSample(t *testing.T) CDM {
+	f, err := os.Open("testdata/sample.cdm")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	c, err := ReadKVN(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestReadKVN(t *testing.T) {
+	c := readSample(t)
+
+	if c.MessageID != "201113719185" || c.Version != "1.0" {
+		t.Errorf("unexpected header %q %q", c.Version, c.MessageID)
+	}
+	tca := time.Date(2010, 3, 13, 22, 37, 52, 618000000, time.UTC)
+	if !c.Relative.TCA.Equal(tca) {
+		t.Errorf("expected TCA %v, got %v", tca, c.Relative.TCA)
+	}
+	if c.Relative.MissDistance != 715 || c.Relative.RelativeSpeed != 14762 {
+		t.Errorf("unexpected miss distance %v and relative speed %v", c.Relative.MissDistance, c.Relative.RelativeSpeed)
+	}
+	if c.Relative.RelativePosition != (satellite.Vector3{X: 27.4, Y: -70.2, Z: 711.8}) {
+		t.Errorf("unexpected relative position %v", c.Relative.RelativePosition)
+	}
+	if c.Relative.CollisionProbability != 4.835e-5 || c.Relative.CollisionProbabilityMethod != "FOSTER-1992" {
+		t.Errorf("unexpected collision probability %v %q", c.Relative.CollisionProbability, c.Relative.CollisionProbabilityMethod)
+	}
+	if shape, ok := c.Relative.Get("SCREEN_VOLUME_SHAPE"); !ok || shape != "ELLIPSOID" {
+		t.Errorf("expected SCREEN_VOLUME_SHAPE in Other, got %q", shape)
+	}
+
+	o1, o2 := c.Objects[0], c.Objects[1]
+	if o1.Object != "OBJECT1" || o2.Object != "OBJECT2" || o2.ObjectDesignator != "30337" {
+		t.Errorf("unexpected objects %q %q %q", o1.Object, o2.Object, o2.ObjectDesignator)
+	}
+	if o1.Comments[0] != "Object1 Metadata" {
+		t.Errorf("unexpected object comments %v", o1.Comments)
+	}
+	if o1.Position.X != 2570.097065 || o2.Velocity.Z != 3.328770172 {
+		t.Errorf("unexpected states %v %v", o1.Position, o2.Velocity)
+	}
+	if o1.Covariance[1][0] != -8.579 || o1.Covariance[0][1] != -8.579 || o2.Covariance[5][5] != 5.178e-5 {
+		t.Errorf("unexpected covariance")
+	}
+	if span, ok := o1.Get("RECOMMENDED_OD_SPAN"); !ok || span != "7.88" {
+		t.Errorf("expected RECOMMENDED_OD_SPAN without its unit, got %q", span)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	c := readSample(t)
+
+	var kvn bytes.Buffer
+	if err := WriteKVN(&kvn, c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fromKVN, err := ReadKVN(&kvn)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(c, fromKVN) {
+		t.Errorf("KVN round trip differs:\n%+v\n%+v", c, fromKVN)
+	}
+
+	var x bytes.Buffer
+	if err := WriteXML(&x, c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fromXML, err := ReadXML(&x)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(c, fromXML) {
+		t.Errorf("XML round trip differs:\n%+v\n%+v", c, fromXML)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	tests := []string{
+		"CCSDS_CDM_VERS = 1.0\nMISS_DISTANCE = 715 [m]\n",
+		"CCSDS_CDM_VERS = 1.0\nTCA = yesterday\n",
+		"CCSDS_CDM_VERS = 1.0\nTCA = 2010-03-13T22:37:52.618\nOBJECT = OBJECT1\n",
+		"CCSDS_CDM_VERS 1.0\n",
+	}
+	for _, test := range tests {
+		if _, err := ReadKVN(strings.NewReader(test)); err == nil {
+			t.Errorf("expected an error reading %q", test)
+		}
+	}
+	if _, err := ReadXML(strings.NewReader("<cdm><body></body></cdm>")); !errors.Is(err, ErrInvalidCDM) {
+		t.Errorf("expected ErrInvalidCDM, got %v", err)
+	}
+}
+
+func TestRescreen(t *testing.T) {
+	iss := [2]string{testsat.ISSLine1, testsat.ISSLine2}
+	crossing := [2]string{"1 99911U 98067A   20140.34419374 -.00000374  00000-0  13653-5 0  9990", "2 99911  53.0000 131.2277 0001338 330.3524 173.1622 15.49372617227549"}
+
+	c := CDM{Relative: RelativeMetadata{TCA: time.Date(2020, 5, 19, 9, 11, 0, 0, time.UTC)}}
+	conjunction, err := c.Rescreen(iss, crossing, satellite.GravityWGS72, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := conjunction.TCA.Sub(c.Relative.TCA); d < 0 || d > time.Minute {
+		t.Errorf("unexpected TCA %v", conjunction.TCA)
+	}
+	if conjunction.MissDistance > 3 {
+		t.Errorf("unexpected miss distance %f km", conjunction.MissDistance)
+	}
+}
@@ -0,0 +1,109 @@
+package cdm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Reads a CDM in keyword value notation. Units in square brackets are kept only for keywords without a typed field.
+func ReadKVN(r io.Reader) (CDM, error) {
+	var c CDM
+	object := -1
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "COMMENT") {
+			comment := strings.TrimSpace(strings.TrimPrefix(line, "COMMENT"))
+			switch {
+			case object >= 0:
+				c.Objects[object].Comments = append(c.Objects[object].Comments, comment)
+			case c.Version == "" || c.MessageID == "":
+				c.Comments = append(c.Comments, comment)
+			default:
+				c.Relative.Comments = append(c.Relative.Comments, comment)
+			}
+			continue
+		}
+
+		equals := strings.Index(line, "=")
+		if equals < 0 {
+			return CDM{}, fmt.Errorf("%w: line %d: missing '='", ErrInvalidCDM, lineNumber)
+		}
+		key := strings.TrimSpace(line[:equals])
+		value := strings.TrimSpace(line[equals+1:])
+		unit := ""
+		if i := strings.LastIndex(value, "["); i >= 0 && strings.HasSuffix(value, "]") {
+			unit = value[i+1 : len(value)-1]
+			value = strings.TrimSpace(value[:i])
+		}
+
+		if key == "OBJECT" {
+			object++
+			if object > 1 {
+				return CDM{}, fmt.Errorf("%w: line %d: more than two objects", ErrInvalidCDM, lineNumber)
+			}
+		}
+		if err := c.set(object, key, value, unit); err != nil {
+			return CDM{}, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return CDM{}, err
+	}
+	if err := c.validate(); err != nil {
+		return CDM{}, err
+	}
+	return c, nil
+}
+
+// Writes a CDM in keyword value notation
+func WriteKVN(w io.Writer, c CDM) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	comments := func(lines []string) {
+		for _, line := range lines {
+			fmt.Fprintf(bw, "COMMENT %s\n", line)
+		}
+	}
+	fields := func(kvs []keyValue) {
+		for _, kv := range kvs {
+			if kv.unit != "" {
+				fmt.Fprintf(bw, "%-36s = %s [%s]\n", kv.key, kv.value, kv.unit)
+			} else {
+				fmt.Fprintf(bw, "%-36s = %s\n", kv.key, kv.value)
+			}
+		}
+	}
+
+	header := c.headerFields()
+	fields(header[:1])
+	comments(c.Comments)
+	fields(header[1:])
+
+	comments(c.Relative.Comments)
+	encounter, state, screening := c.Relative.relativeFields()
+	fields(encounter)
+	fields(state)
+	fields(screening)
+
+	for _, o := range c.Objects {
+		metadata, odParameters, additionalParameters, state, covariance := o.objectFields()
+		fields(metadata[:1])
+		comments(o.Comments)
+		fields(metadata[1:])
+		fields(odParameters)
+		fields(additionalParameters)
+		fields(state)
+		fields(covariance)
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,45 @@
+package cdm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+)
+
+var ErrNoCloseApproach = errors.New("no closest approach within the window")
+
+// Re-screens the event by propagating TLEs of object 1 and object 2 with sgp4 over the window either side of the
+// message TCA. Returns the closest approach nearest the message TCA, in km and km/s unlike the message.
+func (c CDM) Rescreen(tle1, tle2 [2]string, gravity satellite.Gravity, window time.Duration) (satellite.Conjunction, error) {
+	primary, err := satellite.TLEToSat(tle1[0], tle1[1], gravity)
+	if err != nil {
+		return satellite.Conjunction{}, fmt.Errorf("object 1: %w", err)
+	}
+	secondary, err := satellite.TLEToSat(tle2[0], tle2[1], gravity)
+	if err != nil {
+		return satellite.Conjunction{}, fmt.Errorf("object 2: %w", err)
+	}
+
+	tca := c.Relative.TCA
+	conjunctions, err := satellite.ScreenConjunctions(primary, []satellite.Satellite{secondary}, satellite.ScreeningSettings{
+		Start:     tca.Add(-window),
+		Stop:      tca.Add(window),
+		Threshold: math.Inf(1),
+	})
+	if err != nil {
+		return satellite.Conjunction{}, err
+	}
+	if len(conjunctions) == 0 {
+		return satellite.Conjunction{}, fmt.Errorf("%w: %v around %v", ErrNoCloseApproach, window, tca)
+	}
+	nearest := conjunctions[0]
+	for _, conjunction := range conjunctions[1:] {
+		if conjunction.TCA.Sub(tca).Abs() < nearest.TCA.Sub(tca).Abs() {
+			nearest = conjunction
+		}
+	}
+	return nearest, nil
+}
@@ -0,0 +1,169 @@
+package cdm
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Reads a CDM in the CCSDS NDM/XML schema. Units attributes are kept only for keywords without a typed field.
+func ReadXML(r io.Reader) (CDM, error) {
+	var c CDM
+	decoder := xml.NewDecoder(r)
+	object := -1
+	section := ""
+	unit := ""
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return CDM{}, fmt.Errorf("%w: %v", ErrInvalidCDM, err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			text.Reset()
+			unit = ""
+			for _, attr := range t.Attr {
+				if attr.Name.Local == "units" {
+					unit = attr.Value
+				}
+			}
+			switch t.Name.Local {
+			case "cdm":
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "version" {
+						c.Version = attr.Value
+					}
+				}
+			case "header", "relativeMetadataData":
+				section = t.Name.Local
+			case "segment":
+				section = "segment"
+				object++
+				if object > 1 {
+					return CDM{}, fmt.Errorf("%w: more than two segments", ErrInvalidCDM)
+				}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			key := t.Name.Local
+			value := strings.TrimSpace(text.String())
+			text.Reset()
+			switch key {
+			case "cdm", "header", "body", "relativeMetadataData", "relativeStateVector", "segment", "metadata",
+				"data", "odParameters", "additionalParameters", "stateVector", "covarianceMatrix":
+				continue
+			case "COMMENT":
+				switch section {
+				case "header":
+					c.Comments = append(c.Comments, value)
+				case "relativeMetadataData":
+					c.Relative.Comments = append(c.Relative.Comments, value)
+				case "segment":
+					c.Objects[object].Comments = append(c.Objects[object].Comments, value)
+				}
+				continue
+			}
+			target := -1
+			if section == "segment" {
+				target = object
+			}
+			if err := c.set(target, key, value, unit); err != nil {
+				return CDM{}, err
+			}
+		}
+	}
+	if err := c.validate(); err != nil {
+		return CDM{}, err
+	}
+	return c, nil
+}
+
+// Writes a CDM in the CCSDS NDM/XML schema
+func WriteXML(w io.Writer, c CDM) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	indent := func(depth int) {
+		bw.WriteString(strings.Repeat("  ", depth))
+	}
+	element := func(depth int, key, value, unit string) {
+		indent(depth)
+		if unit != "" {
+			fmt.Fprintf(bw, "<%s units=\"%s\">", key, unit)
+		} else {
+			fmt.Fprintf(bw, "<%s>", key)
+		}
+		xml.EscapeText(bw, []byte(value))
+		fmt.Fprintf(bw, "</%s>\n", key)
+	}
+	fields := func(depth int, kvs []keyValue) {
+		for _, kv := range kvs {
+			element(depth, kv.key, kv.value, kv.unit)
+		}
+	}
+	comments := func(depth int, lines []string) {
+		for _, line := range lines {
+			element(depth, "COMMENT", line, "")
+		}
+	}
+	begin := func(depth int, name string) {
+		indent(depth)
+		fmt.Fprintf(bw, "<%s>\n", name)
+	}
+	end := func(depth int, name string) {
+		indent(depth)
+		fmt.Fprintf(bw, "</%s>\n", name)
+	}
+	group := func(depth int, name string, kvs []keyValue) {
+		if len(kvs) == 0 {
+			return
+		}
+		begin(depth, name)
+		fields(depth+1, kvs)
+		end(depth, name)
+	}
+
+	header := c.headerFields()
+	bw.WriteString(xml.Header)
+	fmt.Fprintf(bw, "<cdm id=\"CCSDS_CDM_VERS\" version=\"%s\">\n", header[0].value)
+	begin(1, "header")
+	comments(2, c.Comments)
+	fields(2, header[1:])
+	end(1, "header")
+
+	begin(1, "body")
+	begin(2, "relativeMetadataData")
+	comments(3, c.Relative.Comments)
+	encounter, state, screening := c.Relative.relativeFields()
+	fields(3, encounter)
+	group(3, "relativeStateVector", state)
+	fields(3, screening)
+	end(2, "relativeMetadataData")
+
+	for _, o := range c.Objects {
+		metadata, odParameters, additionalParameters, state, covariance := o.objectFields()
+		begin(2, "segment")
+		begin(3, "metadata")
+		comments(4, o.Comments)
+		fields(4, metadata)
+		end(3, "metadata")
+		begin(3, "data")
+		group(4, "odParameters", odParameters)
+		group(4, "additionalParameters", additionalParameters)
+		group(4, "stateVector", state)
+		group(4, "covarianceMatrix", covariance)
+		end(3, "data")
+		end(2, "segment")
+	}
+	end(1, "body")
+	bw.WriteString("</cdm>\n")
+	return bw.Flush()
+}
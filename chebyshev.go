@@ -0,0 +1,313 @@
+package satellite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+var ErrChebyshevTolerance = errors.New("chebyshev fit cannot reach the tolerance")
+var ErrInvalidChebyshev = errors.New("invalid chebyshev ephemeris")
+
+// Magic bytes and version of the binary encoding of a ChebyshevEphemeris
+const (
+	chebyshevMagic   = "CHEB"
+	chebyshevVersion = 1
+)
+
+// ChebyshevSettings configures the fit of a ChebyshevEphemeris
+type ChebyshevSettings struct {
+	Start time.Time
+	Stop  time.Time
+	// Maximum position error in km at the check points, defaults to 1e-3 (1 m)
+	Tolerance float64
+	// Degree of the polynomials, defaults to 12
+	Degree int
+	// Longest segment, defaults to 30 minutes. Segments are halved until they meet the tolerance.
+	MaxSpan time.Duration
+	// Shortest segment before the fit fails, defaults to one second
+	MinSpan time.Duration
+}
+
+// ChebyshevSegment holds the coefficients of the position over a span of seconds from the ephemeris epoch
+type ChebyshevSegment struct {
+	Start float64   `json:"start"`
+	End   float64   `json:"end"`
+	X     []float64 `json:"x"`
+	Y     []float64 `json:"y"`
+	Z     []float64 `json:"z"`
+}
+
+// ChebyshevEphemeris is a piecewise Chebyshev approximation of a propagator's positions (km), it implements Propagator.
+// Velocities are the derivative of the position polynomials.
+// It encodes to JSON and, more compactly, to binary with MarshalBinary.
+type ChebyshevEphemeris struct {
+	Epoch     time.Time          `json:"epoch"`
+	Degree    int                `json:"degree"`
+	Tolerance float64            `json:"tolerance"`
+	Segments  []ChebyshevSegment `json:"segments"`
+}
+
+// Fits piecewise Chebyshev polynomials to the positions of a propagator.
+// Each segment is sampled at the Chebyshev nodes and accepted once the position error at 4(degree+1)+1 evenly spaced
+// points, including the segment ends, is within the tolerance, otherwise it is halved. The error is only checked at
+// those points, so between them it is not guaranteed to be within the tolerance.
+func FitChebyshev(p Propagator, settings ChebyshevSettings) (ChebyshevEphemeris, error) {
+	if !settings.Stop.After(settings.Start) {
+		return ChebyshevEphemeris{}, fmt.Errorf("%w: %v to %v", ErrInvalidSampling, settings.Start, settings.Stop)
+	}
+	if settings.Tolerance == 0 {
+		settings.Tolerance = 1e-3
+	}
+	if settings.Degree == 0 {
+		settings.Degree = 12
+	}
+	if settings.MaxSpan == 0 {
+		settings.MaxSpan = 30 * time.Minute
+	}
+	if settings.MinSpan == 0 {
+		settings.MinSpan = time.Second
+	}
+	if settings.Tolerance < 0 || settings.Degree < 1 || settings.Degree > math.MaxUint16 || settings.MaxSpan < settings.MinSpan {
+		return ChebyshevEphemeris{}, fmt.Errorf("%w: tolerance %f degree %d span %v to %v", ErrInvalidChebyshev,
+			settings.Tolerance, settings.Degree, settings.MinSpan, settings.MaxSpan)
+	}
+
+	ephemeris := ChebyshevEphemeris{Epoch: settings.Start, Degree: settings.Degree, Tolerance: settings.Tolerance}
+	total := settings.Stop.Sub(settings.Start).Seconds()
+	maxSpan := settings.MaxSpan.Seconds()
+	for start := 0.0; start < total; start += maxSpan {
+		end := math.Min(start+maxSpan, total)
+		segments, err := ephemeris.fit(p, start, end, settings)
+		if err != nil {
+			return ChebyshevEphemeris{}, err
+		}
+		ephemeris.Segments = append(ephemeris.Segments, segments...)
+	}
+	return ephemeris, nil
+}
+
+// fit returns the segments covering start to end (s from the epoch), halving the span until the fit is within the
+// tolerance
+func (c ChebyshevEphemeris) fit(p Propagator, start, end float64, settings ChebyshevSettings) ([]ChebyshevSegment, error) {
+	n := settings.Degree + 1
+	segment := ChebyshevSegment{Start: start, End: end, X: make([]float64, n), Y: make([]float64, n), Z: make([]float64, n)}
+	for k := 0; k < n; k++ {
+		node := math.Cos(math.Pi * (float64(k) + 0.5) / float64(n))
+		pos, _, err := p.PositionVelocity(c.time(segment.fromUnit(node)))
+		if err != nil {
+			return nil, fmt.Errorf("position at %v: %w", c.time(segment.fromUnit(node)), err)
+		}
+		for j := 0; j < n; j++ {
+			weight := 2 / float64(n) * math.Cos(math.Pi*float64(j)*(float64(k)+0.5)/float64(n))
+			segment.X[j] += weight * pos.X
+			segment.Y[j] += weight * pos.Y
+			segment.Z[j] += weight * pos.Z
+		}
+	}
+	segment.X[0] /= 2
+	segment.Y[0] /= 2
+	segment.Z[0] /= 2
+
+	checks := 4 * n
+	maxError := 0.0
+	for i := 0; i <= checks; i++ {
+		offset := start + (end-start)*float64(i)/float64(checks)
+		want, _, err := p.PositionVelocity(c.time(offset))
+		if err != nil {
+			return nil, fmt.Errorf("position at %v: %w", c.time(offset), err)
+		}
+		got, _ := segment.evaluate(offset)
+		maxError = math.Max(maxError, got.Sub(want).Norm())
+	}
+	if maxError <= settings.Tolerance {
+		return []ChebyshevSegment{segment}, nil
+	}
+
+	if (end-start)/2 < settings.MinSpan.Seconds() {
+		return nil, fmt.Errorf("%w: error %f km over %f s at %v", ErrChebyshevTolerance, maxError, end-start, c.time(start))
+	}
+	middle := (start + end) / 2
+	first, err := c.fit(p, start, middle, settings)
+	if err != nil {
+		return nil, err
+	}
+	second, err := c.fit(p, middle, end, settings)
+	if err != nil {
+		return nil, err
+	}
+	return append(first, second...), nil
+}
+
+// time converts seconds from the epoch to a time
+func (c ChebyshevEphemeris) time(offset float64) time.Time {
+	return c.Epoch.Add(time.Duration(math.Round(offset * float64(time.Second))))
+}
+
+// fromUnit maps -1 to 1 onto the segment span
+func (s ChebyshevSegment) fromUnit(x float64) float64 {
+	return s.Start + (x+1)*(s.End-s.Start)/2
+}
+
+// evaluate returns the position (km) and velocity (km/s) at seconds from the epoch with Clenshaw's recurrence
+func (s ChebyshevSegment) evaluate(offset float64) (Vector3, Vector3) {
+	scale := 2 / (s.End - s.Start)
+	x := (offset-s.Start)*scale - 1
+	var b1, b2, d1, d2 Vector3
+	for j := len(s.X) - 1; j >= 1; j-- {
+		c := Vector3{X: s.X[j], Y: s.Y[j], Z: s.Z[j]}
+		b0 := b1.Scale(2 * x).Sub(b2).Add(c)
+		// derivative of the recurrence with respect to x
+		d0 := d1.Scale(2 * x).Sub(d2).Add(b1.Scale(2))
+		b2, b1 = b1, b0
+		d2, d1 = d1, d0
+	}
+	c0 := Vector3{X: s.X[0], Y: s.Y[0], Z: s.Z[0]}
+	pos := b1.Scale(x).Sub(b2).Add(c0)
+	dpos := d1.Scale(x).Sub(d2).Add(b1)
+	return pos, dpos.Scale(scale)
+}
+
+// Returns the first time covered by the ephemeris
+func (c ChebyshevEphemeris) Start() time.Time {
+	if len(c.Segments) == 0 {
+		return c.Epoch
+	}
+	return c.time(c.Segments[0].Start)
+}
+
+// Returns the last time covered by the ephemeris
+func (c ChebyshevEphemeris) Stop() time.Time {
+	if len(c.Segments) == 0 {
+		return c.Epoch
+	}
+	return c.time(c.Segments[len(c.Segments)-1].End)
+}
+
+// Evaluates the position and velocity at the given time
+func (c ChebyshevEphemeris) PositionVelocity(t time.Time) (position, velocity Vector3, err error) {
+	if len(c.Segments) == 0 {
+		return position, velocity, fmt.Errorf("%w: no segments", ErrInvalidChebyshev)
+	}
+	offset := t.Sub(c.Epoch).Seconds()
+	if offset < c.Segments[0].Start || offset > c.Segments[len(c.Segments)-1].End {
+		return position, velocity, fmt.Errorf("%w: %v is not within %v to %v", ErrOutsideEphemeris, t, c.Start(), c.Stop())
+	}
+	i := sort.Search(len(c.Segments), func(i int) bool { return c.Segments[i].End >= offset })
+	position, velocity = c.Segments[i].evaluate(offset)
+	return position, velocity, nil
+}
+
+// validate checks the degree fits the binary encoding and the segments are ordered, contiguous and of that degree
+func (c ChebyshevEphemeris) validate() error {
+	if c.Degree < 0 || c.Degree > math.MaxUint16 {
+		return fmt.Errorf("%w: degree %d", ErrInvalidChebyshev, c.Degree)
+	}
+	for i, s := range c.Segments {
+		n := c.Degree + 1
+		if len(s.X) != n || len(s.Y) != n || len(s.Z) != n {
+			return fmt.Errorf("%w: segment %d does not have %d coefficients", ErrInvalidChebyshev, i, n)
+		}
+		if !(s.End > s.Start) || (i > 0 && s.Start != c.Segments[i-1].End) {
+			return fmt.Errorf("%w: segment %d spans %f to %f", ErrInvalidChebyshev, i, s.Start, s.End)
+		}
+	}
+	return nil
+}
+
+// Decodes an ephemeris from JSON, checking its segments
+func (c *ChebyshevEphemeris) UnmarshalJSON(data []byte) error {
+	// the alias drops this method so the default decoding applies
+	type plain ChebyshevEphemeris
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidChebyshev, err)
+	}
+	if err := ChebyshevEphemeris(decoded).validate(); err != nil {
+		return err
+	}
+	*c = ChebyshevEphemeris(decoded)
+	return nil
+}
+
+// chebyshevHeader is the fixed size start of the binary encoding
+type chebyshevHeader struct {
+	Magic     [4]byte
+	Version   uint8
+	Epoch     int64
+	Degree    uint16
+	Tolerance float64
+	Segments  uint32
+}
+
+// Encodes the ephemeris as little endian binary: a header followed by the span and coefficients of each segment
+func (c ChebyshevEphemeris) MarshalBinary() ([]byte, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	header := chebyshevHeader{
+		Version:   chebyshevVersion,
+		Epoch:     c.Epoch.UnixNano(),
+		Degree:    uint16(c.Degree),
+		Tolerance: c.Tolerance,
+		Segments:  uint32(len(c.Segments)),
+	}
+	copy(header.Magic[:], chebyshevMagic)
+	if err := binary.Write(&buf, binary.LittleEndian, header); err != nil {
+		return nil, err
+	}
+	for _, s := range c.Segments {
+		values := append([]float64{s.Start, s.End}, s.X...)
+		values = append(append(values, s.Y...), s.Z...)
+		if err := binary.Write(&buf, binary.LittleEndian, values); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// Decodes an ephemeris written by MarshalBinary
+func (c *ChebyshevEphemeris) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	var header chebyshevHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidChebyshev, err)
+	}
+	if string(header.Magic[:]) != chebyshevMagic || header.Version != chebyshevVersion {
+		return fmt.Errorf("%w: unknown format %q version %d", ErrInvalidChebyshev, header.Magic[:], header.Version)
+	}
+	n := int(header.Degree) + 1
+	if want := int64(header.Segments) * int64(2+3*n) * 8; int64(r.Len()) != want {
+		return fmt.Errorf("%w: %d bytes of segments, want %d", ErrInvalidChebyshev, r.Len(), want)
+	}
+	decoded := ChebyshevEphemeris{
+		Epoch:     time.Unix(0, header.Epoch).UTC(),
+		Degree:    int(header.Degree),
+		Tolerance: header.Tolerance,
+		Segments:  make([]ChebyshevSegment, header.Segments),
+	}
+	values := make([]float64, 2+3*n)
+	for i := range decoded.Segments {
+		if err := binary.Read(r, binary.LittleEndian, values); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidChebyshev, err)
+		}
+		decoded.Segments[i] = ChebyshevSegment{
+			Start: values[0],
+			End:   values[1],
+			X:     append([]float64(nil), values[2:2+n]...),
+			Y:     append([]float64(nil), values[2+n:2+2*n]...),
+			Z:     append([]float64(nil), values[2+2*n:]...),
+		}
+	}
+	if err := decoded.validate(); err != nil {
+		return err
+	}
+	*c = decoded
+	return nil
+}
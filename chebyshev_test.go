@@ -0,0 +1,180 @@
+package satellite
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite/internal/testsat"
+)
+
+var _ Propagator = ChebyshevEphemeris{}
+
+var chebyshevStart = time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+
+func TestChebyshevErrorBound(t *testing.T) {
+	sat := testsat.ISS(t, TLEToSat, GravityWGS72)
+	// Propagate quantizes time through the Julian date to about 0.3 m along track, tighter fits chase that noise
+	for _, tolerance := range []float64{1e-2, 1e-3} {
+		ephemeris, err := FitChebyshev(sat, ChebyshevSettings{
+			Start:     chebyshevStart,
+			Stop:      chebyshevStart.Add(6 * time.Hour),
+			Tolerance: tolerance,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !ephemeris.Start().Equal(chebyshevStart) || !ephemeris.Stop().Equal(chebyshevStart.Add(6*time.Hour)) {
+			t.Errorf("unexpected coverage %v to %v", ephemeris.Start(), ephemeris.Stop())
+		}
+
+		// every 7 seconds, off the check points of the fit
+		maxError, maxVelocityError := 0.0, 0.0
+		for at := chebyshevStart; !at.After(ephemeris.Stop()); at = at.Add(7 * time.Second) {
+			got, gotVel, err := ephemeris.PositionVelocity(at)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want, wantVel, err := Propagate(sat, at)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if e := got.Sub(want).Norm(); e > maxError {
+				maxError = e
+			}
+			if e := gotVel.Sub(wantVel).Norm(); e > maxVelocityError {
+				maxVelocityError = e
+			}
+		}
+		if maxError > tolerance {
+			t.Errorf("tolerance %g km: maximum error %g km across %d segments", tolerance, maxError, len(ephemeris.Segments))
+		}
+		if maxVelocityError > tolerance {
+			t.Errorf("tolerance %g km: maximum velocity error %g km/s", tolerance, maxVelocityError)
+		}
+	}
+}
+
+func TestChebyshevEncoding(t *testing.T) {
+	sat := testsat.ISS(t, TLEToSat, GravityWGS72)
+	ephemeris, err := FitChebyshev(sat, ChebyshevSettings{Start: chebyshevStart, Stop: chebyshevStart.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	at := chebyshevStart.Add(47*time.Minute + 13*time.Second)
+	want, wantVel, err := ephemeris.PositionVelocity(at)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	binaryData, err := ephemeris.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	jsonData, err := json.Marshal(ephemeris)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(binaryData) >= len(jsonData) {
+		t.Errorf("expected binary (%d bytes) to be smaller than JSON (%d bytes)", len(binaryData), len(jsonData))
+	}
+
+	var fromBinary, fromJSON ChebyshevEphemeris
+	if err := fromBinary.UnmarshalBinary(binaryData); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal(jsonData, &fromJSON); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, decoded := range []ChebyshevEphemeris{fromBinary, fromJSON} {
+		got, gotVel, err := decoded.PositionVelocity(at)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != want || gotVel != wantVel {
+			t.Errorf("decoded ephemeris gives %v %v, want %v %v", got, gotVel, want, wantVel)
+		}
+	}
+
+	var corrupt ChebyshevEphemeris
+	if err := corrupt.UnmarshalBinary(binaryData[:len(binaryData)-8]); !errors.Is(err, ErrInvalidChebyshev) {
+		t.Errorf("expected ErrInvalidChebyshev for truncated data, got %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"degree":2,"segments":[{"start":0,"end":1,"x":[1],"y":[1],"z":[1]}]}`), &corrupt); !errors.Is(err, ErrInvalidChebyshev) {
+		t.Errorf("expected ErrInvalidChebyshev for short coefficients, got %v", err)
+	}
+	for _, degree := range []int{-1, math.MaxUint16 + 1} {
+		data := fmt.Sprintf(`{"degree":%d,"segments":[{"start":0,"end":1,"x":[],"y":[],"z":[]}]}`, degree)
+		if err := json.Unmarshal([]byte(data), &corrupt); !errors.Is(err, ErrInvalidChebyshev) {
+			t.Errorf("expected ErrInvalidChebyshev for degree %d, got %v", degree, err)
+		}
+		invalid := ChebyshevEphemeris{Epoch: chebyshevStart, Degree: degree}
+		if _, err := invalid.MarshalBinary(); !errors.Is(err, ErrInvalidChebyshev) {
+			t.Errorf("expected ErrInvalidChebyshev encoding degree %d, got %v", degree, err)
+		}
+	}
+}
+
+func TestChebyshevInvalid(t *testing.T) {
+	sat := testsat.ISS(t, TLEToSat, GravityWGS72)
+	ephemeris, err := FitChebyshev(sat, ChebyshevSettings{Start: chebyshevStart, Stop: chebyshevStart.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := ephemeris.PositionVelocity(chebyshevStart.Add(-time.Second)); !errors.Is(err, ErrOutsideEphemeris) {
+		t.Errorf("expected ErrOutsideEphemeris, got %v", err)
+	}
+	if _, err := FitChebyshev(sat, ChebyshevSettings{Start: chebyshevStart, Stop: chebyshevStart}); !errors.Is(err, ErrInvalidSampling) {
+		t.Errorf("expected ErrInvalidSampling, got %v", err)
+	}
+	if _, err := FitChebyshev(sat, ChebyshevSettings{Start: chebyshevStart, Stop: chebyshevStart.Add(time.Hour), Degree: math.MaxUint16 + 1}); !errors.Is(err, ErrInvalidChebyshev) {
+		t.Errorf("expected ErrInvalidChebyshev, got %v", err)
+	}
+	_, err = FitChebyshev(sat, ChebyshevSettings{
+		Start:     chebyshevStart,
+		Stop:      chebyshevStart.Add(time.Hour),
+		Degree:    2,
+		Tolerance: 1e-9,
+		MinSpan:   time.Minute,
+	})
+	if !errors.Is(err, ErrChebyshevTolerance) {
+		t.Errorf("expected ErrChebyshevTolerance, got %v", err)
+	}
+}
+
+func BenchmarkPropagate(b *testing.B) {
+	sat := testsat.ISS(b, TLEToSat, GravityWGS72)
+	for i := 0; i < b.N; i++ {
+		at := chebyshevStart.Add(time.Duration(i%86400) * time.Second)
+		if _, _, err := Propagate(sat, at); err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkChebyshevPositionVelocity(b *testing.B) {
+	sat := testsat.ISS(b, TLEToSat, GravityWGS72)
+	ephemeris, err := FitChebyshev(sat, ChebyshevSettings{Start: chebyshevStart, Stop: chebyshevStart.Add(24 * time.Hour)})
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		at := chebyshevStart.Add(time.Duration(i%86400) * time.Second)
+		if _, _, err := ephemeris.PositionVelocity(at); err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkFitChebyshev(b *testing.B) {
+	sat := testsat.ISS(b, TLEToSat, GravityWGS72)
+	for i := 0; i < b.N; i++ {
+		if _, err := FitChebyshev(sat, ChebyshevSettings{Start: chebyshevStart, Stop: chebyshevStart.Add(24 * time.Hour)}); err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
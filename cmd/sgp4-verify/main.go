@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+
+	"github.com/infostellarinc/go-satellite"
+	"github.com/infostellarinc/go-satellite/internal/sgp4ver"
+)
+
+func main() {
+	tlePath := flag.String("tle", "testdata/SGP4-VER.TLE", "Verification element sets")
+	outPath := flag.String("out", "testdata/tcppver.out", "Expected output of the reference program")
+	tolerance := flag.Float64("tol", 1e-4, "Allowed difference per component in km and km/s")
+	gravity := flag.String("grav", string(satellite.GravityWGS72), "Gravity model")
+
+	flag.Parse()
+
+	tleFile, err := os.Open(*tlePath)
+	if err != nil {
+		log.Fatalf("Error opening file: %v", err)
+	}
+	defer tleFile.Close()
+	cases, err := sgp4ver.ReadCases(tleFile)
+	if err != nil {
+		log.Fatalf("Error reading %s: %v", *tlePath, err)
+	}
+
+	outFile, err := os.Open(*outPath)
+	if err != nil {
+		log.Fatalf("Error opening file: %v", err)
+	}
+	defer outFile.Close()
+	output, err := sgp4ver.ReadOutput(outFile)
+	if err != nil {
+		log.Fatalf("Error reading %s: %v", *outPath, err)
+	}
+
+	drifted := 0
+	for _, c := range cases {
+		lines := verify(c, output[c.CatalogNumber], satellite.Gravity(*gravity), *tolerance)
+		if len(lines) == 0 {
+			fmt.Fprintf(os.Stdout, "%05d: ok\n", c.CatalogNumber)
+			continue
+		}
+		drifted++
+		fmt.Fprintf(os.Stdout, "%05d: DRIFT\n", c.CatalogNumber)
+		for _, line := range lines {
+			fmt.Fprintf(os.Stdout, "\t%s\n", line)
+		}
+	}
+
+	fmt.Fprintf(os.Stdout, "Cases:Drifted: %v:%v\n", len(cases), drifted)
+	if drifted > 0 {
+		os.Exit(1)
+	}
+}
+
+// verify propagates a case and returns a report line for every difference from the expected output
+func verify(c sgp4ver.Case, expected []sgp4ver.State, gravity satellite.Gravity, tolerance float64) []string {
+	var report []string
+	var wantErr error
+	if c.ErrorCode != 0 {
+		wantErr = sgp4ver.ErrorCodes[c.ErrorCode]
+		if wantErr == nil {
+			return []string{fmt.Sprintf("unknown error code %d", c.ErrorCode)}
+		}
+	}
+
+	states := make(map[float64]sgp4ver.State)
+	for _, state := range expected {
+		states[state.Tsince] = state
+	}
+
+	sat, err := satellite.TLEToSat(c.Line1, c.Line2, gravity)
+	if err != nil {
+		if wantErr != nil && errors.Is(err, wantErr) {
+			return nil
+		}
+		return []string{fmt.Sprintf("init: got error %v", err)}
+	}
+
+	for _, tsince := range c.Times() {
+		pos, vel, err := satellite.PropagateMinutes(sat, tsince)
+		if err != nil {
+			if wantErr != nil && errors.Is(err, wantErr) {
+				return report
+			}
+			return append(report, fmt.Sprintf("t %14.8f: got error %v", tsince, err))
+		}
+		want, ok := states[tsince]
+		if !ok {
+			continue
+		}
+		delete(states, tsince)
+		got := [6]float64{pos.X, pos.Y, pos.Z, vel.X, vel.Y, vel.Z}
+		exp := [6]float64{want.Position[0], want.Position[1], want.Position[2], want.Velocity[0], want.Velocity[1], want.Velocity[2]}
+		for i := range got {
+			if diff := got[i] - exp[i]; math.IsNaN(diff) || math.Abs(diff) > tolerance {
+				report = append(report, fmt.Sprintf("t %14.8f: got %16.8f %16.8f %16.8f %12.9f %12.9f %12.9f", tsince, got[0], got[1], got[2], got[3], got[4], got[5]))
+				report = append(report, fmt.Sprintf("t %14.8f: exp %16.8f %16.8f %16.8f %12.9f %12.9f %12.9f", tsince, exp[0], exp[1], exp[2], exp[3], exp[4], exp[5]))
+				break
+			}
+		}
+	}
+
+	if wantErr != nil {
+		report = append(report, fmt.Sprintf("expected error %v, propagated cleanly", wantErr))
+	}
+	for tsince := range states {
+		report = append(report, fmt.Sprintf("t %14.8f: in expected output but not propagated", tsince))
+	}
+	return report
+}
@@ -22,9 +22,9 @@ const (
 func main() {
 	start := time.Now()
 	inputFile := flag.String("file", "", "Input file to read (required)")
-	altitude := flag.Float64("alt", 0.0, "Altitude (required)")
-	longitude := flag.Float64("lon", 0.0, "Longitude (required)")
-	latitude := flag.Float64("lat", 0.0, "Latitude (required)")
+	altitude := flag.Float64("alt", 0.0, "Altitude in km (required)")
+	longitude := flag.Float64("lon", 0.0, "Longitude in degrees (required)")
+	latitude := flag.Float64("lat", 0.0, "Latitude in degrees (required)")
 
 	// Parse flags
 	flag.Parse()
@@ -37,9 +37,9 @@ func main() {
 	}
 
 	// Print the coordinates
-	fmt.Printf("Altitude: %.2f\n", *altitude)
-	fmt.Printf("Longitude: %.2f\n", *longitude)
-	fmt.Printf("Latitude: %.2f\n", *latitude)
+	fmt.Printf("Altitude: %.2f km\n", *altitude)
+	fmt.Printf("Longitude: %.2f deg\n", *longitude)
+	fmt.Printf("Latitude: %.2f deg\n", *latitude)
 	fmt.Printf("File: %s\n", *inputFile)
 
 	// Open the input file
@@ -49,11 +49,7 @@ func main() {
 	}
 	defer file.Close()
 
-	var coordinates = satellite.Coordinates{
-		Altitude:  *altitude,
-		Longitude: *longitude,
-		Latitude:  *latitude,
-	}
+	coordinates := satellite.Geodetic(*latitude, *longitude, *altitude)
 
 	// Create a scanner to read the file
 	scanner := bufio.NewScanner(file)
@@ -87,21 +83,22 @@ func main() {
 		} else {
 			tlesParsed++
 			epoch := sat.Tle.EpochTime()
-			pos, _, err := satellite.Propagate(sat, time.Now())
+			now := time.Now()
+			pos, _, err := satellite.Propagate(sat, now)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "could not propagate satellite: %v\n", err)
 				state = StateNone
 				continue
 			}
-			lookAngles := satellite.ECIToLookAngles(pos, coordinates, satellite.JDayTime(time.Now()), sat.GravityConst)
+			lookAngles := satellite.ECIToLookAngles(pos, coordinates, satellite.JDayTime(now), sat.GravityConst)
 
-			if lookAngles.Elevation < 0 {
+			if lookAngles.Elevation.Radians() < 0 {
 				fmt.Fprintf(os.Stdout, "%v:\n\tepoch %v\n\tbelow horizon\n", sat.Tle.CatalogNumber, epoch.Format(time.RFC3339Nano))
 				state = StateNone
 				belowHorizon++
 				continue
 			}
-			fmt.Fprintf(os.Stdout, "%v:\n\tepoch %v\n\tazimuth: %0.2f\n\televation: %0.2f\n", sat.Tle.CatalogNumber, epoch.Format(time.RFC3339Nano), lookAngles.Azimuth, lookAngles.Elevation)
+			fmt.Fprintf(os.Stdout, "%v:\n\tepoch %v\n\tazimuth: %0.2f deg\n\televation: %0.2f deg\n", sat.Tle.CatalogNumber, epoch.Format(time.RFC3339Nano), lookAngles.Azimuth.Degrees(), lookAngles.Elevation.Degrees())
 			state = StateNone
 			aboveHorizon++
 		}
@@ -0,0 +1,189 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrInvalidCovariance = errors.New("covariance is not positive definite")
+var ErrInvalidHardBodyRadius = errors.New("hard body radius must be positive")
+var ErrZeroRelativeVelocity = errors.New("relative velocity is zero, the encounter plane is undefined")
+
+// Relative tolerance of the Foster integration
+const fosterTolerance = 1e-10
+
+// encounterPlane holds the miss vector and combined position covariance projected onto the plane normal to the
+// relative velocity, x along the miss vector
+type encounterPlane struct {
+	miss float64
+	// covariance entries xx, xy, yy
+	cxx, cxy, cyy float64
+}
+
+func newEncounterPlane(r1, v1, r2, v2 Vector3, cov1, cov2 Matrix3) (encounterPlane, error) {
+	relPos := r2.Sub(r1)
+	relVel := v2.Sub(v1)
+	speed := relVel.Norm()
+	if speed == 0 {
+		return encounterPlane{}, ErrZeroRelativeVelocity
+	}
+	z := relVel.Scale(1 / speed)
+	// miss vector perpendicular to the relative velocity, any in plane direction when it vanishes
+	perpendicular := relPos.Add(z.Scale(-relPos.Dot(z)))
+	miss := perpendicular.Norm()
+	var x Vector3
+	if miss > 0 {
+		x = perpendicular.Scale(1 / miss)
+	} else {
+		helper := Vector3{X: 1}
+		if math.Abs(z.X) > 0.9 {
+			helper = Vector3{Y: 1}
+		}
+		x = helper.Cross(z)
+		x = x.Unit()
+	}
+	y := z.Cross(x)
+
+	var combined Matrix3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			combined[i][j] = cov1[i][j] + cov2[i][j]
+		}
+	}
+	quadratic := func(a, b Vector3) float64 {
+		u := [3]float64{a.X, a.Y, a.Z}
+		w := [3]float64{b.X, b.Y, b.Z}
+		sum := 0.0
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				sum += u[i] * combined[i][j] * w[j]
+			}
+		}
+		return sum
+	}
+
+	plane := encounterPlane{
+		miss: miss,
+		cxx:  quadratic(x, x),
+		cxy:  0.5 * (quadratic(x, y) + quadratic(y, x)),
+		cyy:  quadratic(y, y),
+	}
+	if !(plane.cxx > 0) || !(plane.cxx*plane.cyy-plane.cxy*plane.cxy > 0) {
+		return encounterPlane{}, fmt.Errorf("%w: projected covariance [%g %g; %g %g]", ErrInvalidCovariance, plane.cxx, plane.cxy, plane.cxy, plane.cyy)
+	}
+	return plane, nil
+}
+
+// Calculates the 2D probability of collision by integrating the combined position uncertainty over the hard body
+// circle in the encounter plane. Positions (km), velocities (km/s), covariances (km^2) and the combined hard body
+// radius (km) must share one inertial frame.
+// Reference: Foster and Estes, A Parametric Analysis of Orbital Debris Collision Probability and Maneuver Rate for
+// Space Vehicles, NASA JSC-25898, 1992.
+func CollisionProbabilityFoster(r1, v1, r2, v2 Vector3, cov1, cov2 Matrix3, hardBodyRadius float64) (float64, error) {
+	if !(hardBodyRadius > 0) || math.IsInf(hardBodyRadius, 0) {
+		return 0, fmt.Errorf("%w: %f", ErrInvalidHardBodyRadius, hardBodyRadius)
+	}
+	plane, err := newEncounterPlane(r1, v1, r2, v2, cov1, cov2)
+	if err != nil {
+		return 0, err
+	}
+
+	det := plane.cxx*plane.cyy - plane.cxy*plane.cxy
+	// inverse covariance entries
+	a := plane.cyy / det
+	b := -plane.cxy / det
+	c := plane.cxx / det
+	sqrtHalfC := math.Sqrt(c / 2)
+
+	// the y integral over the chord at x is a difference of error functions, x = miss - R cos(phi) spans the circle
+	integrand := func(phi float64) float64 {
+		x := plane.miss - hardBodyRadius*math.Cos(phi)
+		h := hardBodyRadius * math.Sin(phi)
+		shift := b * x / c
+		chord := math.Erf(sqrtHalfC*(h+shift)) - math.Erf(sqrtHalfC*(-h+shift))
+		return math.Exp(-0.5*(a-b*b/c)*x*x) * chord * hardBodyRadius * math.Sin(phi)
+	}
+	integral := adaptiveSimpson(integrand, 0, math.Pi, fosterTolerance, 50)
+
+	// sqrt(pi/(2c)) from the y integral over 2 pi sqrt(det)
+	return integral * math.Sqrt(math.Pi/(2*c)) / (2 * math.Pi * math.Sqrt(det)), nil
+}
+
+// adaptiveSimpson integrates f over [a, b] to a relative tolerance
+func adaptiveSimpson(f func(float64) float64, a, b, tolerance float64, depth int) float64 {
+	fa, fm, fb := f(a), f((a+b)/2), f(b)
+	whole := (b - a) / 6 * (fa + 4*fm + fb)
+	return adaptiveSimpsonStep(f, a, b, fa, fm, fb, whole, tolerance*math.Abs(whole), depth)
+}
+
+func adaptiveSimpsonStep(f func(float64) float64, a, b, fa, fm, fb, whole, tolerance float64, depth int) float64 {
+	m := (a + b) / 2
+	lm, rm := f((a+m)/2), f((m+b)/2)
+	left := (m - a) / 6 * (fa + 4*lm + fm)
+	right := (b - m) / 6 * (fm + 4*rm + fb)
+	if depth <= 0 || math.Abs(left+right-whole) <= 15*tolerance {
+		return left + right + (left+right-whole)/15
+	}
+	return adaptiveSimpsonStep(f, a, m, fa, lm, fm, left, tolerance/2, depth-1) +
+		adaptiveSimpsonStep(f, m, b, fm, rm, fb, right, tolerance/2, depth-1)
+}
+
+// Calculates the 2D probability of collision with Chan's series, which transforms the covariance ellipse to an isotropic
+// one of equal area so the hard body circle becomes a circle with u = R^2/(sigma_u sigma_v). The series is summed until
+// the terms no longer change the result. Inputs are as for CollisionProbabilityFoster.
+// Reference: Chan, Spacecraft Collision Probability, The Aerospace Press, 2008, chapter 4.
+func CollisionProbabilityChan(r1, v1, r2, v2 Vector3, cov1, cov2 Matrix3, hardBodyRadius float64) (float64, error) {
+	if !(hardBodyRadius > 0) || math.IsInf(hardBodyRadius, 0) {
+		return 0, fmt.Errorf("%w: %f", ErrInvalidHardBodyRadius, hardBodyRadius)
+	}
+	plane, err := newEncounterPlane(r1, v1, r2, v2, cov1, cov2)
+	if err != nil {
+		return 0, err
+	}
+
+	// principal axes of the projected covariance
+	theta := 0.5 * math.Atan2(2*plane.cxy, plane.cxx-plane.cyy)
+	cosT, sinT := math.Cos(theta), math.Sin(theta)
+	varU := plane.cxx*cosT*cosT + 2*plane.cxy*sinT*cosT + plane.cyy*sinT*sinT
+	varV := plane.cxx*sinT*sinT - 2*plane.cxy*sinT*cosT + plane.cyy*cosT*cosT
+	missU := plane.miss * cosT
+	missV := -plane.miss * sinT
+
+	u := hardBodyRadius * hardBodyRadius / math.Sqrt(varU*varV)
+	v := missU*missU/varU + missV*missV/varV
+
+	// P = exp(-v/2) sum_m (v/2)^m/m! (1 - exp(-u/2) sum_k<=m (u/2)^k/k!)
+	pc := 0.0
+	outer := 1.0
+	inner := 1.0
+	innerSum := 1.0
+	for m := 0; m < 1000; m++ {
+		if m > 0 {
+			outer *= v / 2 / float64(m)
+			inner *= u / 2 / float64(m)
+			innerSum += inner
+		}
+		term := outer * (1 - math.Exp(-u/2)*innerSum)
+		pc += term
+		if m > 0 && (term <= 1e-16*pc || outer == 0) && float64(m) > v/2 {
+			break
+		}
+	}
+	return math.Exp(-v/2) * pc, nil
+}
+
+// Calculates the largest probability of collision any isotropic position covariance gives for a miss distance and
+// combined hard body radius in km, for conjunctions without covariance. Valid when the hard body radius is small
+// against the miss distance, 1 is returned when the miss distance is within the hard body radius.
+// Reference: Alfano, Relating Position Uncertainty to Maximum Conjunction Probability, 2005.
+func MaxCollisionProbability(missDistance, hardBodyRadius float64) (float64, error) {
+	if !(hardBodyRadius > 0) || math.IsInf(hardBodyRadius, 0) {
+		return 0, fmt.Errorf("%w: %f", ErrInvalidHardBodyRadius, hardBodyRadius)
+	}
+	if missDistance <= hardBodyRadius {
+		return 1, nil
+	}
+	// R^2/(2 s^2) exp(-d^2/(2 s^2)) peaks at s^2 = d^2/2
+	return hardBodyRadius * hardBodyRadius / (math.E * missDistance * missDistance), nil
+}
@@ -0,0 +1,112 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// NASA CARA Analysis Tools Pc2D_Foster unit test case, km and km^2
+var (
+	caraR1   = Vector3{X: 378.39559, Y: 4305.721887, Z: 5752.767554}
+	caraV1   = Vector3{X: 2.360800244, Y: 5.580331936, Z: -4.322349039}
+	caraR2   = Vector3{X: 374.5180598, Y: 4307.560983, Z: 5751.130418}
+	caraV2   = Vector3{X: -5.388125081, Y: -3.946827739, Z: 3.322820358}
+	caraCov1 = [3][3]float64{
+		{44.5757544811362, 81.6751751052616, -67.8687662707124},
+		{81.6751751052616, 158.453402956163, -128.616921644857},
+		{-67.8687662707124, -128.616921644857, 105.490542562701},
+	}
+	caraCov2 = [3][3]float64{
+		{2.31067077720423, 1.69905293875632, -1.4170164577661},
+		{1.69905293875632, 1.24957388457206, -1.04174164279599},
+		{-1.4170164577661, -1.04174164279599, 0.869260558223714},
+	}
+	caraHardBodyRadius = 0.020
+	caraPc             = 2.70601573490125e-05
+)
+
+func TestCollisionProbabilityFoster(t *testing.T) {
+	pc, err := CollisionProbabilityFoster(caraR1, caraV1, caraR2, caraV2, caraCov1, caraCov2, caraHardBodyRadius)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the accepted value comes from an integration with a 1e-9 absolute tolerance
+	if math.Abs(pc-caraPc)/caraPc > 1e-5 {
+		t.Errorf("expected Pc %g, got %g", caraPc, pc)
+	}
+}
+
+func isotropicCovariance(variance float64) [3][3]float64 {
+	return [3][3]float64{{variance, 0, 0}, {0, variance, 0}, {0, 0, variance}}
+}
+
+func TestCollisionProbabilityIsotropic(t *testing.T) {
+	// a head on encounter with zero miss distance has P = 1 - exp(-R^2/(2 s^2))
+	r := Vector3{X: 7000}
+	v1 := Vector3{Y: 7.5}
+	v2 := Vector3{Y: -7.5}
+	cov := isotropicCovariance(0.5 * 0.5 / 2)
+	radius := 0.3
+	expected := 1 - math.Exp(-radius*radius/(2*0.5*0.5))
+
+	foster, err := CollisionProbabilityFoster(r, v1, r, v2, cov, cov, radius)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chanPc, err := CollisionProbabilityChan(r, v1, r, v2, cov, cov, radius)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(foster-expected) > 1e-9 {
+		t.Errorf("Foster: expected %g, got %g", expected, foster)
+	}
+	if math.Abs(chanPc-expected) > 1e-12 {
+		t.Errorf("Chan: expected %g, got %g", expected, chanPc)
+	}
+}
+
+func TestCollisionProbabilityChan(t *testing.T) {
+	pc, err := CollisionProbabilityChan(caraR1, caraV1, caraR2, caraV2, caraCov1, caraCov2, caraHardBodyRadius)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the equivalent area circle approximation is close for a small hard body
+	if math.Abs(pc-caraPc)/caraPc > 1e-3 {
+		t.Errorf("expected Pc near %g, got %g", caraPc, pc)
+	}
+}
+
+func TestMaxCollisionProbability(t *testing.T) {
+	miss := caraR2.Sub(caraR1).Norm()
+	pc, err := MaxCollisionProbability(miss, caraHardBodyRadius)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// brute force over isotropic covariances in the encounter plane
+	best := 0.0
+	for sigma := miss / 100; sigma < miss*10; sigma *= 1.01 {
+		p := caraHardBodyRadius * caraHardBodyRadius / (2 * sigma * sigma) * math.Exp(-miss*miss/(2*sigma*sigma))
+		best = math.Max(best, p)
+	}
+	if math.Abs(pc-best)/best > 1e-3 {
+		t.Errorf("expected maximum Pc %g, got %g", best, pc)
+	}
+
+	if pc, err := MaxCollisionProbability(0.01, caraHardBodyRadius); err != nil || pc != 1 {
+		t.Errorf("expected 1 within the hard body radius, got %g %v", pc, err)
+	}
+}
+
+func TestCollisionProbabilityErrors(t *testing.T) {
+	if _, err := CollisionProbabilityFoster(caraR1, caraV1, caraR2, caraV2, caraCov1, caraCov2, 0); !errors.Is(err, ErrInvalidHardBodyRadius) {
+		t.Errorf("expected ErrInvalidHardBodyRadius, got %v", err)
+	}
+	if _, err := CollisionProbabilityFoster(caraR1, caraV1, caraR2, caraV1, caraCov1, caraCov2, caraHardBodyRadius); !errors.Is(err, ErrZeroRelativeVelocity) {
+		t.Errorf("expected ErrZeroRelativeVelocity, got %v", err)
+	}
+	var zero [3][3]float64
+	if _, err := CollisionProbabilityChan(caraR1, caraV1, caraR2, caraV2, zero, zero, caraHardBodyRadius); !errors.Is(err, ErrInvalidCovariance) {
+		t.Errorf("expected ErrInvalidCovariance, got %v", err)
+	}
+}
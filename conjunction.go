@@ -0,0 +1,285 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+var ErrInvalidScreeningWindow = errors.New("screening window stop is not after start")
+
+// Default margin in km for the apogee/perigee and orbit path filters, covering the short periodic and drag
+// variations the mean elements leave out
+const defaultScreeningPad = 30.0
+
+// Closest approaches are refined to this resolution in seconds
+const tcaTolerance = 1e-3
+
+// ScreeningSettings configures a conjunction screening
+type ScreeningSettings struct {
+	Start time.Time
+	Stop  time.Time
+	// Closest approaches with a miss distance below this many km are reported
+	Threshold float64
+	// Margin in km added to the threshold by the apogee/perigee and orbit path filters, defaults to 30 km
+	FilterPad float64
+	// Sampling step of the range rate, defaults to 1/40 of the shorter orbital period
+	Step time.Duration
+}
+
+// Conjunction is a closest approach between the primary and a catalog object
+type Conjunction struct {
+	// Index of the secondary in the screened catalog
+	Index         int
+	CatalogNumber string
+
+	TCA time.Time
+	// Distance at TCA in km
+	MissDistance float64
+	// Relative speed at TCA in km/s
+	RelativeSpeed float64
+	// Secondary minus primary in the primary's radial, in-track and cross-track directions, in km and km/s.
+	// X is radial, Y in-track and Z cross-track.
+	RelativePositionRIC Vector3
+	RelativeVelocityRIC Vector3
+}
+
+// Finds closest approaches between a primary and catalog objects within the window.
+// Pairs are pruned with the apogee/perigee filter and, for orbits that are not close to coplanar, the orbit path
+// filter before the range rate is sampled and its sign changes are bisected to the time of closest approach.
+// Catalog objects that decay during the window are skipped. Conjunctions are sorted by TCA.
+// Reference: Hoots, Crawford and Roehrich, An Analytical Method to Determine Future Close Approaches Between Satellites, 1984.
+func ScreenConjunctions(primary Satellite, catalog []Satellite, settings ScreeningSettings) ([]Conjunction, error) {
+	if !settings.Stop.After(settings.Start) {
+		return nil, fmt.Errorf("%w: %v to %v", ErrInvalidScreeningWindow, settings.Start, settings.Stop)
+	}
+	pad := settings.FilterPad
+	if pad == 0 {
+		pad = defaultScreeningPad
+	}
+
+	var conjunctions []Conjunction
+	for i, secondary := range catalog {
+		if !apogeePerigeeFilter(primary, secondary, settings.Threshold+pad) {
+			continue
+		}
+		if !orbitPathFilter(primary, secondary, settings, settings.Threshold+pad) {
+			continue
+		}
+		found, err := closestApproaches(primary, secondary, settings)
+		var secondaryErr *secondaryError
+		if errors.As(err, &secondaryErr) && isDecayError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("catalog object %d: %w", i, err)
+		}
+		for _, c := range found {
+			c.Index = i
+			c.CatalogNumber = secondary.Tle.CatalogNumber
+			conjunctions = append(conjunctions, c)
+		}
+	}
+	sort.SliceStable(conjunctions, func(i, j int) bool { return conjunctions[i].TCA.Before(conjunctions[j].TCA) })
+	return conjunctions, nil
+}
+
+// secondaryError marks a propagation error of the secondary object
+type secondaryError struct {
+	err error
+}
+
+func (e *secondaryError) Error() string {
+	return "secondary: " + e.err.Error()
+}
+
+func (e *secondaryError) Unwrap() error {
+	return e.err
+}
+
+// meanSemiMajorAxis returns the sgp4 mean semi-major axis in km
+func (sat Satellite) meanSemiMajorAxis() float64 {
+	// sgp4init leaves the un-Kozai mean motion in no
+	return sat.GravityConst.radiusearthkm * math.Pow(sat.GravityConst.xke/sat.no, 2.0/3.0)
+}
+
+// apogeePerigeeFilter reports whether the radial shells of two orbits come within the margin in km
+func apogeePerigeeFilter(primary, secondary Satellite, margin float64) bool {
+	a1, a2 := primary.meanSemiMajorAxis(), secondary.meanSemiMajorAxis()
+	perigee := math.Max(a1*(1-primary.ecco), a2*(1-secondary.ecco))
+	apogee := math.Min(a1*(1+primary.ecco), a2*(1+secondary.ecco))
+	return perigee-apogee <= margin
+}
+
+// Orbits closer to coplanar than this are not pruned by the orbit path filter
+const coplanarAngle = 1 * DEG2RAD
+
+// Bound on the short periodic variation of the node and inclination the mean elements leave out
+const shortPeriodicAngle = 0.02 * DEG2RAD
+
+// orbitPathFilter reports whether two orbits come within the margin in km near the mutual nodes of their planes.
+// A point an angle du from a node lies r sin(du) sin(i) out of the other plane, so only the arc within
+// asin(margin/(r sin i)) of each node can come within the margin. The radius ranges of both orbits over those arcs
+// are compared, with the arcs widened by a bound on the drift of the node and perigee over the window.
+func orbitPathFilter(primary, secondary Satellite, settings ScreeningSettings, margin float64) bool {
+	minutes := settings.Stop.Sub(settings.Start).Minutes()
+	start1 := (JDayTime(settings.Start) - primary.jdsatepoch) * 1440
+	start2 := (JDayTime(settings.Start) - secondary.jdsatepoch) * 1440
+
+	h1 := orbitNormal(primary.inclo, primary.nodeo+primary.nodedot*start1)
+	h2 := orbitNormal(secondary.inclo, secondary.nodeo+secondary.nodedot*start2)
+	relativeInclination := h1.Angle(h2)
+	if relativeInclination < coplanarAngle || relativeInclination > math.Pi-coplanarAngle {
+		return true
+	}
+
+	// a common nodal drift rotates the mutual nodes with both planes, only the differential drift moves them along
+	// the orbits
+	nodeDrift := (math.Abs(primary.nodedot-secondary.nodedot)*minutes + 2*shortPeriodicAngle) / math.Sin(relativeInclination)
+	arc1 := nodeArc(primary, margin, relativeInclination) + nodeDrift + math.Abs(primary.argpdot)*minutes
+	arc2 := nodeArc(secondary, margin, relativeInclination) + nodeDrift + math.Abs(secondary.argpdot)*minutes
+
+	nodeLine := h1.Cross(h2)
+	for _, direction := range []Vector3{nodeLine, nodeLine.Scale(-1)} {
+		low1, high1 := radiusRange(primary, start1, direction, arc1)
+		low2, high2 := radiusRange(secondary, start2, direction, arc2)
+		if math.Max(low1, low2)-math.Min(high1, high2) <= margin {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeArc returns the largest angle in radians from a mutual node at which the orbit can lie within the margin of a
+// plane inclined by the relative inclination
+func nodeArc(sat Satellite, margin, relativeInclination float64) float64 {
+	perigee := sat.meanSemiMajorAxis() * (1 - sat.ecco)
+	ratio := margin / (perigee * math.Sin(relativeInclination))
+	if ratio >= 1 {
+		return math.Pi
+	}
+	return math.Asin(ratio)
+}
+
+// orbitNormal returns the unit angular momentum direction of an orbit
+func orbitNormal(inclination, raan float64) Vector3 {
+	return Vector3{
+		X: math.Sin(inclination) * math.Sin(raan),
+		Y: -math.Sin(inclination) * math.Cos(raan),
+		Z: math.Cos(inclination),
+	}
+}
+
+// radiusRange returns the smallest and largest mean orbit radius in km over the arc of the orbit within arc radians
+// of a direction in its plane
+func radiusRange(sat Satellite, tsince float64, direction Vector3, arc float64) (low, high float64) {
+	raan := sat.nodeo + sat.nodedot*tsince
+	argp := sat.argpo + sat.argpdot*tsince
+	ascendingNode := Vector3{X: math.Cos(raan), Y: math.Sin(raan)}
+	h := orbitNormal(sat.inclo, raan)
+	argumentOfLatitude := math.Atan2(ascendingNode.Cross(direction).Dot(h), ascendingNode.Dot(direction))
+	trueAnomaly := argumentOfLatitude - argp
+
+	a := sat.meanSemiMajorAxis()
+	e := sat.ecco
+	p := a * (1 - e*e)
+	radius := func(nu float64) float64 {
+		return p / (1 + e*math.Cos(nu))
+	}
+	low = math.Min(radius(trueAnomaly-arc), radius(trueAnomaly+arc))
+	high = math.Max(radius(trueAnomaly-arc), radius(trueAnomaly+arc))
+	// the radius is monotonic between perigee and apogee, so the extremes are at the ends of the arc or at an apsis
+	// inside it
+	if arc >= math.Pi || math.Abs(wrapPi(trueAnomaly)) <= arc {
+		low = a * (1 - e)
+	}
+	if arc >= math.Pi || math.Abs(wrapPi(trueAnomaly-math.Pi)) <= arc {
+		high = a * (1 + e)
+	}
+	return low, high
+}
+
+// closestApproaches samples the range rate over the window and bisects each minimum of the range
+func closestApproaches(primary, secondary Satellite, settings ScreeningSettings) ([]Conjunction, error) {
+	step := settings.Step.Seconds()
+	if step <= 0 {
+		period := math.Min(TWOPI/primary.no, TWOPI/secondary.no) * 60
+		step = period / 40
+	}
+	window := settings.Stop.Sub(settings.Start).Seconds()
+	at := func(seconds float64) time.Time {
+		return settings.Start.Add(time.Duration(seconds * float64(time.Second)))
+	}
+
+	// range rate times range, positive when the objects separate
+	rangeRate := func(seconds float64) (float64, error) {
+		relPos, relVel, _, _, err := relativeState(primary, secondary, at(seconds))
+		if err != nil {
+			return 0, err
+		}
+		return relPos.Dot(relVel), nil
+	}
+
+	var conjunctions []Conjunction
+	previous, err := rangeRate(0)
+	if err != nil {
+		return nil, err
+	}
+	for t0 := 0.0; t0 < window; t0 += step {
+		t1 := math.Min(t0+step, window)
+		current, err := rangeRate(t1)
+		if err != nil {
+			return nil, err
+		}
+		if previous < 0 && current >= 0 {
+			lo, hi := t0, t1
+			for hi-lo > tcaTolerance {
+				mid := (lo + hi) / 2
+				value, err := rangeRate(mid)
+				if err != nil {
+					return nil, err
+				}
+				if value < 0 {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			tca := at((lo + hi) / 2)
+			relPos, relVel, pos, vel, err := relativeState(primary, secondary, tca)
+			if err != nil {
+				return nil, err
+			}
+			if miss := relPos.Norm(); miss <= settings.Threshold {
+				rotation, err := RICRotation(pos, vel)
+				if err != nil {
+					return nil, fmt.Errorf("primary: %w", err)
+				}
+				conjunctions = append(conjunctions, Conjunction{
+					TCA:                 tca,
+					MissDistance:        miss,
+					RelativeSpeed:       relVel.Norm(),
+					RelativePositionRIC: rotation.MulVec(relPos),
+					RelativeVelocityRIC: rotation.MulVec(relVel),
+				})
+			}
+		}
+		previous = current
+	}
+	return conjunctions, nil
+}
+
+// relativeState returns the secondary relative to the primary and the primary state at t
+func relativeState(primary, secondary Satellite, t time.Time) (relPos, relVel, pos, vel Vector3, err error) {
+	pos, vel, err = Propagate(primary, t)
+	if err != nil {
+		return relPos, relVel, pos, vel, fmt.Errorf("primary: %w", err)
+	}
+	pos2, vel2, err := Propagate(secondary, t)
+	if err != nil {
+		return relPos, relVel, pos, vel, &secondaryError{err: err}
+	}
+	return pos2.Sub(pos), vel2.Sub(vel), pos, vel, nil
+}
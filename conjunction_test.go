@@ -0,0 +1,157 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite/internal/testsat"
+)
+
+// ISS and copies crossing its plane, in an eccentric orbit with perigee at the crossing and in geostationary orbit
+var conjunctionTLEs = [][2]string{
+	{testsat.ISSLine1, testsat.ISSLine2},
+	{"1 99911U 98067A   20140.34419374 -.00000374  00000-0  13653-5 0  9990", "2 99911  53.0000 131.2277 0001338 330.3524 173.1622 15.49372617227549"},
+	{"1 99912U 98067A   20140.34419374 -.00000374  00000-0  13653-5 0  9990", "2 99912  53.0000 131.2277 0200000 000.0000 173.1622 15.49372617227549"},
+	{"1 99913U 98067A   20140.34419374 -.00000374  00000-0  13653-5 0  9990", "2 99913   0.0500 131.2277 0001338 330.3524 173.1622  1.00270000227549"},
+}
+
+func conjunctionCatalog(t *testing.T) []Satellite {
+	var sats []Satellite
+	for _, tle := range conjunctionTLEs {
+		sat, err := TLEToSat(tle[0], tle[1], GravityWGS72)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sats = append(sats, sat)
+	}
+	return sats
+}
+
+func TestScreeningFilters(t *testing.T) {
+	sats := conjunctionCatalog(t)
+	start := jdayToTime(sats[0].jdsatepoch)
+	settings := ScreeningSettings{Start: start, Stop: start.Add(6 * time.Hour), Threshold: 5}
+
+	tests := []struct {
+		index         int
+		apogeePerigee bool
+		orbitPath     bool
+	}{
+		{1, true, true},
+		{2, true, false},
+		{3, false, false},
+	}
+	for _, test := range tests {
+		if got := apogeePerigeeFilter(sats[0], sats[test.index], 35); got != test.apogeePerigee {
+			t.Errorf("apogee/perigee filter for %d: expected %v, got %v", test.index, test.apogeePerigee, got)
+		}
+		if test.apogeePerigee {
+			if got := orbitPathFilter(sats[0], sats[test.index], settings, 35); got != test.orbitPath {
+				t.Errorf("orbit path filter for %d: expected %v, got %v", test.index, test.orbitPath, got)
+			}
+		}
+	}
+}
+
+func TestOrbitPathFilterNearCoplanar(t *testing.T) {
+	sats := conjunctionCatalog(t)
+	// about 1.5 degrees from the primary plane with e = 0.05 and the node at a true anomaly of 90 degrees, so the radius
+	// at the node is about 90 km above the primary but the paths meet about 15 degrees from it
+	secondary, err := TLEToSat("1 99914U 98067A   20140.34419374 -.00000374  00000-0  13653-5 0  9990", "2 99914  53.1000 131.2277 0500000 270.0000 173.1622 15.14000000227549", GravityWGS72)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := jdayToTime(sats[0].jdsatepoch)
+	settings := ScreeningSettings{Start: start, Stop: start.Add(6 * time.Hour), Threshold: 10}
+
+	var path1, path2 []Vector3
+	for i := 0; i < 2000; i++ {
+		pos1, _, err := sgp4(&sats[0], float64(i)*TWOPI/sats[0].no/2000)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pos2, _, err := sgp4(&secondary, float64(i)*TWOPI/secondary.no/2000)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		path1, path2 = append(path1, pos1), append(path2, pos2)
+	}
+	closest := math.Inf(1)
+	for _, pos1 := range path1 {
+		for _, pos2 := range path2 {
+			closest = math.Min(closest, pos1.Sub(pos2).Norm())
+		}
+	}
+	if closest > 40 {
+		t.Fatalf("expected the paths to come within 40 km, got %f", closest)
+	}
+	if !orbitPathFilter(sats[0], secondary, settings, 40) {
+		t.Errorf("orbit path filter pruned paths %f km apart", closest)
+	}
+}
+
+func TestScreenConjunctions(t *testing.T) {
+	sats := conjunctionCatalog(t)
+	start := jdayToTime(sats[0].jdsatepoch)
+	settings := ScreeningSettings{Start: start, Stop: start.Add(6 * time.Hour), Threshold: 12}
+
+	conjunctions, err := ScreenConjunctions(sats[0], sats[1:], settings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the crossing copy starts at the same argument of latitude and meets the primary at both nodes every orbit
+	if len(conjunctions) < 7 {
+		t.Fatalf("expected at least 7 conjunctions, got %d", len(conjunctions))
+	}
+	for i, c := range conjunctions {
+		if c.Index != 0 || c.CatalogNumber != "99911" {
+			t.Errorf("unexpected secondary %d %s", c.Index, c.CatalogNumber)
+		}
+		if i > 0 && c.TCA.Before(conjunctions[i-1].TCA) {
+			t.Errorf("conjunctions are not sorted by TCA")
+		}
+		if math.Abs(c.RelativePositionRIC.Norm()-c.MissDistance) > 1e-6 || math.Abs(c.RelativeVelocityRIC.Norm()-c.RelativeSpeed) > 1e-9 {
+			t.Errorf("RIC components do not preserve length")
+		}
+		// the planes cross at 1.36 degrees at about 7.66 km/s
+		if math.Abs(c.RelativeSpeed-0.18) > 0.03 {
+			t.Errorf("unexpected relative speed %f", c.RelativeSpeed)
+		}
+
+		for _, offset := range []time.Duration{-10 * time.Second, 10 * time.Second} {
+			relPos, _, _, _, err := relativeState(sats[0], sats[1], c.TCA.Add(offset))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if relPos.Norm() < c.MissDistance {
+				t.Errorf("range %f at %v is below the miss distance %f", relPos.Norm(), offset, c.MissDistance)
+			}
+		}
+	}
+
+	settings.Stop = settings.Start
+	if _, err := ScreenConjunctions(sats[0], sats[1:], settings); !errors.Is(err, ErrInvalidScreeningWindow) {
+		t.Errorf("expected ErrInvalidScreeningWindow, got %v", err)
+	}
+}
+
+func TestScreenConjunctionsSkipsDecayedObjects(t *testing.T) {
+	sats := conjunctionCatalog(t)
+	decayed, err := TLEToSat("1 99902U 05037B   05333.02012661  .25992681  00000-0  24476-1 0  1534", "2 99902  96.4736 157.9986 0001931 221.8733 138.1530 16.26445040  1230", GravityWGS72)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := jdayToTime(sats[0].jdsatepoch)
+	settings := ScreeningSettings{Start: start, Stop: start.Add(3 * time.Hour), Threshold: 5000}
+	conjunctions, err := ScreenConjunctions(sats[0], []Satellite{decayed, sats[1]}, settings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, c := range conjunctions {
+		if c.Index == 0 {
+			t.Errorf("unexpected conjunction with a decayed object at %v", c.TCA)
+		}
+	}
+}
@@ -14,3 +14,9 @@ const XPDOTP float64 = 1440.0 / TWOPI
 const GRAVITY_EARTH float64 = 398600.4418
 const EQUATOR_RADIUS float64 = 6378.137
 const POLAR_RADIUS float64 = 6356.7523142
+const EARTH_ANGULAR_VELOCITY float64 = 7.292115e-5
+const ASTRONOMICAL_UNIT float64 = 149597870.7
+const GRAVITY_SUN float64 = 1.32712440018e11
+const GRAVITY_MOON float64 = 4902.800066
+const SOLAR_PRESSURE float64 = 4.56e-6
+const BSTAR_REFERENCE_DENSITY float64 = 0.15696615
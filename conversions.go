@@ -21,7 +21,7 @@ func days2mdhms(year int64, epochDays float64) (float64, float64, float64, float
 	i := 1.0
 	inttemp := 0.0
 
-	for dayofyr > inttemp+float64(lmonth[int(i-1)]) && i < 22 {
+	for dayofyr > inttemp+float64(lmonth[int(i-1)]) && i < 12 {
 		inttemp += float64(lmonth[int(i-1)])
 		i += 1
 	}
@@ -40,10 +40,12 @@ func days2mdhms(year int64, epochDays float64) (float64, float64, float64, float
 	return month, day, hour, minute, second
 }
 
+// Calc julian date for a time, converted to UTC and including fractional seconds
 func JDayTime(date time.Time) float64 {
+	date = date.UTC()
 	year, month, day := date.Date()
 	hour, minute, second := date.Clock()
-	jDay := JDay(year, int(month), day, hour, minute, float64(second))
+	jDay := JDay(year, int(month), day, hour, minute, float64(second)+float64(date.Nanosecond())/1e9)
 	return jDay
 }
 
@@ -79,17 +81,18 @@ func GSTimeFromDate(date time.Time) float64 {
 	return gstime(jDay)
 }
 
-// Holds latitude and Longitude in either degrees or radians
+// Geodetic latitude, longitude and altitude above the ellipsoid
 type Coordinates struct {
-	Latitude  float64
-	Longitude float64
-	Altitude  float64
+	Latitude  Angle
+	Longitude Angle
+	Altitude  Distance
 }
 
+// Azimuth from north through east, elevation above the horizon and range from the observer
 type LookAngles struct {
-	Azimuth   float64
-	Elevation float64
-	Range     float64
+	Azimuth   Angle
+	Elevation Angle
+	Range     Distance
 }
 
 // Convert Earth Centered Inertial coordinated into equivalent latitude, longitude, altitude and velocity.
@@ -119,28 +122,31 @@ func ECIToLLA(eciCoords Vector3, gmst float64) (velocity float64, ret Coordinate
 	// Orbital Speed ≈ sqrt(μ / r) where μ = std. gravitaional parameter
 	velocity = math.Sqrt(GRAVITY_EARTH / (altitude + EQUATOR_RADIUS))
 
-	ret.Latitude = latitude
-	ret.Longitude = longitude
-	ret.Altitude = altitude
+	ret.Latitude = Radians(latitude)
+	ret.Longitude = Radians(longitude)
+	ret.Altitude = Kilometers(altitude)
 
 	return
 }
 
-// Convert LatLong in radians to LatLong in degrees.
-func LatLongDeg(rad Coordinates) (Coordinates, error) {
-	var result Coordinates
-	result.Longitude = math.Mod(rad.Longitude/math.Pi*180, 360)
-	if result.Longitude > 180 {
-		result.Longitude = 360 - result.Longitude
-	} else if result.Longitude < -180 {
-		result.Longitude = 360 + result.Longitude
-	}
+// Latitude and longitude in degrees and altitude in km as plain numbers, as returned by LatLongDeg
+type LatLongDegrees struct {
+	Latitude  float64
+	Longitude float64
+	Altitude  float64
+}
 
-	if rad.Latitude < (-math.Pi/2) || rad.Latitude > math.Pi/2 {
-		return Coordinates{}, ErrInvalidLatitude
+// Converts coordinates to degrees with the longitude wrapped to -180 to 180 degrees.
+// Returns ErrInvalidLatitude for a latitude outside -90 to 90 degrees.
+func LatLongDeg(coords Coordinates) (LatLongDegrees, error) {
+	if coords.Latitude.Radians() < -math.Pi/2 || coords.Latitude.Radians() > math.Pi/2 {
+		return LatLongDegrees{}, ErrInvalidLatitude
 	}
-	result.Latitude = (rad.Latitude / math.Pi * 180)
-	return result, nil
+	return LatLongDegrees{
+		Latitude:  coords.Latitude.Degrees(),
+		Longitude: wrapPi(coords.Longitude.Radians()) * RAD2DEG,
+		Altitude:  coords.Altitude.Kilometers(),
+	}, nil
 }
 
 // Calculate GMST from Julian date.
@@ -158,61 +164,43 @@ func ThetaGJD(jday float64) float64 {
 // Convert latitude, longitude and altitude(km) into equivalent Earth Centered Intertial coordinates(km)
 // Reference: The 1992 Astronomical Almanac, page K11.
 func LLAToECI(obsCoords Coordinates, jday float64, grav GravConst) Vector3 {
-	theta := math.Mod(ThetaGJD(jday)+obsCoords.Longitude, TWOPI)
-	var eciObs Vector3
-	latSin := math.Sin(obsCoords.Latitude)
-	latCos := math.Cos(obsCoords.Latitude)
+	return ECEFToECI(GeodeticToECEF(obsCoords, grav), ThetaGJD(jday))
+}
+
+// Convert latitude, longitude and altitude(km) on the ellipsoid of the gravity model into Earth Centered Earth Fixed
+// coordinates(km)
+// Reference: The 1992 Astronomical Almanac, page K11.
+func GeodeticToECEF(coords Coordinates, grav GravConst) Vector3 {
+	latSin, latCos := math.Sincos(coords.Latitude.Radians())
+	lonSin, lonCos := math.Sincos(coords.Longitude.Radians())
+	altitude := coords.Altitude.Kilometers()
 	c := 1 / math.Sqrt(1+grav.flattening*(grav.flattening-2)*latSin*latSin)
 	sq := c * (1 - grav.flattening) * (1 - grav.flattening)
-	achcp := (grav.radiusearthkm*c + obsCoords.Altitude) * latCos
-
-	eciObs.X = achcp * math.Cos(theta)
-	eciObs.Y = achcp * math.Sin(theta)
-	eciObs.Z = (grav.radiusearthkm*sq + obsCoords.Altitude) * latSin
+	achcp := (grav.radiusearthkm*c + altitude) * latCos
 
-	return eciObs
+	return Vector3{
+		X: achcp * lonCos,
+		Y: achcp * lonSin,
+		Z: (grav.radiusearthkm*sq + altitude) * latSin,
+	}
 }
 
 // Convert Earth Centered Intertial coordinates into Earth Cenetered Earth Final coordinates
 // Reference: http://ccar.colorado.edu/ASEN5070/handouts/coordsys.doc
 func ECIToECEF(eciCoords Vector3, gmst float64) Vector3 {
-	var ecfCoords Vector3
-	ecfCoords.X = eciCoords.X*math.Cos(gmst) + eciCoords.Y*math.Sin(gmst)
-	ecfCoords.Y = eciCoords.X*-math.Sin(gmst) + eciCoords.Y*math.Cos(gmst)
-	ecfCoords.Z = eciCoords.Z
-	return ecfCoords
+	return R3(gmst).MulVec(eciCoords)
+}
+
+// Convert Earth Centered Earth Fixed coordinates into Earth Centered Inertial coordinates
+func ECEFToECI(ecefCoords Vector3, gmst float64) Vector3 {
+	return R3(-gmst).MulVec(ecefCoords)
 }
 
 // Calculate look angles for given satellite position and observer position
 // obsAlt in km
 // Reference: http://celestrak.com/columns/v02n02/
 func ECIToLookAngles(eciSat Vector3, obsCoords Coordinates, jday float64, grav GravConst) LookAngles {
-	theta := math.Mod(ThetaGJD(jday)+obsCoords.Longitude, TWOPI)
-	obsPos := LLAToECI(obsCoords, jday, grav)
-
-	rx := eciSat.X - obsPos.X
-	ry := eciSat.Y - obsPos.Y
-	rz := eciSat.Z - obsPos.Z
-
-	latSin := math.Sin(obsCoords.Latitude)
-	latCos := math.Cos(obsCoords.Latitude)
-	thetaSin := math.Sin(theta)
-	thetaCos := math.Cos(theta)
-
-	topS := latSin*thetaCos*rx + latSin*thetaSin*ry - latCos*rz
-	topE := -thetaSin*rx + thetaCos*ry
-	topZ := latCos*thetaCos*rx + latCos*thetaSin*ry + latSin*rz
-
-	var lookAngles LookAngles
-	lookAngles.Azimuth = math.Atan(-topE / topS)
-	if topS > 0 {
-		lookAngles.Azimuth += math.Pi
-	}
-	if lookAngles.Azimuth < 0 {
-		lookAngles.Azimuth += TWOPI
-	}
-	lookAngles.Range = math.Sqrt(rx*rx + ry*ry + rz*rz)
-	lookAngles.Elevation = math.Asin(topZ / lookAngles.Range)
-
-	return lookAngles
+	ecefSat := ECIToECEF(eciSat, ThetaGJD(jday))
+	enu := enuRotation(obsCoords).MulVec(ecefSat.Sub(GeodeticToECEF(obsCoords, grav)))
+	return enuToLookAngles(enu)
 }
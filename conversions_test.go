@@ -3,8 +3,33 @@ package satellite
 import (
 	"testing"
 	"time"
+
+	"github.com/infostellarinc/go-satellite/internal/testsat"
 )
 
+func TestJDayTime(t *testing.T) {
+	utc := time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		time time.Time
+		want float64
+	}{
+		{"J2000 epoch", utc, 2451545.0},
+		// the same instant in other locations is converted to UTC first, across the date line too
+		{"UTC+9", utc.In(time.FixedZone("JST", 9*3600)), 2451545.0},
+		{"UTC-10", utc.In(time.FixedZone("HST", -10*3600)), 2451545.0},
+		{"UTC+14 next day", time.Date(2000, 1, 2, 2, 0, 0, 0, time.FixedZone("LINT", 14*3600)), 2451545.0},
+		// fractional seconds are kept down to the float64 resolution of about 40 microseconds
+		{"nanoseconds", utc.Add(123456789 * time.Nanosecond), 2451545.0 + 0.123456789/86400},
+		{"half second", utc.Add(-500 * time.Millisecond), 2451545.0 - 0.5/86400},
+	}
+	for _, tt := range tests {
+		if got := JDayTime(tt.time); !closeFloatWithin(got, tt.want, 1e-9) {
+			t.Errorf("%s: JDayTime(%v) = %.10f, want %.10f", tt.name, tt.time, got, tt.want)
+		}
+	}
+}
+
 func TestECItoLookAngles(t *testing.T) {
 	tests := []struct {
 		name                string
@@ -20,8 +45,8 @@ func TestECItoLookAngles(t *testing.T) {
 	}{
 		{
 			name:                "ISS#22825 at 2020-05-23T20:23:37",
-			line1:               "1 25544U 98067A   20140.34419374 -.00000374  00000-0  13653-5 0  9990",
-			line2:               "2 25544  51.6433 131.2277 0001338 330.3524 173.1622 15.49372617227549",
+			line1:               testsat.ISSLine1,
+			line2:               testsat.ISSLine2,
 			gravConst:           GravityWGS72,
 			time:                time.Date(2020, 5, 23, 20, 23, 37, 0, time.UTC),
 			latitudeDegree:      55.6167,
@@ -46,20 +71,16 @@ func TestECItoLookAngles(t *testing.T) {
 				t.Errorf("sat.Propagate() error = %v", err)
 			}
 
-			coordinates := Coordinates{
-				Latitude:  tt.latitudeDegree * DEG2RAD,
-				Longitude: tt.longitudeDegree * DEG2RAD,
-				Altitude:  tt.altitude,
-			}
+			coordinates := Geodetic(tt.latitudeDegree, tt.longitudeDegree, tt.altitude)
 
 			lookAngles := ECIToLookAngles(pos, coordinates, jday, sat.GravityConst)
 
-			if !closeFloat(lookAngles.Azimuth*RAD2DEG, tt.wantAzimuthDegree) {
-				t.Errorf("ECItoLookAngles() Azimuth = %v, want %v", lookAngles.Azimuth*RAD2DEG, tt.wantAzimuthDegree)
+			if !closeFloat(lookAngles.Azimuth.Degrees(), tt.wantAzimuthDegree) {
+				t.Errorf("ECItoLookAngles() Azimuth = %v, want %v", lookAngles.Azimuth.Degrees(), tt.wantAzimuthDegree)
 			}
 
-			if !closeFloat(lookAngles.Elevation*RAD2DEG, tt.wantElevationDegree) {
-				t.Errorf("ECItoLookAngles() Elevation = %v, want %v", lookAngles.Elevation*RAD2DEG, tt.wantElevationDegree)
+			if !closeFloat(lookAngles.Elevation.Degrees(), tt.wantElevationDegree) {
+				t.Errorf("ECItoLookAngles() Elevation = %v, want %v", lookAngles.Elevation.Degrees(), tt.wantElevationDegree)
 			}
 		})
 	}
@@ -0,0 +1,295 @@
+// Package czml writes CZML documents for CesiumJS with satellite positions, ground stations and the access intervals
+// between them.
+//
+// Positions are written in meters as Lagrange interpolated samples, in seconds from the start of the window.
+package czml
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+)
+
+var ErrDuplicateID = errors.New("duplicate czml packet id")
+
+// Frame is the reference frame of the written satellite positions
+type Frame int
+
+const (
+	// Earth fixed, reached by rotating the propagator states with GMST
+	FrameFixed Frame = iota
+	// J2000 (EME2000), reached by rotating the TEME propagator states with satellite.TEMEToJ2000, declared to Cesium as
+	// INERTIAL. Cesium takes INERTIAL as ICRF, the J2000 frame bias of about 0.02 arcseconds is ignored.
+	FrameInertial
+)
+
+func (f Frame) String() string {
+	switch f {
+	case FrameFixed:
+		return "FIXED"
+	case FrameInertial:
+		return "INERTIAL"
+	}
+	return fmt.Sprintf("Frame(%d)", int(f))
+}
+
+// Object is a satellite to sample, its propagator returns TEME states as sgp4 does
+type Object struct {
+	ID         string
+	Name       string
+	Propagator satellite.Propagator
+}
+
+// Station is a ground station, access to every object is computed above its minimum elevation
+type Station struct {
+	ID           string
+	Name         string
+	Coordinates  satellite.Coordinates
+	MinElevation satellite.Angle
+}
+
+type Settings struct {
+	// Name of the document
+	Name  string
+	Start time.Time
+	Stop  time.Time
+	// Sample step of the positions and the access search
+	Step  time.Duration
+	Frame Frame
+	// Gravity model of the station ellipsoid
+	Gravity satellite.Gravity
+}
+
+type packet struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name,omitempty"`
+	Version      string    `json:"version,omitempty"`
+	Clock        *clock    `json:"clock,omitempty"`
+	Availability any       `json:"availability,omitempty"`
+	Label        *label    `json:"label,omitempty"`
+	Point        *point    `json:"point,omitempty"`
+	Path         *path     `json:"path,omitempty"`
+	Position     *position `json:"position,omitempty"`
+	Polyline     *polyline `json:"polyline,omitempty"`
+}
+
+type clock struct {
+	Interval    string  `json:"interval"`
+	CurrentTime string  `json:"currentTime"`
+	Multiplier  float64 `json:"multiplier"`
+	Range       string  `json:"range"`
+	Step        string  `json:"step"`
+}
+
+type label struct {
+	Text             string `json:"text"`
+	Font             string `json:"font,omitempty"`
+	HorizontalOrigin string `json:"horizontalOrigin,omitempty"`
+}
+
+type point struct {
+	PixelSize float64 `json:"pixelSize"`
+	Color     *color  `json:"color,omitempty"`
+}
+
+type color struct {
+	RGBA [4]int `json:"rgba"`
+}
+
+type path struct {
+	Width     float64 `json:"width"`
+	LeadTime  float64 `json:"leadTime"`
+	TrailTime float64 `json:"trailTime"`
+	Material  any     `json:"material,omitempty"`
+}
+
+type position struct {
+	Epoch                  string    `json:"epoch,omitempty"`
+	ReferenceFrame         string    `json:"referenceFrame,omitempty"`
+	InterpolationAlgorithm string    `json:"interpolationAlgorithm,omitempty"`
+	InterpolationDegree    int       `json:"interpolationDegree,omitempty"`
+	Cartesian              []float64 `json:"cartesian,omitempty"`
+	CartographicDegrees    []float64 `json:"cartographicDegrees,omitempty"`
+	References             []string  `json:"references,omitempty"`
+}
+
+type polyline struct {
+	Show      bool      `json:"show"`
+	Width     float64   `json:"width"`
+	Positions *position `json:"positions"`
+	Material  any       `json:"material,omitempty"`
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func formatInterval(start, stop time.Time) string {
+	return formatTime(start) + "/" + formatTime(stop)
+}
+
+func solidColor(r, g, b, a int) any {
+	return map[string]any{"solidColor": map[string]any{"color": color{RGBA: [4]int{r, g, b, a}}}}
+}
+
+// Writes a CZML document with a packet per object and station, and a line between a station and an object during
+// each access interval
+func Write(w io.Writer, objects []Object, stations []Station, settings Settings) error {
+	if !settings.Stop.After(settings.Start) || settings.Step <= 0 {
+		return fmt.Errorf("%w: %v to %v every %v", satellite.ErrInvalidSampling, settings.Start, settings.Stop, settings.Step)
+	}
+	if settings.Frame != FrameFixed && settings.Frame != FrameInertial {
+		return fmt.Errorf("unknown frame %d", int(settings.Frame))
+	}
+	ids := map[string]bool{"document": true}
+	for _, id := range append(objectIDs(objects), stationIDs(stations)...) {
+		if ids[id] {
+			return fmt.Errorf("%w: %q", ErrDuplicateID, id)
+		}
+		ids[id] = true
+	}
+
+	window := formatInterval(settings.Start, settings.Stop)
+	packets := []packet{{
+		ID:      "document",
+		Name:    settings.Name,
+		Version: "1.0",
+		Clock: &clock{
+			Interval:    window,
+			CurrentTime: formatTime(settings.Start),
+			Multiplier:  60,
+			Range:       "LOOP_STOP",
+			Step:        "SYSTEM_CLOCK_MULTIPLIER",
+		},
+	}}
+
+	for _, object := range objects {
+		p, err := objectPacket(object, settings)
+		if err != nil {
+			return fmt.Errorf("object %q: %w", object.ID, err)
+		}
+		packets = append(packets, p)
+	}
+	for _, station := range stations {
+		packets = append(packets, stationPacket(station))
+	}
+	for _, station := range stations {
+		for _, object := range objects {
+			intervals, err := satellite.AccessIntervals(object.Propagator, station.Coordinates, station.MinElevation,
+				settings.Start, settings.Stop, settings.Step, settings.Gravity)
+			if err != nil {
+				return fmt.Errorf("access from %q to %q: %w", station.ID, object.ID, err)
+			}
+			if len(intervals) == 0 {
+				continue
+			}
+			packets = append(packets, accessPacket(object, station, intervals))
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(packets)
+}
+
+func objectIDs(objects []Object) []string {
+	ids := make([]string, len(objects))
+	for i, o := range objects {
+		ids[i] = o.ID
+	}
+	return ids
+}
+
+func stationIDs(stations []Station) []string {
+	ids := make([]string, len(stations))
+	for i, s := range stations {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+func objectPacket(object Object, settings Settings) (packet, error) {
+	var samples []float64
+	for t := settings.Start; ; t = t.Add(settings.Step) {
+		if t.After(settings.Stop) {
+			t = settings.Stop
+		}
+		pos, vel, err := object.Propagator.PositionVelocity(t)
+		if err != nil {
+			return packet{}, fmt.Errorf("position at %v: %w", t, err)
+		}
+		if settings.Frame == FrameFixed {
+			pos = satellite.ECIToECEF(pos, satellite.GSTimeFromDate(t))
+		} else {
+			pos, _ = satellite.TEMEToJ2000(pos, vel, t)
+		}
+		samples = append(samples, t.Sub(settings.Start).Seconds(), pos.X*1000, pos.Y*1000, pos.Z*1000)
+		if !t.Before(settings.Stop) {
+			break
+		}
+	}
+
+	name := object.Name
+	if name == "" {
+		name = object.ID
+	}
+	return packet{
+		ID:           object.ID,
+		Name:         name,
+		Availability: formatInterval(settings.Start, settings.Stop),
+		Label:        &label{Text: name, Font: "11pt sans-serif", HorizontalOrigin: "LEFT"},
+		Point:        &point{PixelSize: 6, Color: &color{RGBA: [4]int{255, 255, 0, 255}}},
+		Path: &path{
+			Width:     1,
+			LeadTime:  0,
+			TrailTime: settings.Stop.Sub(settings.Start).Seconds(),
+			Material:  solidColor(255, 255, 0, 128),
+		},
+		Position: &position{
+			Epoch:                  formatTime(settings.Start),
+			ReferenceFrame:         settings.Frame.String(),
+			InterpolationAlgorithm: "LAGRANGE",
+			InterpolationDegree:    5,
+			Cartesian:              samples,
+		},
+	}, nil
+}
+
+func stationPacket(station Station) packet {
+	name := station.Name
+	if name == "" {
+		name = station.ID
+	}
+	c := station.Coordinates
+	return packet{
+		ID:    station.ID,
+		Name:  name,
+		Label: &label{Text: name, Font: "11pt sans-serif", HorizontalOrigin: "LEFT"},
+		Point: &point{PixelSize: 8, Color: &color{RGBA: [4]int{0, 255, 255, 255}}},
+		Position: &position{
+			CartographicDegrees: []float64{c.Longitude.Degrees(), c.Latitude.Degrees(), c.Altitude.Meters()},
+		},
+	}
+}
+
+func accessPacket(object Object, station Station, intervals []satellite.AccessInterval) packet {
+	availability := make([]string, len(intervals))
+	for i, interval := range intervals {
+		availability[i] = formatInterval(interval.Start, interval.End)
+	}
+	return packet{
+		ID:           "access/" + station.ID + "/" + object.ID,
+		Name:         fmt.Sprintf("%s to %s", station.ID, object.ID),
+		Availability: availability,
+		Polyline: &polyline{
+			Show:      true,
+			Width:     2,
+			Positions: &position{References: []string{station.ID + "#position", object.ID + "#position"}},
+			Material:  solidColor(0, 255, 0, 255),
+		},
+	}
+}
@@ -0,0 +1,132 @@
+package czml
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+	"github.com/infostellarinc/go-satellite/internal/testsat"
+)
+
+func testObject(t *testing.T) Object {
+	t.Helper()
+	return Object{ID: "25544", Name: "ISS", Propagator: testsat.ISS(t, satellite.TLEToSat, satellite.GravityWGS72)}
+}
+
+func TestWrite(t *testing.T) {
+	start := time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+	settings := Settings{
+		Name:    "passes",
+		Start:   start,
+		Stop:    start.Add(12 * time.Hour),
+		Step:    time.Minute,
+		Frame:   FrameInertial,
+		Gravity: satellite.GravityWGS72,
+	}
+	stations := []Station{{
+		ID:           "copenhagen",
+		Coordinates:  satellite.Geodetic(55.6167, 12.65, 0.005),
+		MinElevation: satellite.Degrees(5),
+	}}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, []Object{testObject(t)}, stations, settings); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var packets []struct {
+		ID           string          `json:"id"`
+		Version      string          `json:"version"`
+		Clock        *clock          `json:"clock"`
+		Availability json.RawMessage `json:"availability"`
+		Position     *position       `json:"position"`
+		Polyline     *polyline       `json:"polyline"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &packets); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(packets) != 4 {
+		t.Fatalf("expected document, object, station and access packets, got %d", len(packets))
+	}
+
+	document := packets[0]
+	if document.ID != "document" || document.Version != "1.0" || document.Clock == nil {
+		t.Fatalf("expected the document packet first, got %+v", document)
+	}
+	if document.Clock.Interval != "2020-05-23T00:00:00Z/2020-05-23T12:00:00Z" {
+		t.Errorf("unexpected clock interval %s", document.Clock.Interval)
+	}
+
+	object := packets[1]
+	if object.Position == nil || object.Position.ReferenceFrame != "INERTIAL" {
+		t.Fatalf("expected inertial positions, got %+v", object.Position)
+	}
+	if samples := len(object.Position.Cartesian); samples != 4*721 {
+		t.Errorf("expected 721 samples, got %d values", samples)
+	}
+	last := object.Position.Cartesian[len(object.Position.Cartesian)-4:]
+	pos, vel, err := testObject(t).Propagator.PositionVelocity(settings.Stop)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Cesium reads INERTIAL as ICRF, so the TEME states are written in J2000
+	j2000, _ := satellite.TEMEToJ2000(pos, vel, settings.Stop)
+	if last[0] != 12*3600 || last[1] != j2000.X*1000 || last[2] != j2000.Y*1000 || last[3] != j2000.Z*1000 {
+		t.Errorf("expected the last sample at the stop time in J2000 meters, got %v", last)
+	}
+	if last[1] == pos.X*1000 {
+		t.Errorf("expected the sample to differ from TEME")
+	}
+
+	station := packets[2]
+	if station.Position == nil || len(station.Position.CartographicDegrees) != 3 || station.Position.CartographicDegrees[2] != 5 {
+		t.Errorf("unexpected station position %+v", station.Position)
+	}
+
+	access := packets[3]
+	var intervals []string
+	if err := json.Unmarshal(access.Availability, &intervals); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if access.ID != "access/copenhagen/25544" || len(intervals) == 0 || access.Polyline == nil {
+		t.Fatalf("expected access intervals, got %s with %v", access.ID, intervals)
+	}
+	refs := access.Polyline.Positions.References
+	if len(refs) != 2 || refs[0] != "copenhagen#position" || refs[1] != "25544#position" {
+		t.Errorf("unexpected polyline references %v", refs)
+	}
+}
+
+func TestWriteFixedFrame(t *testing.T) {
+	start := time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+	settings := Settings{Start: start, Stop: start.Add(time.Hour), Step: 10 * time.Minute, Gravity: satellite.GravityWGS72}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, []Object{testObject(t)}, nil, settings); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var packets []packet
+	if err := json.Unmarshal(buf.Bytes(), &packets); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(packets) != 2 || packets[1].Position.ReferenceFrame != "FIXED" {
+		t.Fatalf("expected a fixed frame object packet, got %+v", packets)
+	}
+}
+
+func TestWriteInvalid(t *testing.T) {
+	start := time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+	settings := Settings{Start: start, Stop: start.Add(time.Hour), Step: time.Minute, Gravity: satellite.GravityWGS72}
+	object := testObject(t)
+
+	if err := Write(&bytes.Buffer{}, []Object{object, object}, nil, settings); !errors.Is(err, ErrDuplicateID) {
+		t.Errorf("expected ErrDuplicateID, got %v", err)
+	}
+	settings.Step = 0
+	if err := Write(&bytes.Buffer{}, []Object{object}, nil, settings); !errors.Is(err, satellite.ErrInvalidSampling) {
+		t.Errorf("expected ErrInvalidSampling, got %v", err)
+	}
+}
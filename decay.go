@@ -0,0 +1,166 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var ErrNoDecay = errors.New("no decay found within the search span")
+
+// Longest span after the TLE epoch searched for decay, 25 years in minutes
+const maxDecaySearch = 25 * 365.25 * 1440.0
+
+// Step in minutes of the decay search while the perigee is high
+const decayCoarseStep = 1440.0
+
+// Osculating perigee height in km below which the decay search samples every revolution
+const decayRefineHeight = 150.0
+
+// Decay times are bisected to this resolution in minutes
+const decayTolerance = 1e-3
+
+// DecayError reports the first instant at which sgp4 reports the satellite as decayed or its orbit as invalid.
+// Err is the sgp4 error at that instant, so errors.Is(err, ErrSatelliteDecay) and the other sentinels still match.
+type DecayError struct {
+	Epoch time.Time
+	Err   error
+}
+
+func (e *DecayError) Error() string {
+	return fmt.Sprintf("decayed at %s: %v", e.Epoch.Format(time.RFC3339Nano), e.Err)
+}
+
+func (e *DecayError) Unwrap() error {
+	return e.Err
+}
+
+// isDecayError reports whether an sgp4 error means the orbit has decayed rather than the input being invalid
+func isDecayError(err error) bool {
+	return errors.Is(err, ErrSatelliteDecay) ||
+		errors.Is(err, ErrInvalidMeanEccentricity) ||
+		errors.Is(err, ErrInvalidMeanMotion) ||
+		errors.Is(err, ErrInvalidPertubedEccentricity) ||
+		errors.Is(err, ErrInvalidSemilatusRectum)
+}
+
+// jdayToTime converts a julian date to a UTC time
+func jdayToTime(jd float64) time.Time {
+	days, fraction := math.Modf(jd - JULIAN_DAY_JAN_1_2000)
+	j2000 := time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)
+	return j2000.AddDate(0, 0, int(days)).Add(time.Duration(fraction * SECONDS_IN_DAY * float64(time.Second)))
+}
+
+// Finds the first instant after the TLE epoch at which sgp4 reports decay or an invalid eccentricity, mean motion or
+// semilatus rectum. The orbit is sampled daily until sgp4 fails or the osculating perigee falls below 150 km, then
+// eight times per revolution from the last daily sample, and the transition is bisected to 60 ms. Decay errors
+// are assumed to persist once reached, so the first failing daily sample bounds the search.
+// Returns ErrNoDecay when the model does not decay within 25 years.
+func DecayTime(sat Satellite) (time.Time, error) {
+	decay, err := findDecay(&sat, 0, maxDecaySearch)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return decay.Epoch, nil
+}
+
+// Calculates position and velocity vectors for given time like Propagate.
+// Once the model reports decay the vectors are zero and the error is a *DecayError carrying the decay epoch.
+func PropagateUntilDecay(sat Satellite, date time.Time) (position, velocity Vector3, err error) {
+	timeSince := (JDayTime(date) - sat.jdsatepoch) * 1440
+	position, velocity, err = sgp4(&sat, timeSince)
+	if err == nil || !isDecayError(err) || !(timeSince > 0) {
+		return position, velocity, err
+	}
+	decay, findErr := findDecay(&sat, 0, timeSince)
+	if findErr != nil {
+		return Vector3{}, Vector3{}, findErr
+	}
+	return Vector3{}, Vector3{}, decay
+}
+
+// findDecay scans sgp4 forward from start to stop minutes since epoch and bisects the first decay error.
+// The scan steps a day at a time while the osculating perigee stays above decayRefineHeight and only samples eight
+// times per revolution from the last coarse sample before the perigee drops or sgp4 first fails.
+func findDecay(sat *Satellite, start, stop float64) (*DecayError, error) {
+	fine := TWOPI / sat.no / 8
+	coarse := math.Max(decayCoarseStep, fine)
+	previous := start
+	for tsince := start; ; tsince += coarse {
+		if tsince > stop {
+			tsince = stop
+		}
+		pos, vel, err := sgp4(sat, tsince)
+		if err != nil {
+			if !isDecayError(err) {
+				return nil, err
+			}
+			if tsince == start {
+				return &DecayError{Epoch: sat.minutesToTime(tsince), Err: err}, nil
+			}
+			return scanDecay(sat, previous, tsince, fine)
+		}
+		if sat.perigeeHeight(pos, vel) < decayRefineHeight {
+			return scanDecay(sat, previous, stop, fine)
+		}
+		if tsince == stop {
+			return nil, fmt.Errorf("%w: searched %f minutes after epoch", ErrNoDecay, stop)
+		}
+		previous = tsince
+	}
+}
+
+// scanDecay samples sgp4 every step minutes from a valid start to stop and bisects the first decay error
+func scanDecay(sat *Satellite, start, stop, step float64) (*DecayError, error) {
+	previous := start
+	for tsince := start + step; ; tsince += step {
+		if tsince > stop {
+			tsince = stop
+		}
+		_, _, err := sgp4(sat, tsince)
+		if err != nil {
+			if !isDecayError(err) {
+				return nil, err
+			}
+			return bisectDecay(sat, previous, tsince, err)
+		}
+		if tsince == stop {
+			return nil, fmt.Errorf("%w: searched %f minutes after epoch", ErrNoDecay, stop)
+		}
+		previous = tsince
+	}
+}
+
+// perigeeHeight returns the height in km above the equatorial radius of the osculating perigee of a TEME state.
+// Degenerate states report zero so the search refines around them.
+func (sat *Satellite) perigeeHeight(pos, vel Vector3) float64 {
+	el, err := StateToKeplerian(pos, vel, sat.GravityConst.mu)
+	if err != nil {
+		return 0
+	}
+	return el.SemilatusRectum/(1+el.Eccentricity) - sat.GravityConst.radiusearthkm
+}
+
+// bisectDecay narrows the interval between a valid time and a decayed time
+func bisectDecay(sat *Satellite, valid, decayed float64, decayErr error) (*DecayError, error) {
+	for decayed-valid > decayTolerance {
+		mid := (valid + decayed) / 2
+		_, _, err := sgp4(sat, mid)
+		switch {
+		case err == nil:
+			valid = mid
+		case isDecayError(err):
+			decayed = mid
+			decayErr = err
+		default:
+			return nil, err
+		}
+	}
+	return &DecayError{Epoch: sat.minutesToTime(decayed), Err: decayErr}, nil
+}
+
+// minutesToTime converts minutes since the TLE epoch to a UTC time
+func (sat Satellite) minutesToTime(tsince float64) time.Time {
+	return jdayToTime(sat.jdsatepoch).Add(time.Duration(tsince * 60 * float64(time.Second)))
+}
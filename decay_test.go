@@ -0,0 +1,70 @@
+package satellite
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite/internal/testsat"
+)
+
+func TestDecayTime(t *testing.T) {
+	line1 := "1 99902U 05037B   05333.02012661  .25992681  00000-0  24476-1 0  1534"
+	line2 := "2 99902  96.4736 157.9986 0001931 221.8733 138.1530 16.26445040  1230"
+	sat, err := TLEToSat(line1, line2, GravityWGS72)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decay, err := DecayTime(sat)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	epoch := jdayToTime(sat.jdsatepoch)
+	if !decay.After(epoch) || decay.Sub(epoch) > 24*time.Hour {
+		t.Fatalf("expected decay within a day of epoch %v, got %v", epoch, decay)
+	}
+	if _, _, err := Propagate(sat, decay.Add(-time.Second)); err != nil {
+		t.Errorf("unexpected error before decay: %v", err)
+	}
+	if _, _, err := Propagate(sat, decay.Add(time.Second)); !errors.Is(err, ErrSatelliteDecay) {
+		t.Errorf("expected ErrSatelliteDecay after decay, got %v", err)
+	}
+
+	_, _, err = PropagateUntilDecay(sat, decay.Add(12*time.Hour))
+	var decayErr *DecayError
+	if !errors.As(err, &decayErr) {
+		t.Fatalf("expected *DecayError, got %v", err)
+	}
+	if !errors.Is(err, ErrSatelliteDecay) {
+		t.Errorf("expected the error to wrap ErrSatelliteDecay, got %v", err)
+	}
+	if d := decayErr.Epoch.Sub(decay); d > 100*time.Millisecond || d < -100*time.Millisecond {
+		t.Errorf("expected decay epoch %v, got %v", decay, decayErr.Epoch)
+	}
+
+	if _, _, err := PropagateUntilDecay(sat, epoch.Add(time.Hour)); err != nil {
+		t.Errorf("unexpected error before decay: %v", err)
+	}
+}
+
+func TestDecayTimeNoDecay(t *testing.T) {
+	sat := testsat.ISS(t, TLEToSat, GravityWGS72)
+	if _, err := DecayTime(sat); !errors.Is(err, ErrNoDecay) {
+		t.Fatalf("expected ErrNoDecay, got %v", err)
+	}
+}
+
+func TestJdayToTime(t *testing.T) {
+	dates := []time.Time{
+		time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(1980, 6, 15, 3, 20, 10, 0, time.UTC),
+		time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC),
+	}
+	for _, date := range dates {
+		got := jdayToTime(JDayTime(date))
+		if d := got.Sub(date); d > time.Millisecond || d < -time.Millisecond {
+			t.Errorf("expected %v, got %v", date, got)
+		}
+	}
+}
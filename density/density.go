@@ -0,0 +1,37 @@
+// Package density provides upper atmosphere density models for drag, evaluated at an Earth centered inertial
+// position in km and returning kg/m^3. Every model satisfies satellite.Atmosphere.
+//
+// Harris-Priester is a static diurnal bulge model. Jacchia71 is driven by F10.7 and Kp from a SolarActivity,
+// typically a SpaceWeather table read from CelesTrak's SW-All.csv.
+//
+// Jacchia-Roberts and NRLMSISE-00 are not provided. Both need published coefficient tables, Roberts' fits of the
+// 125 km number densities and the MSIS coefficient arrays, that are not in this tree. Jacchia71 is a simplified
+// Jacchia 1971 model and does not stand in for Jacchia-Roberts.
+package density
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+)
+
+var ErrAltitudeOutOfRange = errors.New("altitude outside the range of the density model")
+
+var (
+	_ satellite.Atmosphere = HarrisPriester{}
+	_ satellite.Atmosphere = Jacchia71{}
+)
+
+// geodetic returns the latitude in radians and height in km of an inertial position
+func geodetic(pos satellite.Vector3) (latitude, height float64) {
+	_, lla := satellite.ECIToLLA(pos, 0)
+	return lla.Latitude.Radians(), lla.Altitude.Kilometers()
+}
+
+// sunDirection returns the right ascension and declination of the Sun in radians
+func sunDirection(t time.Time) (rightAscension, declination float64) {
+	sun := satellite.SunPosition(t)
+	return math.Atan2(sun.Y, sun.X), math.Atan2(sun.Z, math.Hypot(sun.X, sun.Y))
+}
@@ -0,0 +1,196 @@
+package density
+
+import (
+	"errors"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+)
+
+var testEpoch = time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+
+// position at a height above the equator, at the Sun's right ascension or opposite to it
+func equatorialPosition(height float64, dayside bool) satellite.Vector3 {
+	rightAscension, _ := sunDirection(testEpoch)
+	if !dayside {
+		rightAscension += math.Pi
+	}
+	r := satellite.EQUATOR_RADIUS + height
+	return satellite.Vector3{X: r * math.Cos(rightAscension), Y: r * math.Sin(rightAscension)}
+}
+
+func TestReadSpaceWeatherCSV(t *testing.T) {
+	f, err := os.Open("testdata/SW-sample.csv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	sw, err := ReadSpaceWeatherCSV(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sw.Records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(sw.Records))
+	}
+
+	kp, err := sw.Kp(time.Date(2024, 1, 2, 4, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if kp != 4.0 {
+		t.Errorf("expected Kp 4.0, got %v", kp)
+	}
+	ap, err := sw.Ap(time.Date(2024, 1, 3, 22, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ap != 48 {
+		t.Errorf("expected blank ap filled from daily average 48, got %v", ap)
+	}
+	kp, err = sw.Kp(time.Date(2024, 1, 3, 22, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if kp != 5 {
+		t.Errorf("expected Kp 5 for ap 48, got %v", kp)
+	}
+
+	daily, average, err := sw.SolarFlux(testEpoch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if daily != 150.2 || average != 156.0 {
+		t.Errorf("expected F10.7 150.2 and average 156.0, got %v and %v", daily, average)
+	}
+
+	if _, _, err := sw.SolarFlux(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ErrNoSpaceWeather) {
+		t.Errorf("expected ErrNoSpaceWeather, got %v", err)
+	}
+}
+
+func TestKpFromAp(t *testing.T) {
+	tests := []struct {
+		ap, kp float64
+	}{
+		{0, 0},
+		{3, 2.0 / 3},
+		{15, 3},
+		{400, 9},
+		{1000, 9},
+	}
+	for _, test := range tests {
+		if kp := KpFromAp(test.ap); math.Abs(kp-test.kp) > 1e-12 {
+			t.Errorf("KpFromAp(%v): expected %v, got %v", test.ap, test.kp, kp)
+		}
+	}
+	for _, ap := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if kp := KpFromAp(ap); !math.IsNaN(kp) {
+			t.Errorf("KpFromAp(%v): expected NaN, got %v", ap, kp)
+		}
+	}
+}
+
+func TestBlankGeomagneticIndices(t *testing.T) {
+	// a prediction row without 3 hourly or daily ap
+	csv := "DATE,KP1,AP1,AP_AVG,F10.7_OBS,F10.7_ADJ,F10.7_OBS_CENTER81,F10.7_OBS_LAST81\n" +
+		"2024-01-02,,,,150.0,150.0,150.0,150.0\n"
+	sw, err := ReadSpaceWeatherCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := sw.Kp(testEpoch); !errors.Is(err, ErrNoSpaceWeather) {
+		t.Errorf("expected ErrNoSpaceWeather for a blank Kp, got %v", err)
+	}
+	if _, err := sw.Ap(testEpoch); !errors.Is(err, ErrNoSpaceWeather) {
+		t.Errorf("expected ErrNoSpaceWeather for a blank ap, got %v", err)
+	}
+	if _, err := (Jacchia71{Activity: sw}).Density(equatorialPosition(400, true), testEpoch); !errors.Is(err, ErrNoSpaceWeather) {
+		t.Errorf("expected ErrNoSpaceWeather from the density, got %v", err)
+	}
+}
+
+func TestHarrisPriester(t *testing.T) {
+	hp := HarrisPriester{}
+	day, err := hp.Density(equatorialPosition(400, true), testEpoch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	night, err := hp.Density(equatorialPosition(400, false), testEpoch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// bounded by the table's minimum and maximum at 400 km
+	if night < 2.2e-12 || day > 7.5e-12 || day <= night {
+		t.Errorf("unexpected densities at 400 km, day %e night %e", day, night)
+	}
+
+	if _, err := hp.Density(equatorialPosition(90, true), testEpoch); !errors.Is(err, ErrAltitudeOutOfRange) {
+		t.Errorf("expected ErrAltitudeOutOfRange, got %v", err)
+	}
+	if rho, err := hp.Density(equatorialPosition(1200, true), testEpoch); err != nil || rho != 0 {
+		t.Errorf("expected zero density above 1000 km, got %e %v", rho, err)
+	}
+}
+
+func TestJacchia71(t *testing.T) {
+	model := Jacchia71{Activity: ConstantActivity{F107: 150, F107Avg: 150, KpIndex: 3}}
+
+	previous := math.Inf(1)
+	for height := 100.0; height <= 1000; height += 50 {
+		rho, err := model.Density(equatorialPosition(height, true), testEpoch)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !(rho < previous) {
+			t.Fatalf("density does not decrease with height at %v km: %e", height, rho)
+		}
+		previous = rho
+	}
+
+	rho, err := model.Density(equatorialPosition(300, true), testEpoch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rho < 5e-12 || rho > 5e-11 {
+		t.Errorf("unexpected density at 300 km %e", rho)
+	}
+
+	night, err := model.Density(equatorialPosition(400, false), testEpoch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	day, err := model.Density(equatorialPosition(400, true), testEpoch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !(day > night) {
+		t.Errorf("expected a diurnal bulge, day %e night %e", day, night)
+	}
+
+	active := Jacchia71{Activity: ConstantActivity{F107: 250, F107Avg: 250, KpIndex: 3}}
+	high, err := active.Density(equatorialPosition(400, true), testEpoch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !(high > 2*day) {
+		t.Errorf("expected higher density for higher solar flux, %e and %e", high, day)
+	}
+
+	if _, err := model.Density(equatorialPosition(90, true), testEpoch); !errors.Is(err, ErrAltitudeOutOfRange) {
+		t.Errorf("expected ErrAltitudeOutOfRange, got %v", err)
+	}
+}
+
+func TestJacchiaTemperature(t *testing.T) {
+	tInf := 1000.0
+	if temperature := jacchiaTemperature(tInf, 90); math.Abs(temperature-jacchiaT0) > 1e-6 {
+		t.Errorf("expected %v K at 90 km, got %v", jacchiaT0, temperature)
+	}
+	if temperature := jacchiaTemperature(tInf, 2000); math.Abs(temperature-tInf) > 1 {
+		t.Errorf("expected exospheric temperature at 2000 km, got %v", temperature)
+	}
+}
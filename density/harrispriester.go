@@ -0,0 +1,111 @@
+package density
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+)
+
+// height (km), minimum and maximum density (g/km^3) for mean solar activity
+var harrisPriesterTable = [][3]float64{
+	{100, 497400.0, 497400.0},
+	{120, 24900.0, 24900.0},
+	{130, 8377.0, 8710.0},
+	{140, 3899.0, 4059.0},
+	{150, 2122.0, 2215.0},
+	{160, 1263.0, 1344.0},
+	{170, 800.8, 875.8},
+	{180, 528.3, 601.0},
+	{190, 361.7, 429.7},
+	{200, 255.7, 316.2},
+	{210, 183.9, 239.6},
+	{220, 134.1, 185.3},
+	{230, 99.49, 145.5},
+	{240, 74.88, 115.7},
+	{250, 57.09, 93.08},
+	{260, 44.03, 75.55},
+	{270, 34.30, 61.82},
+	{280, 26.97, 50.95},
+	{290, 21.39, 42.26},
+	{300, 17.08, 35.26},
+	{320, 10.99, 25.11},
+	{340, 7.214, 18.19},
+	{360, 4.824, 13.37},
+	{380, 3.274, 9.955},
+	{400, 2.249, 7.492},
+	{420, 1.558, 5.684},
+	{440, 1.091, 4.355},
+	{460, 0.7701, 3.362},
+	{480, 0.5474, 2.612},
+	{500, 0.3916, 2.042},
+	{520, 0.2819, 1.605},
+	{540, 0.2042, 1.267},
+	{560, 0.1488, 1.005},
+	{580, 0.1092, 0.7997},
+	{600, 0.08070, 0.6390},
+	{620, 0.06012, 0.5123},
+	{640, 0.04519, 0.4121},
+	{660, 0.03430, 0.3325},
+	{680, 0.02632, 0.2691},
+	{700, 0.02043, 0.2185},
+	{720, 0.01607, 0.1779},
+	{740, 0.01281, 0.1452},
+	{760, 0.01036, 0.1190},
+	{780, 0.008496, 0.09776},
+	{800, 0.007069, 0.08059},
+	{840, 0.004680, 0.05741},
+	{880, 0.003200, 0.04210},
+	{920, 0.002210, 0.03130},
+	{960, 0.001560, 0.02360},
+	{1000, 0.001150, 0.01810},
+}
+
+// Lag of the diurnal bulge apex behind the Sun
+const harrisPriesterLag = 30.0 * satellite.DEG2RAD
+
+// HarrisPriester is the modified Harris-Priester model for mean solar activity, valid from 100 to 1000 km.
+// Exponent shapes the diurnal bulge, 2 for low inclination orbits to 6 for polar orbits, 4 when zero.
+// Density above 1000 km is taken as zero.
+// Reference: Montenbruck and Gill, Satellite Orbits, section 3.5.2.
+type HarrisPriester struct {
+	Exponent float64
+}
+
+func (hp HarrisPriester) Density(pos satellite.Vector3, t time.Time) (float64, error) {
+	_, height := geodetic(pos)
+	table := harrisPriesterTable
+	if !(height >= table[0][0]) {
+		return 0, fmt.Errorf("%w: %f km is below %f km", ErrAltitudeOutOfRange, height, table[0][0])
+	}
+	if height >= table[len(table)-1][0] {
+		return 0, nil
+	}
+
+	i := 0
+	for height >= table[i+1][0] {
+		i++
+	}
+	hMin := (table[i][0] - table[i+1][0]) / math.Log(table[i+1][1]/table[i][1])
+	hMax := (table[i][0] - table[i+1][0]) / math.Log(table[i+1][2]/table[i][2])
+	rhoMin := table[i][1] * math.Exp((table[i][0]-height)/hMin)
+	rhoMax := table[i][2] * math.Exp((table[i][0]-height)/hMax)
+
+	rightAscension, declination := sunDirection(t)
+	apex := satellite.Vector3{
+		X: math.Cos(declination) * math.Cos(rightAscension+harrisPriesterLag),
+		Y: math.Cos(declination) * math.Sin(rightAscension+harrisPriesterLag),
+		Z: math.Sin(declination),
+	}
+	cosPsi := pos.Dot(apex) / pos.Norm()
+
+	n := hp.Exponent
+	if n == 0 {
+		n = 4
+	}
+	cosPsiHalfN := math.Pow(0.5+0.5*cosPsi, n/2)
+
+	// g/km^3 to kg/m^3
+	return (rhoMin + (rhoMax-rhoMin)*cosPsiHalfN) * 1e-12, nil
+}
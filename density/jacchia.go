@@ -0,0 +1,156 @@
+package density
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+)
+
+const (
+	jacchiaBoundaryHeight   = 100.0
+	jacchiaInflectionHeight = 125.0
+	jacchiaMaxHeight        = 2500.0
+	jacchiaT0               = 183.0
+
+	gasConstant = 8.31432
+	avogadro    = 6.022169e23
+	g0          = 9.80665
+	// radius used for the altitude dependence of gravity
+	jacchiaEarthRadius = 6356.766
+)
+
+// Gas constituents with molar mass (kg/mol), thermal diffusion coefficient and number density (m^-3) at 100 km
+var jacchiaSpecies = []struct {
+	molarMass          float64
+	thermalDiffusion   float64
+	boundaryNumberDens float64
+}{
+	{28.0134e-3, 0, 9.210e18},    // N2
+	{31.9988e-3, 0, 2.151e18},    // O2
+	{15.9994e-3, 0, 4.298e17},    // O
+	{39.948e-3, 0, 1.101e17},     // Ar
+	{4.0026e-3, -0.38, 5.851e13}, // He
+}
+
+// Jacchia71 is the Jacchia 1971 thermosphere model driven by F10.7 and Kp, valid from 100 to 2500 km.
+// Density above 2500 km is taken as zero.
+//
+// Exospheric temperature, the temperature profile, the geomagnetic and the semiannual variations follow J71.
+// Below 125 km the J71 mixing region is replaced by diffusive equilibrium from fixed U.S. Standard Atmosphere 1976
+// number densities at 100 km; hydrogen, the seasonal-latitudinal and the helium variations are not modeled.
+// Reference: Jacchia, Revised Static Models of the Thermosphere and Exosphere with Empirical Temperature Profiles,
+// SAO Special Report 332, 1971.
+type Jacchia71 struct {
+	Activity SolarActivity
+}
+
+func (j Jacchia71) Density(pos satellite.Vector3, t time.Time) (float64, error) {
+	latitude, height := geodetic(pos)
+	if !(height >= jacchiaBoundaryHeight) {
+		return 0, fmt.Errorf("%w: %f km is below %f km", ErrAltitudeOutOfRange, height, jacchiaBoundaryHeight)
+	}
+	if height > jacchiaMaxHeight {
+		return 0, nil
+	}
+
+	f107, f107Avg, err := j.Activity.SolarFlux(t)
+	if err != nil {
+		return 0, fmt.Errorf("solar flux: %w", err)
+	}
+	// geomagnetic heating lags the index by 6.7 hours
+	kp, err := j.Activity.Kp(t.Add(-402 * time.Minute))
+	if err != nil {
+		return 0, fmt.Errorf("kp: %w", err)
+	}
+
+	sunRightAscension, sunDeclination := sunDirection(t)
+	hourAngle := math.Remainder(math.Atan2(pos.Y, pos.X)-sunRightAscension, satellite.TWOPI)
+
+	tInf := jacchiaExosphericTemperature(f107, f107Avg, kp, latitude, sunDeclination, hourAngle, height)
+	rho := jacchiaStaticDensity(tInf, height)
+
+	logCorrection := jacchiaSemiannual(t, height)
+	if height < 200 {
+		logCorrection += 0.012*kp + 1.2e-5*math.Exp(kp)
+	}
+	return rho * math.Pow(10, logCorrection), nil
+}
+
+// jacchiaExosphericTemperature returns the exospheric temperature in K including diurnal and geomagnetic variations
+func jacchiaExosphericTemperature(f107, f107Avg, kp, latitude, sunDeclination, hourAngle, height float64) float64 {
+	// nighttime minimum global exospheric temperature
+	tc := 379.0 + 3.24*f107Avg + 1.3*(f107-f107Avg)
+
+	const r, m, n = 0.3, 2.2, 3.0
+	const beta, p, gamma = -37.0 * satellite.DEG2RAD, 6.0 * satellite.DEG2RAD, 43.0 * satellite.DEG2RAD
+	eta := math.Abs(latitude-sunDeclination) / 2
+	theta := math.Abs(latitude+sunDeclination) / 2
+	tau := hourAngle + beta + p*math.Sin(hourAngle+gamma)
+	tau = math.Max(-math.Pi, math.Min(math.Pi, tau))
+
+	sinTheta := math.Pow(math.Sin(theta), m)
+	cosEta := math.Pow(math.Cos(eta), m)
+	tl := tc * (1 + r*sinTheta + r*(cosEta-sinTheta)*math.Pow(math.Cos(tau/2), n))
+
+	if height >= 200 {
+		return tl + 28*kp + 0.03*math.Exp(kp)
+	}
+	return tl + 14*kp + 0.02*math.Exp(kp)
+}
+
+// jacchiaTemperature returns the temperature in K at a height in km for an exospheric temperature
+func jacchiaTemperature(tInf, height float64) float64 {
+	tx := 371.6678 + 0.0518806*tInf - 294.3505*math.Exp(-0.00216222*tInf)
+	if height <= jacchiaInflectionHeight {
+		z := height
+		polynomial := -89284375.0 + z*(3542400.0+z*(-52687.5+z*(340.5+z*-0.8)))
+		return tx + (tx-jacchiaT0)/1500625.0*polynomial
+	}
+	gx := 1.9 * (tx - jacchiaT0) / (jacchiaInflectionHeight - 90)
+	a := 2 * (tInf - tx) / math.Pi
+	dz := height - jacchiaInflectionHeight
+	return tx + a*math.Atan(gx*dz*(1+4.5e-6*math.Pow(dz, 2.5))/a)
+}
+
+// jacchiaStaticDensity returns the density in kg/m^3 from diffusive equilibrium above 100 km
+func jacchiaStaticDensity(tInf, height float64) float64 {
+	gOverRT := func(z float64) float64 {
+		g := g0 / ((1 + z/jacchiaEarthRadius) * (1 + z/jacchiaEarthRadius))
+		return g / (gasConstant * jacchiaTemperature(tInf, z))
+	}
+
+	// Simpson's rule with steps of at most 1 km, the integral is per meter
+	intervals := 2 * int(math.Ceil((height-jacchiaBoundaryHeight)/2))
+	integral := 0.0
+	if intervals > 0 {
+		step := (height - jacchiaBoundaryHeight) / float64(intervals)
+		sum := gOverRT(jacchiaBoundaryHeight) + gOverRT(height)
+		for i := 1; i < intervals; i++ {
+			weight := 2.0
+			if i%2 == 1 {
+				weight = 4
+			}
+			sum += weight * gOverRT(jacchiaBoundaryHeight+float64(i)*step)
+		}
+		integral = sum * step / 3 * 1000
+	}
+
+	temperatureRatio := jacchiaTemperature(tInf, jacchiaBoundaryHeight) / jacchiaTemperature(tInf, height)
+	rho := 0.0
+	for _, species := range jacchiaSpecies {
+		n := species.boundaryNumberDens * math.Pow(temperatureRatio, 1+species.thermalDiffusion) * math.Exp(-species.molarMass*integral)
+		rho += n * species.molarMass / avogadro
+	}
+	return rho
+}
+
+// jacchiaSemiannual returns the log10 density correction for the semiannual variation
+func jacchiaSemiannual(t time.Time, height float64) float64 {
+	phi := (satellite.JDayTime(t) - 2400999.5) / 365.2422
+	tau := phi + 0.09544*(math.Pow(0.5+0.5*math.Sin(satellite.TWOPI*phi+6.035), 1.650)-0.5)
+	g := 0.02835 + 0.3817*(1+0.4671*math.Sin(satellite.TWOPI*tau+4.137))*math.Sin(2*satellite.TWOPI*tau+4.259)
+	f := (5.876e-7*math.Pow(height, 2.331) + 0.06328) * math.Exp(-0.002868*height)
+	return f * g
+}
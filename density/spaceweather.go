@@ -0,0 +1,229 @@
+package density
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNoSpaceWeather = errors.New("no space weather data for time")
+var ErrMissingColumn = errors.New("space weather file is missing a column")
+
+// SolarActivity supplies the solar flux and geomagnetic indices that drive the density models
+type SolarActivity interface {
+	// F10.7 of the day before t and its 81 day centered average, in solar flux units
+	SolarFlux(t time.Time) (daily, average float64, err error)
+	// 3 hourly planetary Kp index at t
+	Kp(t time.Time) (float64, error)
+}
+
+// ConstantActivity is a fixed solar flux and geomagnetic index, for projections and tests
+type ConstantActivity struct {
+	F107    float64
+	F107Avg float64
+	KpIndex float64
+}
+
+func (c ConstantActivity) SolarFlux(t time.Time) (float64, float64, error) {
+	return c.F107, c.F107Avg, nil
+}
+
+func (c ConstantActivity) Kp(t time.Time) (float64, error) {
+	return c.KpIndex, nil
+}
+
+// One day of CelesTrak space weather data
+type SpaceWeatherRecord struct {
+	Date time.Time
+
+	// 3 hourly indices starting at 00:00 UT
+	Kp    [8]float64
+	Ap    [8]float64
+	ApAvg float64
+
+	F107Obs         float64
+	F107Adj         float64
+	F107ObsCenter81 float64
+	F107ObsLast81   float64
+	F107AdjCenter81 float64
+	F107AdjLast81   float64
+
+	// OBS, INT, PRD or PRM
+	DataType string
+}
+
+// SpaceWeather is a day by day table read from CelesTrak's SW-All.csv
+type SpaceWeather struct {
+	Records []SpaceWeatherRecord
+}
+
+// Reads CelesTrak's SW-All.csv (or SW-Last5Years.csv), columns are located by their header names.
+// Kp values in the file are multiplied by ten and are scaled back here. Blank 3 hourly values, as in the monthly
+// predictions, are filled from the daily average ap, and are left NaN when that is blank too.
+func ReadSpaceWeatherCSV(r io.Reader) (SpaceWeather, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return SpaceWeather{}, fmt.Errorf("header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	required := []string{"DATE", "AP_AVG", "F10.7_OBS", "F10.7_ADJ", "F10.7_OBS_CENTER81", "F10.7_OBS_LAST81"}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return SpaceWeather{}, fmt.Errorf("%w: %s", ErrMissingColumn, name)
+		}
+	}
+
+	var sw SpaceWeather
+	line := 1
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return SpaceWeather{}, fmt.Errorf("line %d: %w", line, err)
+		}
+		value := func(name string) (float64, error) {
+			i, ok := columns[name]
+			if !ok || i >= len(row) || strings.TrimSpace(row[i]) == "" {
+				return math.NaN(), nil
+			}
+			return strconv.ParseFloat(strings.TrimSpace(row[i]), 64)
+		}
+
+		var record SpaceWeatherRecord
+		record.Date, err = time.Parse("2006-01-02", strings.TrimSpace(row[columns["DATE"]]))
+		if err != nil {
+			return SpaceWeather{}, fmt.Errorf("line %d: DATE: %w", line, err)
+		}
+		fields := []struct {
+			name string
+			dst  *float64
+		}{
+			{"AP_AVG", &record.ApAvg},
+			{"F10.7_OBS", &record.F107Obs},
+			{"F10.7_ADJ", &record.F107Adj},
+			{"F10.7_OBS_CENTER81", &record.F107ObsCenter81},
+			{"F10.7_OBS_LAST81", &record.F107ObsLast81},
+			{"F10.7_ADJ_CENTER81", &record.F107AdjCenter81},
+			{"F10.7_ADJ_LAST81", &record.F107AdjLast81},
+		}
+		for _, field := range fields {
+			*field.dst, err = value(field.name)
+			if err != nil {
+				return SpaceWeather{}, fmt.Errorf("line %d: %s: %w", line, field.name, err)
+			}
+		}
+		for i := 0; i < 8; i++ {
+			kp, err := value(fmt.Sprintf("KP%d", i+1))
+			if err != nil {
+				return SpaceWeather{}, fmt.Errorf("line %d: KP%d: %w", line, i+1, err)
+			}
+			ap, err := value(fmt.Sprintf("AP%d", i+1))
+			if err != nil {
+				return SpaceWeather{}, fmt.Errorf("line %d: AP%d: %w", line, i+1, err)
+			}
+			if math.IsNaN(ap) {
+				ap = record.ApAvg
+			}
+			if math.IsNaN(kp) {
+				kp = KpFromAp(ap)
+			} else {
+				kp /= 10
+			}
+			record.Kp[i] = kp
+			record.Ap[i] = ap
+		}
+		if i, ok := columns["F10.7_DATA_TYPE"]; ok && i < len(row) {
+			record.DataType = strings.TrimSpace(row[i])
+		}
+		sw.Records = append(sw.Records, record)
+	}
+
+	sort.Slice(sw.Records, func(i, j int) bool { return sw.Records[i].Date.Before(sw.Records[j].Date) })
+	return sw, nil
+}
+
+// Returns the record for the UT day containing t
+func (sw SpaceWeather) Day(t time.Time) (SpaceWeatherRecord, error) {
+	day := t.UTC().Truncate(24 * time.Hour)
+	i := sort.Search(len(sw.Records), func(i int) bool { return !sw.Records[i].Date.Before(day) })
+	if i == len(sw.Records) || !sw.Records[i].Date.Equal(day) {
+		return SpaceWeatherRecord{}, fmt.Errorf("%w: %v", ErrNoSpaceWeather, t)
+	}
+	return sw.Records[i], nil
+}
+
+// Observed F10.7 of the day before t and the observed 81 day centered average of the day of t
+func (sw SpaceWeather) SolarFlux(t time.Time) (daily, average float64, err error) {
+	yesterday, err := sw.Day(t.Add(-24 * time.Hour))
+	if err != nil {
+		return 0, 0, err
+	}
+	today, err := sw.Day(t)
+	if err != nil {
+		return 0, 0, err
+	}
+	if math.IsNaN(yesterday.F107Obs) || math.IsNaN(today.F107ObsCenter81) {
+		return 0, 0, fmt.Errorf("%w: blank F10.7 at %v", ErrNoSpaceWeather, t)
+	}
+	return yesterday.F107Obs, today.F107ObsCenter81, nil
+}
+
+// 3 hourly planetary Kp index at t
+func (sw SpaceWeather) Kp(t time.Time) (float64, error) {
+	record, err := sw.Day(t)
+	if err != nil {
+		return 0, err
+	}
+	kp := record.Kp[t.UTC().Hour()/3]
+	if math.IsNaN(kp) {
+		return 0, fmt.Errorf("%w: blank Kp at %v", ErrNoSpaceWeather, t)
+	}
+	return kp, nil
+}
+
+// 3 hourly planetary ap index at t
+func (sw SpaceWeather) Ap(t time.Time) (float64, error) {
+	record, err := sw.Day(t)
+	if err != nil {
+		return 0, err
+	}
+	ap := record.Ap[t.UTC().Hour()/3]
+	if math.IsNaN(ap) {
+		return 0, fmt.Errorf("%w: blank ap at %v", ErrNoSpaceWeather, t)
+	}
+	return ap, nil
+}
+
+// ap equivalents of Kp 0o, 0+, 1-, 1o ... 9o
+var apTable = []float64{0, 2, 3, 4, 5, 6, 7, 9, 12, 15, 18, 22, 27, 32, 39, 48, 56, 67, 80, 94, 111, 132, 154, 179, 207, 236, 300, 400}
+
+// Converts an ap index to Kp by linear interpolation of the standard conversion table, NaN for a non-finite ap
+func KpFromAp(ap float64) float64 {
+	if math.IsNaN(ap) || math.IsInf(ap, 0) {
+		return math.NaN()
+	}
+	if ap <= 0 {
+		return 0
+	}
+	for i := 1; i < len(apTable); i++ {
+		if ap <= apTable[i] {
+			fraction := (ap - apTable[i-1]) / (apTable[i] - apTable[i-1])
+			return (float64(i-1) + fraction) / 3
+		}
+	}
+	return 9
+}
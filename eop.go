@@ -0,0 +1,211 @@
+package satellite
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNoEOP = errors.New("no earth orientation parameters")
+var ErrMissingEOPColumn = errors.New("earth orientation parameter file is missing a column")
+
+// EarthOrientation supplies the polar motion and UT1-UTC that relate TEME to ITRF
+type EarthOrientation interface {
+	// Pole coordinates x and y in arcseconds and UT1-UTC in seconds at t
+	Orientation(t time.Time) (x, y, ut1MinusUTC float64, err error)
+}
+
+// ConstantOrientation is a fixed polar motion and UT1-UTC, for a short span around one epoch and tests
+type ConstantOrientation struct {
+	// arcseconds
+	X float64
+	Y float64
+	// seconds
+	UT1MinusUTC float64
+}
+
+func (c ConstantOrientation) Orientation(t time.Time) (float64, float64, float64, error) {
+	return c.X, c.Y, c.UT1MinusUTC, nil
+}
+
+// One day of CelesTrak earth orientation parameters, at 00:00 UTC
+type EOPRecord struct {
+	Date time.Time
+
+	// Pole coordinates in arcseconds
+	X float64
+	Y float64
+	// seconds
+	UT1MinusUTC float64
+	// Excess length of day in seconds
+	LOD float64
+	// IAU 1980 nutation corrections in arcseconds
+	DPsi float64
+	DEps float64
+	// IAU 2000 celestial pole offsets in arcseconds
+	DX float64
+	DY float64
+	// TAI-UTC in seconds
+	DAT float64
+
+	// O for observed or P for predicted
+	DataType string
+}
+
+// EOP is a day by day table read from CelesTrak's EOP-All.csv
+type EOP struct {
+	Records []EOPRecord
+}
+
+// Reads CelesTrak's EOP-All.csv (or EOP-Last5Years.csv), columns are located by their header names.
+// DATE, X, Y and UT1-UTC are required, the other columns are NaN when missing or blank.
+func ReadEOPCSV(r io.Reader) (EOP, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return EOP{}, fmt.Errorf("header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	required := []string{"DATE", "X", "Y", "UT1-UTC"}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return EOP{}, fmt.Errorf("%w: %s", ErrMissingEOPColumn, name)
+		}
+	}
+
+	var eop EOP
+	line := 1
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return EOP{}, fmt.Errorf("line %d: %w", line, err)
+		}
+		value := func(name string) (float64, error) {
+			i, ok := columns[name]
+			if !ok || i >= len(row) || strings.TrimSpace(row[i]) == "" {
+				return math.NaN(), nil
+			}
+			return strconv.ParseFloat(strings.TrimSpace(row[i]), 64)
+		}
+
+		var record EOPRecord
+		record.Date, err = time.Parse("2006-01-02", strings.TrimSpace(row[columns["DATE"]]))
+		if err != nil {
+			return EOP{}, fmt.Errorf("line %d: DATE: %w", line, err)
+		}
+		fields := []struct {
+			name string
+			dst  *float64
+		}{
+			{"X", &record.X},
+			{"Y", &record.Y},
+			{"UT1-UTC", &record.UT1MinusUTC},
+			{"LOD", &record.LOD},
+			{"DPSI", &record.DPsi},
+			{"DEPS", &record.DEps},
+			{"DX", &record.DX},
+			{"DY", &record.DY},
+			{"DAT", &record.DAT},
+		}
+		for _, field := range fields {
+			*field.dst, err = value(field.name)
+			if err != nil {
+				return EOP{}, fmt.Errorf("line %d: %s: %w", line, field.name, err)
+			}
+		}
+		if i, ok := columns["DATA_TYPE"]; ok && i < len(row) {
+			record.DataType = strings.TrimSpace(row[i])
+		}
+		eop.Records = append(eop.Records, record)
+	}
+
+	sort.Slice(eop.Records, func(i, j int) bool { return eop.Records[i].Date.Before(eop.Records[j].Date) })
+	return eop, nil
+}
+
+// Pole coordinates in arcseconds and UT1-UTC in seconds at t, interpolated linearly between the daily records.
+// UT1-TAI is interpolated when both days have TAI-UTC, so UT1-UTC steps at a leap second instead of drifting across
+// the day before it.
+func (e EOP) Orientation(t time.Time) (x, y, ut1MinusUTC float64, err error) {
+	t = t.UTC()
+	i := sort.Search(len(e.Records), func(i int) bool { return e.Records[i].Date.After(t) }) - 1
+	if i < 0 || (i == len(e.Records)-1 && !e.Records[i].Date.Equal(t)) {
+		return 0, 0, 0, fmt.Errorf("%w: %v", ErrNoEOP, t)
+	}
+	before := e.Records[i]
+	after := before
+	fraction := 0.0
+	if i+1 < len(e.Records) {
+		after = e.Records[i+1]
+		fraction = t.Sub(before.Date).Seconds() / after.Date.Sub(before.Date).Seconds()
+	}
+	if math.IsNaN(before.X) || math.IsNaN(before.Y) || math.IsNaN(before.UT1MinusUTC) ||
+		math.IsNaN(after.X) || math.IsNaN(after.Y) || math.IsNaN(after.UT1MinusUTC) {
+		return 0, 0, 0, fmt.Errorf("%w: blank values at %v", ErrNoEOP, t)
+	}
+
+	interpolate := func(a, b float64) float64 {
+		return a + (b-a)*fraction
+	}
+	x = interpolate(before.X, after.X)
+	y = interpolate(before.Y, after.Y)
+	if math.IsNaN(before.DAT) || math.IsNaN(after.DAT) {
+		ut1MinusUTC = interpolate(before.UT1MinusUTC, after.UT1MinusUTC)
+	} else {
+		ut1MinusUTC = interpolate(before.UT1MinusUTC-before.DAT, after.UT1MinusUTC-after.DAT) + before.DAT
+	}
+	return x, y, ut1MinusUTC, nil
+}
+
+// polarMotion returns the rotation from the pseudo Earth fixed frame to ITRF for pole coordinates in radians, the
+// transpose of Vallado's IAU 1980 polarm
+func polarMotion(x, y float64) Matrix3 {
+	cx, sx := math.Cos(x), math.Sin(x)
+	cy, sy := math.Cos(y), math.Sin(y)
+	return Matrix3{
+		{cx, sx * sy, sx * cy},
+		{0, cy, -sy},
+		{-sx, cx * sy, cx * cy},
+	}
+}
+
+// Converts a TEME position and velocity, as returned by Propagate, to ITRF. TEME is rotated by GMST of UT1 into the
+// pseudo Earth fixed frame and polar motion takes that to ITRF. The velocity is relative to the rotating Earth at the
+// nominal rotation rate, the length of day is not applied.
+// Reference: Vallado, Crawford, Hujsak and Kelso, Revisiting Spacetrack Report #3, 2006, appendix C.
+func TEMEToITRF(pos, vel Vector3, t time.Time, eop EarthOrientation) (Vector3, Vector3, error) {
+	x, y, ut1MinusUTC, err := eop.Orientation(t)
+	if err != nil {
+		return Vector3{}, Vector3{}, err
+	}
+	ut1 := t.Add(time.Duration(ut1MinusUTC * float64(time.Second)))
+	pos, vel = ECIToECEFState(pos, vel, GSTimeFromDate(ut1))
+	rotation := polarMotion(x*arcsecond, y*arcsecond)
+	return rotation.MulVec(pos), rotation.MulVec(vel), nil
+}
+
+// Converts an ITRF position and velocity to TEME, the inverse of TEMEToITRF
+func ITRFToTEME(pos, vel Vector3, t time.Time, eop EarthOrientation) (Vector3, Vector3, error) {
+	x, y, ut1MinusUTC, err := eop.Orientation(t)
+	if err != nil {
+		return Vector3{}, Vector3{}, err
+	}
+	rotation := polarMotion(x*arcsecond, y*arcsecond).Transpose()
+	ut1 := t.Add(time.Duration(ut1MinusUTC * float64(time.Second)))
+	pos, vel = ECEFToECIState(rotation.MulVec(pos), rotation.MulVec(vel), GSTimeFromDate(ut1))
+	return pos, vel, nil
+}
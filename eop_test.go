@@ -0,0 +1,90 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTEMEToITRF(t *testing.T) {
+	// Vallado, Fundamentals of Astrodynamics and Applications, example 3-15
+	epoch := time.Date(2004, 4, 6, 7, 51, 28, 386009000, time.UTC)
+	eop := ConstantOrientation{X: -0.140682, Y: 0.333309, UT1MinusUTC: -0.4399619}
+	pos := Vector3{X: 5094.18016210, Y: 6127.64465950, Z: 6380.34453270}
+	vel := Vector3{X: -4.746131487, Y: 0.785818041, Z: 5.531931288}
+
+	gotPos, gotVel, err := TEMEToITRF(pos, vel, epoch, eop)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantPos := Vector3{X: -1033.4793830, Y: 7901.2952754, Z: 6380.3565958}
+	wantVel := Vector3{X: -3.225636520, Y: -2.872451450, Z: 5.531924446}
+	if !gotPos.EqualsWithin(wantPos, 1e-6) || !gotVel.EqualsWithin(wantVel, 1e-6) {
+		t.Errorf("TEMEToITRF() = %v %v, want %v %v", gotPos, gotVel, wantPos, wantVel)
+	}
+
+	backPos, backVel, err := ITRFToTEME(gotPos, gotVel, epoch, eop)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !backPos.EqualsWithin(pos, 1e-8) || !backVel.EqualsWithin(vel, 1e-11) {
+		t.Errorf("ITRFToTEME() = %v %v, want %v %v", backPos, backVel, pos, vel)
+	}
+}
+
+// Values around the leap second at the end of 2005, the last day is a blank prediction
+const eopSample = `DATE,MJD,X,Y,UT1-UTC,LOD,DPSI,DEPS,DX,DY,DAT,DATA_TYPE
+2005-12-30,53734,0.050000,0.380000,-0.6600000,0.0008000,-0.050000,-0.004000,0.000100,-0.000100,32,O
+2005-12-31,53735,0.052000,0.382000,-0.6610000,0.0007000,-0.051000,-0.004100,0.000200,-0.000200,32,O
+2006-01-01,53736,0.054000,0.384000,0.3385000,0.0006000,-0.052000,-0.004200,0.000300,-0.000300,33,O
+2006-01-02,53737,,,,,,,,,33,P
+`
+
+func TestEOP(t *testing.T) {
+	eop, err := ReadEOPCSV(strings.NewReader(eopSample))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(eop.Records) != 4 || eop.Records[0].LOD != 0.0008 || eop.Records[2].DAT != 33 || eop.Records[3].DataType != "P" {
+		t.Fatalf("unexpected records %+v", eop.Records)
+	}
+
+	tests := []struct {
+		t           time.Time
+		x, y, ut1   float64
+		description string
+	}{
+		{time.Date(2005, 12, 30, 0, 0, 0, 0, time.UTC), 0.05, 0.38, -0.66, "first record"},
+		{time.Date(2005, 12, 30, 6, 0, 0, 0, time.UTC), 0.0505, 0.3805, -0.66025, "a quarter day"},
+		// UT1-UTC steps by a second at the leap second and UT1-TAI is interpolated across it
+		{time.Date(2005, 12, 31, 12, 0, 0, 0, time.UTC), 0.053, 0.383, -0.66125, "the day before the leap second"},
+	}
+	for _, test := range tests {
+		x, y, ut1, err := eop.Orientation(test.t)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.description, err)
+		}
+		if math.Abs(x-test.x) > 1e-12 || math.Abs(y-test.y) > 1e-12 || math.Abs(ut1-test.ut1) > 1e-9 {
+			t.Errorf("%s: got %f %f %f, want %f %f %f", test.description, x, y, ut1, test.x, test.y, test.ut1)
+		}
+	}
+
+	for _, at := range []time.Time{
+		time.Date(2005, 12, 29, 23, 0, 0, 0, time.UTC),
+		time.Date(2006, 1, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2006, 1, 3, 0, 0, 0, 0, time.UTC),
+	} {
+		if _, _, _, err := eop.Orientation(at); !errors.Is(err, ErrNoEOP) {
+			t.Errorf("expected ErrNoEOP at %v, got %v", at, err)
+		}
+		if _, _, err := TEMEToITRF(Vector3{X: 7000}, Vector3{Y: 7}, at, eop); !errors.Is(err, ErrNoEOP) {
+			t.Errorf("expected ErrNoEOP converting at %v, got %v", at, err)
+		}
+	}
+
+	if _, err := ReadEOPCSV(strings.NewReader("DATE,X,Y\n2006-01-01,0.1,0.2\n")); !errors.Is(err, ErrMissingEOPColumn) {
+		t.Errorf("expected ErrMissingEOPColumn, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var ErrInvalidEphemeris = errors.New("invalid ephemeris")
+var ErrOutsideEphemeris = errors.New("time is outside the ephemeris coverage")
+
+// Interpolation is the method used between ephemeris samples
+type Interpolation int
+
+const (
+	// Lagrange polynomials through the positions and, separately, through the velocities
+	InterpolationLagrange Interpolation = iota
+	// Hermite polynomials through the positions matching the velocities, the velocity is the derivative
+	InterpolationHermite
+)
+
+func (i Interpolation) String() string {
+	switch i {
+	case InterpolationLagrange:
+		return "LAGRANGE"
+	case InterpolationHermite:
+		return "HERMITE"
+	}
+	return fmt.Sprintf("Interpolation(%d)", int(i))
+}
+
+// EphemerisState is a tabulated position (km) and velocity (km/s)
+type EphemerisState struct {
+	Time     time.Time
+	Position Vector3
+	Velocity Vector3
+}
+
+// Ephemeris interpolates time ordered states, it implements Propagator in the frame of the states
+type Ephemeris struct {
+	states []EphemerisState
+	method Interpolation
+	// number of samples used for each interpolation
+	points int
+}
+
+// Builds an ephemeris from states in strictly increasing time order.
+// The degree is that of the interpolating polynomial: Lagrange uses degree+1 samples and Hermite, which needs an odd
+// degree, uses (degree+1)/2 samples.
+func NewEphemeris(states []EphemerisState, method Interpolation, degree int) (Ephemeris, error) {
+	if degree < 1 {
+		return Ephemeris{}, fmt.Errorf("%w: degree %d", ErrInvalidEphemeris, degree)
+	}
+	var points int
+	switch method {
+	case InterpolationLagrange:
+		points = degree + 1
+	case InterpolationHermite:
+		if degree%2 == 0 {
+			return Ephemeris{}, fmt.Errorf("%w: hermite degree %d is not odd", ErrInvalidEphemeris, degree)
+		}
+		points = (degree + 1) / 2
+	default:
+		return Ephemeris{}, fmt.Errorf("%w: unknown interpolation %d", ErrInvalidEphemeris, int(method))
+	}
+	if len(states) < points {
+		return Ephemeris{}, fmt.Errorf("%w: %d states for %d samples per interpolation", ErrInvalidEphemeris, len(states), points)
+	}
+	for i := 1; i < len(states); i++ {
+		if !states[i].Time.After(states[i-1].Time) {
+			return Ephemeris{}, fmt.Errorf("%w: state %d at %v is not after %v", ErrInvalidEphemeris, i, states[i].Time, states[i-1].Time)
+		}
+	}
+	return Ephemeris{states: append([]EphemerisState(nil), states...), method: method, points: points}, nil
+}
+
+// Returns the first time covered by the ephemeris
+func (e Ephemeris) Start() time.Time {
+	return e.states[0].Time
+}
+
+// Returns the last time covered by the ephemeris
+func (e Ephemeris) Stop() time.Time {
+	return e.states[len(e.states)-1].Time
+}
+
+// Interpolates the position and velocity at the given time
+func (e Ephemeris) PositionVelocity(t time.Time) (position, velocity Vector3, err error) {
+	if len(e.states) == 0 {
+		return position, velocity, fmt.Errorf("%w: no states", ErrInvalidEphemeris)
+	}
+	if t.Before(e.Start()) || t.After(e.Stop()) {
+		return position, velocity, fmt.Errorf("%w: %v is not within %v to %v", ErrOutsideEphemeris, t, e.Start(), e.Stop())
+	}
+	// first state after t, the samples are centered on the interval holding t
+	next := sort.Search(len(e.states), func(i int) bool { return e.states[i].Time.After(t) })
+	if next > 0 && e.states[next-1].Time.Equal(t) {
+		return e.states[next-1].Position, e.states[next-1].Velocity, nil
+	}
+	first := next - e.points/2
+	if first < 0 {
+		first = 0
+	}
+	if first > len(e.states)-e.points {
+		first = len(e.states) - e.points
+	}
+	window := e.states[first : first+e.points]
+
+	// seconds from t keep the polynomials well conditioned
+	x := make([]float64, len(window))
+	for i, s := range window {
+		x[i] = s.Time.Sub(t).Seconds()
+	}
+	if e.method == InterpolationHermite {
+		position, velocity = hermite(x, window)
+		return position, velocity, nil
+	}
+	for j, s := range window {
+		weight := 1.0
+		for m := range window {
+			if m != j {
+				weight *= -x[m] / (x[j] - x[m])
+			}
+		}
+		position = position.Add(s.Position.Scale(weight))
+		velocity = velocity.Add(s.Velocity.Scale(weight))
+	}
+	return position, velocity, nil
+}
+
+// hermite evaluates at 0 the Hermite polynomial through the positions and velocities of the states at times x (s)
+// using divided differences with every node repeated
+func hermite(x []float64, states []EphemerisState) (Vector3, Vector3) {
+	n := 2 * len(states)
+	z := make([]float64, n)
+	q := make([][]Vector3, n)
+	for i := range q {
+		q[i] = make([]Vector3, n)
+		z[i] = x[i/2]
+		q[i][0] = states[i/2].Position
+		if i == 0 {
+			continue
+		}
+		if i%2 == 1 {
+			q[i][1] = states[i/2].Velocity
+		} else {
+			q[i][1] = q[i][0].Sub(q[i-1][0]).Scale(1 / (z[i] - z[i-1]))
+		}
+	}
+	for j := 2; j < n; j++ {
+		for i := j; i < n; i++ {
+			q[i][j] = q[i][j-1].Sub(q[i-1][j-1]).Scale(1 / (z[i] - z[i-j]))
+		}
+	}
+
+	// Horner's scheme for the Newton form and its derivative
+	position := q[n-1][n-1]
+	var velocity Vector3
+	for k := n - 2; k >= 0; k-- {
+		velocity = velocity.Scale(-z[k]).Add(position)
+		position = position.Scale(-z[k]).Add(q[k][k])
+	}
+	return position, velocity
+}
@@ -0,0 +1,120 @@
+package satellite
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var _ Propagator = Ephemeris{}
+
+func sampleEphemeris(t *testing.T, p Propagator, start time.Time, samples int, step time.Duration) []EphemerisState {
+	t.Helper()
+	states := make([]EphemerisState, samples)
+	for i := range states {
+		at := start.Add(time.Duration(i) * step)
+		pos, vel, err := p.PositionVelocity(at)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		states[i] = EphemerisState{Time: at, Position: pos, Velocity: vel}
+	}
+	return states
+}
+
+func TestEphemerisInterpolation(t *testing.T) {
+	epoch := time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+	pos, vel, err := KeplerianToState(KeplerianElements{SemiMajorAxis: 6800, Eccentricity: 0.01, Inclination: 0.9, RightAscensionOfAscendingNode: 0.4, ArgumentOfPerigee: 1.1, TrueAnomaly: 0.2}, GRAVITY_EARTH)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kepler, err := NewKeplerPropagator(epoch, pos, vel, GRAVITY_EARTH)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	states := sampleEphemeris(t, kepler, epoch, 100, time.Minute)
+
+	tests := []struct {
+		name   string
+		method Interpolation
+		degree int
+		posTol float64
+		velTol float64
+	}{
+		{name: "lagrange 7", method: InterpolationLagrange, degree: 7, posTol: 1e-5, velTol: 1e-7},
+		{name: "lagrange 9", method: InterpolationLagrange, degree: 9, posTol: 1e-7, velTol: 1e-9},
+		{name: "hermite 5", method: InterpolationHermite, degree: 5, posTol: 1e-5, velTol: 1e-7},
+		{name: "hermite 7", method: InterpolationHermite, degree: 7, posTol: 1e-7, velTol: 1e-9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEphemeris(states, tt.method, tt.degree)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			// midpoints, including the first and last intervals where the samples cannot be centered
+			for i := 0; i < len(states)-1; i++ {
+				at := states[i].Time.Add(30 * time.Second)
+				got, gotVel, err := e.PositionVelocity(at)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				want, wantVel, err := kepler.PositionVelocity(at)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !got.EqualsWithin(want, tt.posTol) || !gotVel.EqualsWithin(wantVel, tt.velTol) {
+					t.Fatalf("at %v got %v %v, want %v %v", at, got, gotVel, want, wantVel)
+				}
+			}
+			got, gotVel, err := e.PositionVelocity(states[10].Time)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != states[10].Position || gotVel != states[10].Velocity {
+				t.Errorf("expected the sample at a sample time")
+			}
+		})
+	}
+}
+
+func TestEphemerisBounds(t *testing.T) {
+	epoch := time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+	states := []EphemerisState{
+		{Time: epoch, Position: Vector3{X: 7000}, Velocity: Vector3{Y: 7.5}},
+		{Time: epoch.Add(time.Minute), Position: Vector3{X: 6990, Y: 450}, Velocity: Vector3{X: -0.5, Y: 7.5}},
+		{Time: epoch.Add(2 * time.Minute), Position: Vector3{X: 6960, Y: 900}, Velocity: Vector3{X: -1, Y: 7.4}},
+	}
+	e, err := NewEphemeris(states, InterpolationLagrange, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !e.Start().Equal(epoch) || !e.Stop().Equal(epoch.Add(2*time.Minute)) {
+		t.Errorf("unexpected coverage %v to %v", e.Start(), e.Stop())
+	}
+	for _, at := range []time.Time{epoch.Add(-time.Nanosecond), epoch.Add(2*time.Minute + time.Nanosecond)} {
+		if _, _, err := e.PositionVelocity(at); !errors.Is(err, ErrOutsideEphemeris) {
+			t.Errorf("expected ErrOutsideEphemeris at %v, got %v", at, err)
+		}
+	}
+	if _, _, err := e.PositionVelocity(e.Stop()); err != nil {
+		t.Errorf("unexpected error at the last sample: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		states []EphemerisState
+		method Interpolation
+		degree int
+	}{
+		{name: "too few states", states: states, method: InterpolationLagrange, degree: 3},
+		{name: "even hermite", states: states, method: InterpolationHermite, degree: 4},
+		{name: "zero degree", states: states, method: InterpolationLagrange, degree: 0},
+		{name: "unordered", states: []EphemerisState{states[1], states[0], states[2]}, method: InterpolationLagrange, degree: 1},
+		{name: "unknown method", states: states, method: Interpolation(5), degree: 1},
+	} {
+		if _, err := NewEphemeris(tt.states, tt.method, tt.degree); !errors.Is(err, ErrInvalidEphemeris) {
+			t.Errorf("%s: expected ErrInvalidEphemeris, got %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var ErrInvalidFootprint = errors.New("footprint parameters are invalid")
+var ErrFootprintOffEarth = errors.New("sensor field of view extends past the Earth's limb")
+
+// Calculates the region on the ellipsoid from which a satellite at an ECI position (km) is seen at or above a minimum
+// elevation. The boundary is returned as a closed counterclockwise ring of the given number of vertices plus the
+// closing vertex, with longitudes within -180 to 180 degrees.
+func CoverageFootprint(pos Vector3, t time.Time, minElevation Angle, vertices int) ([]Coordinates, error) {
+	if minElevation.Radians() < 0 || minElevation.Radians() >= math.Pi/2 {
+		return nil, fmt.Errorf("%w: minimum elevation %v", ErrInvalidFootprint, minElevation)
+	}
+	return footprint(pos, t, vertices, func(sat, nadir, direction Vector3) (float64, error) {
+		// the elevation seen from the ground falls from 90 degrees below the satellite to 0 at the limb
+		low, high := 0.0, math.Pi/2
+		for i := 0; i < 60; i++ {
+			angle := (low + high) / 2
+			ground, ok := ellipsoidIntersection(sat, rayDirection(nadir, direction, angle))
+			if ok && groundElevation(ground, sat) >= minElevation.Radians() {
+				low = angle
+			} else {
+				high = angle
+			}
+		}
+		return low, nil
+	})
+}
+
+// Calculates the region on the ellipsoid seen by a nadir pointing sensor with a circular field of view of the given
+// half-angle on a satellite at an ECI position (km). The boundary is returned as in CoverageFootprint.
+func SensorFootprint(pos Vector3, t time.Time, halfAngle Angle, vertices int) ([]Coordinates, error) {
+	if halfAngle.Radians() <= 0 || halfAngle.Radians() >= math.Pi/2 {
+		return nil, fmt.Errorf("%w: half-angle %v", ErrInvalidFootprint, halfAngle)
+	}
+	return footprint(pos, t, vertices, func(sat, nadir, direction Vector3) (float64, error) {
+		if _, ok := ellipsoidIntersection(sat, rayDirection(nadir, direction, halfAngle.Radians())); !ok {
+			return 0, fmt.Errorf("%w: half-angle %v", ErrFootprintOffEarth, halfAngle)
+		}
+		return halfAngle.Radians(), nil
+	})
+}
+
+// footprint intersects the ellipsoid with rays from the satellite around the geodetic nadir, boundary returns the
+// angle off nadir of the ray towards a horizontal direction
+func footprint(pos Vector3, t time.Time, vertices int, boundary func(sat, nadir, direction Vector3) (float64, error)) ([]Coordinates, error) {
+	if vertices < 3 {
+		return nil, fmt.Errorf("%w: %d vertices", ErrInvalidFootprint, vertices)
+	}
+	sat := ECIToECEF(pos, GSTimeFromDate(t))
+	if !sat.IsFinite() || ellipsoidValue(sat) <= 1 {
+		return nil, fmt.Errorf("%w: satellite is not above the ellipsoid", ErrInvalidFootprint)
+	}
+	_, subpoint := ECIToLLA(sat, 0)
+	toECEF := enuRotation(subpoint).Transpose()
+	east := toECEF.MulVec(Vector3{X: 1})
+	north := toECEF.MulVec(Vector3{Y: 1})
+	nadir := toECEF.MulVec(Vector3{Z: -1})
+
+	ring := make([]Coordinates, 0, vertices+1)
+	for i := 0; i < vertices; i++ {
+		// decreasing azimuth runs north, west, south, east: counterclockwise seen from above
+		azimuth := -TWOPI * float64(i) / float64(vertices)
+		direction := north.Scale(math.Cos(azimuth)).Add(east.Scale(math.Sin(azimuth)))
+		angle, err := boundary(sat, nadir, direction)
+		if err != nil {
+			return nil, err
+		}
+		ground, ok := ellipsoidIntersection(sat, rayDirection(nadir, direction, angle))
+		if !ok {
+			return nil, fmt.Errorf("%w: azimuth %f", ErrFootprintOffEarth, -azimuth*RAD2DEG)
+		}
+		_, coords := ECIToLLA(ground, 0)
+		coords.Longitude = Radians(wrapPi(coords.Longitude.Radians()))
+		ring = append(ring, coords)
+	}
+	return append(ring, ring[0]), nil
+}
+
+// rayDirection tilts the nadir towards a horizontal direction by an angle
+func rayDirection(nadir, direction Vector3, angle float64) Vector3 {
+	return nadir.Scale(math.Cos(angle)).Add(direction.Scale(math.Sin(angle)))
+}
+
+// ellipsoidValue is 1 on the ellipsoid used by ECIToLLA, less inside and more outside
+func ellipsoidValue(p Vector3) float64 {
+	return (p.X*p.X+p.Y*p.Y)/(EQUATOR_RADIUS*EQUATOR_RADIUS) + p.Z*p.Z/(POLAR_RADIUS*POLAR_RADIUS)
+}
+
+// ellipsoidIntersection returns the first point where a ray from an origin outside the ellipsoid meets it
+func ellipsoidIntersection(origin, direction Vector3) (Vector3, bool) {
+	// scale z so the ellipsoid becomes a sphere of the equatorial radius
+	k := EQUATOR_RADIUS / POLAR_RADIUS
+	o := Vector3{X: origin.X, Y: origin.Y, Z: origin.Z * k}
+	d := Vector3{X: direction.X, Y: direction.Y, Z: direction.Z * k}
+
+	a := d.Dot(d)
+	b := 2 * o.Dot(d)
+	c := o.Dot(o) - EQUATOR_RADIUS*EQUATOR_RADIUS
+	discriminant := b*b - 4*a*c
+	if discriminant < 0 {
+		return Vector3{}, false
+	}
+	distance := (-b - math.Sqrt(discriminant)) / (2 * a)
+	if distance < 0 {
+		return Vector3{}, false
+	}
+	return origin.Add(direction.Scale(distance)), true
+}
+
+// groundElevation returns the elevation (rad) of a target seen from a point on the ellipsoid, against the ellipsoid
+// normal
+func groundElevation(ground, target Vector3) float64 {
+	up := Vector3{
+		X: ground.X / (EQUATOR_RADIUS * EQUATOR_RADIUS),
+		Y: ground.Y / (EQUATOR_RADIUS * EQUATOR_RADIUS),
+		Z: ground.Z / (POLAR_RADIUS * POLAR_RADIUS),
+	}.Unit()
+	return math.Asin(up.Dot(target.Sub(ground).Unit()))
+}
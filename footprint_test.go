@@ -0,0 +1,90 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+var footprintEpoch = time.Date(2024, 3, 20, 6, 30, 0, 0, time.UTC)
+
+func TestCoverageFootprint(t *testing.T) {
+	grav, err := getGravConst(GravityWGS84)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gmst := GSTimeFromDate(footprintEpoch)
+	sat := GeodeticToECEF(Geodetic(48, 10, 700), grav)
+	pos := ECEFToECI(sat, gmst)
+
+	for _, minElevation := range []float64{0, 10, 30} {
+		ring, err := CoverageFootprint(pos, footprintEpoch, Degrees(minElevation), 36)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ring) != 37 || ring[0] != ring[36] {
+			t.Fatalf("expected a closed ring of 37 vertices, got %d", len(ring))
+		}
+		for _, vertex := range ring {
+			if math.Abs(vertex.Altitude.Kilometers()) > 1e-6 {
+				t.Errorf("vertex %v is not on the ellipsoid", vertex)
+			}
+			enu, _, err := ECEFToTopocentric(FrameENU, vertex, grav, sat, Vector3{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			look, err := TopocentricToLookAngles(FrameENU, enu)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !closeFloatWithin(look.Elevation.Degrees(), minElevation, 1e-5) {
+				t.Errorf("elevation from %v is %f, want %f", vertex, look.Elevation.Degrees(), minElevation)
+			}
+		}
+	}
+}
+
+func TestCoverageFootprintIsCounterclockwise(t *testing.T) {
+	ring, err := CoverageFootprint(Vector3{X: 7000}, footprintEpoch, Degrees(5), 8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// shoelace area in longitude and latitude is positive for counterclockwise rings
+	area := 0.0
+	for i := 0; i < len(ring)-1; i++ {
+		area += ring[i].Longitude.Degrees()*ring[i+1].Latitude.Degrees() - ring[i+1].Longitude.Degrees()*ring[i].Latitude.Degrees()
+	}
+	if area <= 0 {
+		t.Errorf("expected a counterclockwise ring, got signed area %f", area)
+	}
+}
+
+func TestSensorFootprint(t *testing.T) {
+	grav, err := getGravConst(GravityWGS84)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gmst := GSTimeFromDate(footprintEpoch)
+	subpoint := Geodetic(-20, 170, 0)
+	sat := GeodeticToECEF(Geodetic(-20, 170, 500), grav)
+	nadir := GeodeticToECEF(subpoint, grav).Sub(sat)
+
+	ring, err := SensorFootprint(ECEFToECI(sat, gmst), footprintEpoch, Degrees(20), 24)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, vertex := range ring {
+		ground := GeodeticToECEF(vertex, grav)
+		if angle := ground.Sub(sat).Angle(nadir) * RAD2DEG; !closeFloatWithin(angle, 20, 1e-6) {
+			t.Errorf("vertex %v is %f degrees off nadir, want 20", vertex, angle)
+		}
+	}
+
+	if _, err := SensorFootprint(ECEFToECI(sat, gmst), footprintEpoch, Degrees(80), 24); !errors.Is(err, ErrFootprintOffEarth) {
+		t.Errorf("expected ErrFootprintOffEarth, got %v", err)
+	}
+	if _, err := SensorFootprint(ECEFToECI(sat, gmst), footprintEpoch, Degrees(20), 2); !errors.Is(err, ErrInvalidFootprint) {
+		t.Errorf("expected ErrInvalidFootprint, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ForceModel returns an acceleration in km/s^2 for an Earth centered inertial position (km) and velocity (km/s)
+type ForceModel interface {
+	Acceleration(t time.Time, pos, vel Vector3) (Vector3, error)
+}
+
+// Atmosphere returns the mass density in kg/m^3 at an Earth centered inertial position in km
+type Atmosphere interface {
+	Density(pos Vector3, t time.Time) (float64, error)
+}
+
+// Drag from an atmosphere rotating with the Earth
+type Drag struct {
+	Atmosphere Atmosphere
+	// Cd*A/m in m^2/kg
+	BallisticCoefficient float64
+}
+
+func (d Drag) Acceleration(t time.Time, pos, vel Vector3) (Vector3, error) {
+	rho, err := d.Atmosphere.Density(pos, t)
+	if err != nil {
+		return Vector3{}, fmt.Errorf("density: %w", err)
+	}
+	relative := Vector3{
+		X: vel.X + EARTH_ANGULAR_VELOCITY*pos.Y,
+		Y: vel.Y - EARTH_ANGULAR_VELOCITY*pos.X,
+		Z: vel.Z,
+	}
+	// 0.5 rho B v^2 in m/s^2 with v in km/s is 0.5 rho B v^2 * 1e6, back to km/s^2 divides by 1e3
+	k := -0.5 * rho * d.BallisticCoefficient * relative.Norm() * 1e3
+	return relative.Scale(k), nil
+}
+
+// Solar radiation pressure on a flat plate facing the Sun with a cylindrical Earth shadow
+type SolarRadiationPressure struct {
+	// Cr*A/m in m^2/kg
+	Coefficient float64
+}
+
+func (s SolarRadiationPressure) Acceleration(t time.Time, pos, vel Vector3) (Vector3, error) {
+	if InEarthShadow(pos, t) {
+		return Vector3{}, nil
+	}
+	sun := SunPosition(t)
+	fromSun := pos.Sub(sun)
+	distance := fromSun.Norm()
+	// pressure scales with the inverse square of the distance in AU, m/s^2 to km/s^2
+	k := SOLAR_PRESSURE * s.Coefficient * (ASTRONOMICAL_UNIT / distance) * (ASTRONOMICAL_UNIT / distance) / 1e3
+	return fromSun.Scale(k / distance), nil
+}
+
+// Point mass perturbation of a third body on an Earth orbiting satellite
+type ThirdBody struct {
+	Mu       float64
+	Position func(t time.Time) Vector3
+}
+
+var SunThirdBody = ThirdBody{Mu: GRAVITY_SUN, Position: SunPosition}
+var MoonThirdBody = ThirdBody{Mu: GRAVITY_MOON, Position: MoonPosition}
+
+func (b ThirdBody) Acceleration(t time.Time, pos, vel Vector3) (Vector3, error) {
+	body := b.Position(t)
+	toBody := body.Sub(pos)
+	dSat := toBody.Norm()
+	dEarth := body.Norm()
+	return toBody.Scale(b.Mu / (dSat * dSat * dSat)).Add(body.Scale(-b.Mu / (dEarth * dEarth * dEarth))), nil
+}
+
+// exponentialAtmosphereTable holds base altitude (km), nominal density (kg/m^3) and scale height (km)
+var exponentialAtmosphereTable = [][3]float64{
+	{0, 1.225, 7.249},
+	{25, 3.899e-2, 6.349},
+	{30, 1.774e-2, 6.682},
+	{40, 3.972e-3, 7.554},
+	{50, 1.057e-3, 8.382},
+	{60, 3.206e-4, 7.714},
+	{70, 8.770e-5, 6.549},
+	{80, 1.905e-5, 5.799},
+	{90, 3.396e-6, 5.382},
+	{100, 5.297e-7, 5.877},
+	{110, 9.661e-8, 7.263},
+	{120, 2.438e-8, 9.473},
+	{130, 8.484e-9, 12.636},
+	{140, 3.845e-9, 16.149},
+	{150, 2.070e-9, 22.523},
+	{180, 5.464e-10, 29.740},
+	{200, 2.789e-10, 37.105},
+	{250, 7.248e-11, 45.546},
+	{300, 2.418e-11, 53.628},
+	{350, 9.518e-12, 53.298},
+	{400, 3.725e-12, 58.515},
+	{450, 1.585e-12, 60.828},
+	{500, 6.967e-13, 63.822},
+	{600, 1.454e-13, 71.835},
+	{700, 3.614e-14, 88.667},
+	{800, 1.170e-14, 124.64},
+	{900, 5.245e-15, 181.05},
+	{1000, 3.019e-15, 268.00},
+}
+
+// ExponentialAtmosphere is a static piecewise exponential density model
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, table 8-4.
+type ExponentialAtmosphere struct{}
+
+func (ExponentialAtmosphere) Density(pos Vector3, t time.Time) (float64, error) {
+	_, lla := ECIToLLA(pos, 0)
+	altitude := lla.Altitude.Kilometers()
+	if !isFinite(altitude) {
+		return 0, fmt.Errorf("%w: altitude is %f", ErrNonFiniteValue, altitude)
+	}
+	if altitude < 0 {
+		altitude = 0
+	}
+	i := len(exponentialAtmosphereTable) - 1
+	for i > 0 && altitude < exponentialAtmosphereTable[i][0] {
+		i--
+	}
+	row := exponentialAtmosphereTable[i]
+	return row[1] * math.Exp(-(altitude-row[0])/row[2]), nil
+}
@@ -0,0 +1,174 @@
+package satellite
+
+import (
+	"fmt"
+)
+
+// LocalFrame is an orbital frame attached to a position and velocity
+type LocalFrame int
+
+const (
+	// Radial, in-track and cross-track, the same axes as RTN
+	FrameRIC LocalFrame = iota
+	// Radial along the position, normal along the angular momentum and transverse completing the triad
+	FrameRTN
+	// Local vertical local horizontal: z to nadir, y against the angular momentum and x completing the triad, close
+	// to the velocity
+	FrameLVLH
+	// Velocity, normal along the angular momentum and co-normal completing the triad
+	FrameVNC
+)
+
+func (f LocalFrame) String() string {
+	switch f {
+	case FrameRIC:
+		return "RIC"
+	case FrameRTN:
+		return "RTN"
+	case FrameLVLH:
+		return "LVLH"
+	case FrameVNC:
+		return "VNC"
+	}
+	return fmt.Sprintf("LocalFrame(%d)", int(f))
+}
+
+// Returns the rotation from ECI to the local frame of a position and velocity, the rows are the frame axes in ECI
+func LocalFrameRotation(frame LocalFrame, pos, vel Vector3) (Matrix3, error) {
+	r := pos.Norm()
+	h := pos.Cross(vel)
+	hMag := h.Norm()
+	if r == 0 || hMag == 0 {
+		return Matrix3{}, fmt.Errorf("%w: radius %f angular momentum %f", ErrDegenerateState, r, hMag)
+	}
+	radial := pos.Scale(1 / r)
+	normal := h.Scale(1 / hMag)
+
+	var x, y, z Vector3
+	switch frame {
+	case FrameRIC, FrameRTN:
+		x, y, z = radial, normal.Cross(radial), normal
+	case FrameLVLH:
+		z = radial.Scale(-1)
+		y = normal.Scale(-1)
+		x = y.Cross(z)
+	case FrameVNC:
+		x = vel.Unit()
+		y = normal
+		z = x.Cross(y)
+	default:
+		return Matrix3{}, fmt.Errorf("unknown local frame %d", int(frame))
+	}
+	return Matrix3{
+		{x.X, x.Y, x.Z},
+		{y.X, y.Y, y.Z},
+		{z.X, z.Y, z.Z},
+	}, nil
+}
+
+// Returns the rotation from ECI to the radial, in-track and cross-track frame
+func RICRotation(pos, vel Vector3) (Matrix3, error) {
+	return LocalFrameRotation(FrameRIC, pos, vel)
+}
+
+// Returns the rotation from ECI to the radial, transverse and normal frame
+func RTNRotation(pos, vel Vector3) (Matrix3, error) {
+	return LocalFrameRotation(FrameRTN, pos, vel)
+}
+
+// Returns the rotation from ECI to the local vertical local horizontal frame
+func LVLHRotation(pos, vel Vector3) (Matrix3, error) {
+	return LocalFrameRotation(FrameLVLH, pos, vel)
+}
+
+// Returns the rotation from ECI to the velocity, normal and co-normal frame
+func VNCRotation(pos, vel Vector3) (Matrix3, error) {
+	return LocalFrameRotation(FrameVNC, pos, vel)
+}
+
+// Expresses an ECI vector, e.g. a position difference, in the local frame of a position and velocity
+func ECIToLocal(frame LocalFrame, pos, vel, v Vector3) (Vector3, error) {
+	rotation, err := LocalFrameRotation(frame, pos, vel)
+	if err != nil {
+		return Vector3{}, err
+	}
+	return rotation.MulVec(v), nil
+}
+
+// Expresses a local frame vector in ECI
+func LocalToECI(frame LocalFrame, pos, vel, v Vector3) (Vector3, error) {
+	rotation, err := LocalFrameRotation(frame, pos, vel)
+	if err != nil {
+		return Vector3{}, err
+	}
+	return rotation.Transpose().MulVec(v), nil
+}
+
+// Rotates a 6x6 position and velocity covariance from ECI to the local frame.
+// Both blocks use the same rotation, the rotation rate of the frame is not applied, as in CCSDS CDM covariances.
+func CovarianceECIToLocal(frame LocalFrame, pos, vel Vector3, cov [6][6]float64) ([6][6]float64, error) {
+	rotation, err := LocalFrameRotation(frame, pos, vel)
+	if err != nil {
+		return [6][6]float64{}, err
+	}
+	return rotateCovariance(rotation, cov), nil
+}
+
+// Rotates a 6x6 position and velocity covariance from the local frame to ECI
+func CovarianceLocalToECI(frame LocalFrame, pos, vel Vector3, cov [6][6]float64) ([6][6]float64, error) {
+	rotation, err := LocalFrameRotation(frame, pos, vel)
+	if err != nil {
+		return [6][6]float64{}, err
+	}
+	return rotateCovariance(rotation.Transpose(), cov), nil
+}
+
+// Returns the upper left 3x3 position block of a 6x6 covariance
+func PositionCovariance(cov [6][6]float64) Matrix3 {
+	var position Matrix3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			position[i][j] = cov[i][j]
+		}
+	}
+	return position
+}
+
+// rotateCovariance returns R C R^T with R applied to both the position and velocity blocks
+func rotateCovariance(rotation Matrix3, cov [6][6]float64) [6][6]float64 {
+	var full [6][6]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			full[i][j] = rotation[i][j]
+			full[i+3][j+3] = rotation[i][j]
+		}
+	}
+	var temp, result [6][6]float64
+	for i := 0; i < 6; i++ {
+		for j := 0; j < 6; j++ {
+			for k := 0; k < 6; k++ {
+				temp[i][j] += full[i][k] * cov[k][j]
+			}
+		}
+	}
+	for i := 0; i < 6; i++ {
+		for j := 0; j < 6; j++ {
+			for k := 0; k < 6; k++ {
+				result[i][j] += temp[i][k] * full[j][k]
+			}
+		}
+	}
+	return result
+}
+
+// Returns the position and velocity of a state relative to a reference state, in the reference's local frame.
+// The velocity difference is rotated without the frame rotation rate, as for covariances.
+func RelativeStateLocal(frame LocalFrame, refPos, refVel, pos, vel Vector3) (relPos, relVel Vector3, err error) {
+	rotation, err := LocalFrameRotation(frame, refPos, refVel)
+	if err != nil {
+		return relPos, relVel, err
+	}
+	relPos = rotation.MulVec(pos.Sub(refPos))
+	relVel = rotation.MulVec(vel.Sub(refVel))
+	return relPos, relVel, nil
+}
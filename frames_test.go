@@ -0,0 +1,149 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+var (
+	framePos = Vector3{X: -4400.594, Y: 1932.870, Z: 4760.712}
+	frameVel = Vector3{X: -5.498, Y: -5.387, Z: -2.923}
+)
+
+func TestLocalFrameRotation(t *testing.T) {
+	r := framePos.Norm()
+	v := frameVel.Norm()
+	tests := []struct {
+		frame    LocalFrame
+		position Vector3
+	}{
+		{FrameRIC, Vector3{X: r}},
+		{FrameRTN, Vector3{X: r}},
+		{FrameLVLH, Vector3{Z: -r}},
+		{FrameVNC, Vector3{}},
+	}
+	for _, test := range tests {
+		rotation, err := LocalFrameRotation(test.frame, framePos, frameVel)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// orthonormal and right handed
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				sum := 0.0
+				for k := 0; k < 3; k++ {
+					sum += rotation[i][k] * rotation[j][k]
+				}
+				expected := 0.0
+				if i == j {
+					expected = 1
+				}
+				if math.Abs(sum-expected) > 1e-12 {
+					t.Errorf("%v: rotation is not orthonormal", test.frame)
+				}
+			}
+		}
+		x := Vector3{X: rotation[0][0], Y: rotation[0][1], Z: rotation[0][2]}
+		y := Vector3{X: rotation[1][0], Y: rotation[1][1], Z: rotation[1][2]}
+		z := Vector3{X: rotation[2][0], Y: rotation[2][1], Z: rotation[2][2]}
+		if x.Cross(y).Dot(z) < 0.999999 {
+			t.Errorf("%v: rotation is not right handed", test.frame)
+		}
+
+		local, err := ECIToLocal(test.frame, framePos, frameVel, framePos)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if test.frame != FrameVNC && !local.Equals(test.position) {
+			t.Errorf("%v: expected position %v, got %v", test.frame, test.position, local)
+		}
+		back, err := LocalToECI(test.frame, framePos, frameVel, local)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !back.Equals(framePos) {
+			t.Errorf("%v: round trip expected %v, got %v", test.frame, framePos, back)
+		}
+
+		velocity, err := ECIToLocal(test.frame, framePos, frameVel, frameVel)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		switch test.frame {
+		case FrameVNC:
+			if !velocity.Equals(Vector3{X: v}) {
+				t.Errorf("VNC: expected velocity along x, got %v", velocity)
+			}
+		case FrameLVLH:
+			if velocity.X <= 0 || math.Abs(velocity.Y) > 1e-9 {
+				t.Errorf("LVLH: expected velocity in +x and the orbit plane, got %v", velocity)
+			}
+		default:
+			if velocity.Y <= 0 || math.Abs(velocity.Z) > 1e-9 {
+				t.Errorf("%v: expected in-track velocity in the orbit plane, got %v", test.frame, velocity)
+			}
+		}
+	}
+}
+
+func TestCovarianceRotation(t *testing.T) {
+	var cov [6][6]float64
+	values := []float64{4, 1, 0.25, 1e-4, 4e-4, 1e-6}
+	for i, value := range values {
+		cov[i][i] = value
+	}
+	cov[0][1], cov[1][0] = 0.5, 0.5
+	cov[0][4], cov[4][0] = 1e-3, 1e-3
+
+	for _, frame := range []LocalFrame{FrameRIC, FrameRTN, FrameLVLH, FrameVNC} {
+		local, err := CovarianceECIToLocal(frame, framePos, frameVel, cov)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		traceECI, traceLocal := 0.0, 0.0
+		for i := 0; i < 3; i++ {
+			traceECI += cov[i][i]
+			traceLocal += local[i][i]
+		}
+		if math.Abs(traceECI-traceLocal) > 1e-12 {
+			t.Errorf("%v: position trace changed from %g to %g", frame, traceECI, traceLocal)
+		}
+		back, err := CovarianceLocalToECI(frame, framePos, frameVel, local)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := 0; i < 6; i++ {
+			for j := 0; j < 6; j++ {
+				if math.Abs(back[i][j]-cov[i][j]) > 1e-12 {
+					t.Errorf("%v: round trip differs at %d,%d: %g and %g", frame, i, j, cov[i][j], back[i][j])
+				}
+			}
+		}
+	}
+
+	position := PositionCovariance(cov)
+	if position[0][1] != 0.5 || position[2][2] != 0.25 {
+		t.Errorf("unexpected position covariance %v", position)
+	}
+}
+
+func TestRelativeStateLocal(t *testing.T) {
+	// a point 1 km higher and 2 km ahead
+	rotation, err := RTNRotation(framePos, frameVel)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	offset := rotation.Transpose().MulVec(Vector3{X: 1, Y: 2})
+	relPos, relVel, err := RelativeStateLocal(FrameRTN, framePos, frameVel, framePos.Add(offset), frameVel)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !relPos.Equals(Vector3{X: 1, Y: 2}) || !relVel.Equals(Vector3{}) {
+		t.Errorf("unexpected relative state %v %v", relPos, relVel)
+	}
+
+	if _, err := RTNRotation(framePos, framePos.Scale(0.001)); !errors.Is(err, ErrDegenerateState) {
+		t.Errorf("expected ErrDegenerateState, got %v", err)
+	}
+}
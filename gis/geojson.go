@@ -0,0 +1,106 @@
+package gis
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+)
+
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+type Feature struct {
+	Type       string         `json:"type"`
+	Geometry   Geometry       `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+type Geometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+// Returns a ground track as a MultiLineString with one line per segment.
+// The properties hold the name, the start and end of the track and the time of every vertex in coordTimes.
+func GroundTrackFeature(name string, segments []satellite.GroundTrackSegment) Feature {
+	lines := make([][]point, 0, len(segments))
+	times := make([][]string, 0, len(segments))
+	var start, end time.Time
+	for _, segment := range segments {
+		if len(segment) == 0 {
+			continue
+		}
+		line := make([]point, len(segment))
+		lineTimes := make([]string, len(segment))
+		for i, p := range segment {
+			line[i] = toPoint(p.Coordinates)
+			lineTimes[i] = formatTime(p.Time)
+		}
+		if start.IsZero() {
+			start = segment[0].Time
+		}
+		end = segment[len(segment)-1].Time
+		lines = append(lines, line)
+		times = append(times, lineTimes)
+	}
+	return Feature{
+		Type:     "Feature",
+		Geometry: Geometry{Type: "MultiLineString", Coordinates: lines},
+		Properties: map[string]any{
+			"name":       name,
+			"start":      formatTime(start),
+			"end":        formatTime(end),
+			"coordTimes": times,
+		},
+	}
+}
+
+// Returns a footprint ring, e.g. from satellite.CoverageFootprint, as a Polygon, or a MultiPolygon when it crosses the
+// antimeridian. The properties hold the name and the time of the footprint.
+func FootprintFeature(name string, ring []satellite.Coordinates, t time.Time) Feature {
+	rings := splitPolygon(ring)
+	geometry := Geometry{Type: "MultiPolygon"}
+	if len(rings) == 1 {
+		geometry = Geometry{Type: "Polygon", Coordinates: [][]point{rings[0]}}
+	} else {
+		polygons := make([][][]point, len(rings))
+		for i, r := range rings {
+			polygons[i] = [][]point{r}
+		}
+		geometry.Coordinates = polygons
+	}
+	return Feature{
+		Type:     "Feature",
+		Geometry: geometry,
+		Properties: map[string]any{
+			"name": name,
+			"time": formatTime(t),
+		},
+	}
+}
+
+// Returns a ground station as a Point with its altitude in meters
+func StationFeature(name string, station satellite.Coordinates) Feature {
+	return Feature{
+		Type: "Feature",
+		Geometry: Geometry{
+			Type:        "Point",
+			Coordinates: []float64{station.Longitude.Degrees(), station.Latitude.Degrees(), station.Altitude.Meters()},
+		},
+		Properties: map[string]any{"name": name},
+	}
+}
+
+// Writes features as a GeoJSON FeatureCollection
+func WriteGeoJSON(w io.Writer, features ...Feature) error {
+	if features == nil {
+		features = []Feature{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(FeatureCollection{Type: "FeatureCollection", Features: features})
+}
@@ -0,0 +1,112 @@
+// Package gis writes ground tracks, coverage footprints and ground stations as GeoJSON (RFC 7946) and KML 2.2 so GIS
+// tools such as QGIS and Google Earth can display and animate them.
+//
+// Longitudes and latitudes are written in degrees and altitudes in meters. Lines and polygons are split where they
+// cross ±180 degrees longitude.
+package gis
+
+import (
+	"math"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+)
+
+// point is a longitude and latitude in degrees
+type point [2]float64
+
+func toPoint(c satellite.Coordinates) point {
+	return point{c.Longitude.Degrees(), c.Latitude.Degrees()}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// splitPolygon splits a closed ring of coordinates into rings within -180 to 180 degrees longitude, closing rings
+// that enclose a pole along the pole
+func splitPolygon(ring []satellite.Coordinates) [][]point {
+	if len(ring) < 3 {
+		return nil
+	}
+	// unwrap the longitudes so consecutive vertices are less than 180 degrees apart
+	unwrapped := make([]point, len(ring))
+	unwrapped[0] = toPoint(ring[0])
+	meanLatitude := 0.0
+	for i := 1; i < len(ring); i++ {
+		p := toPoint(ring[i])
+		p[0] = unwrapped[i-1][0] + math.Remainder(p[0]-unwrapped[i-1][0], 360)
+		unwrapped[i] = p
+		meanLatitude += p[1]
+	}
+	if first, last := unwrapped[0], unwrapped[len(unwrapped)-1]; first != last {
+		if math.Abs(last[0]-first[0]) > 180 {
+			// the ring winds around a pole
+			pole := math.Copysign(90, meanLatitude)
+			unwrapped = append(unwrapped, point{last[0], pole}, point{first[0], pole})
+		}
+		unwrapped = append(unwrapped, first)
+	}
+
+	minLon, maxLon := math.Inf(1), math.Inf(-1)
+	for _, p := range unwrapped {
+		minLon = math.Min(minLon, p[0])
+		maxLon = math.Max(maxLon, p[0])
+	}
+	var rings [][]point
+	for k := math.Floor((minLon + 180) / 360); k <= math.Floor((maxLon+180)/360); k++ {
+		west, east := -180+360*k, 180+360*k
+		clipped := clip(clip(unwrapped, west, false), east, true)
+		if len(clipped) < 4 {
+			continue
+		}
+		for i := range clipped {
+			clipped[i][0] -= 360 * k
+		}
+		area := signedArea(clipped)
+		if math.Abs(area) < 1e-12 {
+			continue
+		}
+		if area < 0 {
+			for i, j := 0, len(clipped)-1; i < j; i, j = i+1, j-1 {
+				clipped[i], clipped[j] = clipped[j], clipped[i]
+			}
+		}
+		rings = append(rings, clipped)
+	}
+	return rings
+}
+
+// clip keeps the part of a closed ring west (or east) of a meridian
+func clip(ring []point, meridian float64, keepWest bool) []point {
+	inside := func(p point) bool {
+		if keepWest {
+			return p[0] <= meridian
+		}
+		return p[0] >= meridian
+	}
+	var out []point
+	for i := 0; i < len(ring)-1; i++ {
+		a, b := ring[i], ring[i+1]
+		if inside(a) {
+			out = append(out, a)
+		}
+		if inside(a) != inside(b) && a[0] != meridian && b[0] != meridian {
+			fraction := (meridian - a[0]) / (b[0] - a[0])
+			out = append(out, point{meridian, a[1] + fraction*(b[1]-a[1])})
+		}
+	}
+	if len(out) > 0 {
+		out = append(out, out[0])
+	}
+	return out
+}
+
+// signedArea is the shoelace area of a closed ring, positive when counterclockwise
+func signedArea(ring []point) float64 {
+	area := 0.0
+	for i := 0; i < len(ring)-1; i++ {
+		area += ring[i][0]*ring[i+1][1] - ring[i+1][0]*ring[i][1]
+	}
+	return area / 2
+}
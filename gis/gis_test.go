@@ -0,0 +1,208 @@
+package gis
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+	"github.com/infostellarinc/go-satellite/internal/testsat"
+)
+
+var testEpoch = time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+
+func ring(points ...[2]float64) []satellite.Coordinates {
+	coords := make([]satellite.Coordinates, 0, len(points)+1)
+	for _, p := range points {
+		coords = append(coords, satellite.Geodetic(p[1], p[0], 0))
+	}
+	return append(coords, coords[0])
+}
+
+func totalArea(rings [][]point) float64 {
+	area := 0.0
+	for _, r := range rings {
+		area += signedArea(r)
+	}
+	return area
+}
+
+func TestSplitPolygon(t *testing.T) {
+	tests := []struct {
+		name      string
+		ring      []satellite.Coordinates
+		wantRings int
+		wantArea  float64
+	}{
+		{
+			name:      "inside",
+			ring:      ring([2]float64{10, -5}, [2]float64{20, -5}, [2]float64{20, 5}, [2]float64{10, 5}),
+			wantRings: 1,
+			wantArea:  100,
+		},
+		{
+			name:      "crossing",
+			ring:      ring([2]float64{170, -10}, [2]float64{-170, -10}, [2]float64{-170, 10}, [2]float64{170, 10}),
+			wantRings: 2,
+			wantArea:  400,
+		},
+		{
+			name:      "clockwise",
+			ring:      ring([2]float64{170, 10}, [2]float64{-170, 10}, [2]float64{-170, -10}, [2]float64{170, -10}),
+			wantRings: 2,
+			wantArea:  400,
+		},
+		{
+			name:      "north pole",
+			ring:      ring([2]float64{0, 70}, [2]float64{90, 70}, [2]float64{180, 70}, [2]float64{-90, 70}),
+			wantRings: 2,
+			wantArea:  360 * 20,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rings := splitPolygon(tt.ring)
+			if len(rings) != tt.wantRings {
+				t.Fatalf("expected %d rings, got %d: %v", tt.wantRings, len(rings), rings)
+			}
+			for _, r := range rings {
+				if r[0] != r[len(r)-1] {
+					t.Errorf("ring %v is not closed", r)
+				}
+				for _, p := range r {
+					if p[0] < -180 || p[0] > 180 {
+						t.Errorf("longitude %f out of range", p[0])
+					}
+				}
+			}
+			if area := totalArea(rings); math.Abs(area-tt.wantArea) > 1e-9 {
+				t.Errorf("expected area %f, got %f", tt.wantArea, area)
+			}
+		})
+	}
+}
+
+// fixedPosition returns the ECI position at the test epoch of an Earth fixed point on the x axis
+func fixedPosition(x float64) satellite.Vector3 {
+	return satellite.ECEFToECI(satellite.Vector3{X: x}, satellite.GSTimeFromDate(testEpoch))
+}
+
+func testGroundTrack(t *testing.T) []satellite.GroundTrackSegment {
+	t.Helper()
+	sat := testsat.ISS(t, satellite.TLEToSat, satellite.GravityWGS72)
+	segments, err := satellite.GroundTrack(sat, testEpoch, testEpoch.Add(3*time.Hour), time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return segments
+}
+
+func TestWriteGeoJSON(t *testing.T) {
+	segments := testGroundTrack(t)
+	footprint, err := satellite.CoverageFootprint(fixedPosition(-6900), testEpoch, satellite.Degrees(5), 36)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = WriteGeoJSON(&buf,
+		GroundTrackFeature("ISS", segments),
+		FootprintFeature("ISS coverage", footprint, testEpoch),
+		StationFeature("Tokyo", satellite.Geodetic(35.6762, 139.6503, 0.04)),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.Type != "FeatureCollection" || len(decoded.Features) != 3 {
+		t.Fatalf("expected a collection of 3 features, got %s with %d", decoded.Type, len(decoded.Features))
+	}
+
+	track := decoded.Features[0]
+	var lines [][][2]float64
+	var times [][]string
+	if err := json.Unmarshal(track.Geometry.Coordinates, &lines); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal(track.Properties["coordTimes"], &times); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if track.Geometry.Type != "MultiLineString" || len(lines) != len(segments) || len(times) != len(segments) {
+		t.Fatalf("expected a MultiLineString of %d lines, got %s of %d", len(segments), track.Geometry.Type, len(lines))
+	}
+	for i := range lines {
+		if len(lines[i]) != len(times[i]) {
+			t.Errorf("line %d has %d vertices and %d times", i, len(lines[i]), len(times[i]))
+		}
+	}
+
+	if typ := decoded.Features[1].Geometry.Type; typ != "MultiPolygon" {
+		t.Errorf("expected the footprint over the antimeridian to be a MultiPolygon, got %s", typ)
+	}
+	var station []float64
+	if err := json.Unmarshal(decoded.Features[2].Geometry.Coordinates, &station); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(station) != 3 || station[2] != 40 {
+		t.Errorf("expected a station at 40 m, got %v", station)
+	}
+}
+
+func TestWriteKML(t *testing.T) {
+	segments := testGroundTrack(t)
+	footprint, err := satellite.CoverageFootprint(fixedPosition(6900), testEpoch, satellite.Degrees(5), 36)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = WriteKML(&buf, "passes",
+		GroundTrackPlacemark("ISS", segments),
+		FootprintPlacemark("ISS coverage", footprint, testEpoch),
+		StationPlacemark("Tokyo", satellite.Geodetic(35.6762, 139.6503, 0.04)),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Errorf("expected an xml header")
+	}
+
+	var doc kmlDocument
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc.Name != "passes" || len(doc.Placemarks) != 3 {
+		t.Fatalf("expected 3 placemarks in passes, got %d in %s", len(doc.Placemarks), doc.Name)
+	}
+	track := doc.Placemarks[0]
+	if track.MultiGeometry == nil || len(track.MultiGeometry.LineStrings) != len(segments) || track.TimeSpan == nil {
+		t.Fatalf("expected %d timed line strings, got %+v", len(segments), track)
+	}
+	if track.TimeSpan.Begin != "2020-05-23T00:00:00Z" || track.TimeSpan.End != "2020-05-23T03:00:00Z" {
+		t.Errorf("unexpected time span %+v", track.TimeSpan)
+	}
+	if fp := doc.Placemarks[1]; fp.MultiGeometry == nil || len(fp.MultiGeometry.Polygons) != 1 || fp.TimeStamp == nil {
+		t.Errorf("expected one timed polygon, got %+v", fp)
+	}
+	if station := doc.Placemarks[2]; station.Point == nil || !strings.HasSuffix(station.Point.Coordinates, ",40") {
+		t.Errorf("expected a station at 40 m, got %+v", station.Point)
+	}
+}
@@ -0,0 +1,129 @@
+package gis
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+)
+
+const kmlNamespace = "http://www.opengis.net/kml/2.2"
+
+type kmlDocument struct {
+	XMLName    xml.Name    `xml:"kml"`
+	Namespace  string      `xml:"xmlns,attr"`
+	Name       string      `xml:"Document>name"`
+	Placemarks []Placemark `xml:"Document>Placemark"`
+}
+
+type Placemark struct {
+	Name          string         `xml:"name"`
+	TimeStamp     *TimeStamp     `xml:"TimeStamp,omitempty"`
+	TimeSpan      *TimeSpan      `xml:"TimeSpan,omitempty"`
+	Point         *Point         `xml:"Point,omitempty"`
+	MultiGeometry *MultiGeometry `xml:"MultiGeometry,omitempty"`
+}
+
+type TimeStamp struct {
+	When string `xml:"when"`
+}
+
+type TimeSpan struct {
+	Begin string `xml:"begin"`
+	End   string `xml:"end"`
+}
+
+type Point struct {
+	AltitudeMode string `xml:"altitudeMode,omitempty"`
+	Coordinates  string `xml:"coordinates"`
+}
+
+type MultiGeometry struct {
+	LineStrings []LineString `xml:"LineString"`
+	Polygons    []Polygon    `xml:"Polygon"`
+}
+
+type LineString struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"coordinates"`
+}
+
+type Polygon struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"outerBoundaryIs>LinearRing>coordinates"`
+}
+
+// formatCoordinates writes points as KML longitude,latitude tuples
+func formatCoordinates(points []point) string {
+	tuples := make([]string, len(points))
+	for i, p := range points {
+		tuples[i] = strconv.FormatFloat(p[0], 'f', -1, 64) + "," + strconv.FormatFloat(p[1], 'f', -1, 64)
+	}
+	return strings.Join(tuples, " ")
+}
+
+// Returns a ground track as a placemark with one line per segment, shown between the start and end of the track
+func GroundTrackPlacemark(name string, segments []satellite.GroundTrackSegment) Placemark {
+	placemark := Placemark{Name: name, MultiGeometry: &MultiGeometry{}}
+	var start, end time.Time
+	for _, segment := range segments {
+		if len(segment) == 0 {
+			continue
+		}
+		line := make([]point, len(segment))
+		for i, p := range segment {
+			line[i] = toPoint(p.Coordinates)
+		}
+		if start.IsZero() {
+			start = segment[0].Time
+		}
+		end = segment[len(segment)-1].Time
+		placemark.MultiGeometry.LineStrings = append(placemark.MultiGeometry.LineStrings, LineString{
+			Tessellate:  1,
+			Coordinates: formatCoordinates(line),
+		})
+	}
+	placemark.TimeSpan = &TimeSpan{Begin: formatTime(start), End: formatTime(end)}
+	return placemark
+}
+
+// Returns a footprint ring, e.g. from satellite.CoverageFootprint, as a placemark stamped with the time of the footprint
+func FootprintPlacemark(name string, ring []satellite.Coordinates, t time.Time) Placemark {
+	placemark := Placemark{Name: name, TimeStamp: &TimeStamp{When: formatTime(t)}, MultiGeometry: &MultiGeometry{}}
+	for _, r := range splitPolygon(ring) {
+		placemark.MultiGeometry.Polygons = append(placemark.MultiGeometry.Polygons, Polygon{
+			Tessellate:  1,
+			Coordinates: formatCoordinates(r),
+		})
+	}
+	return placemark
+}
+
+// Returns a ground station as a point placemark with its altitude in meters
+func StationPlacemark(name string, station satellite.Coordinates) Placemark {
+	return Placemark{
+		Name: name,
+		Point: &Point{
+			AltitudeMode: "absolute",
+			Coordinates: formatCoordinates([]point{toPoint(station)}) + "," +
+				strconv.FormatFloat(station.Altitude.Meters(), 'f', -1, 64),
+		},
+	}
+}
+
+// Writes placemarks as a KML document
+func WriteKML(w io.Writer, name string, placemarks ...Placemark) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(kmlDocument{Namespace: kmlNamespace, Name: name, Placemarks: placemarks}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
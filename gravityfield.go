@@ -0,0 +1,226 @@
+package satellite
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDegree = errors.New("degree or order out of range")
+
+// GravityField is a spherical harmonic expansion of the Earth's gravity potential.
+// Coefficients are stored unnormalized, indexed [n][m]. Degree and Order can be lowered to truncate the expansion,
+// they are clamped to the coefficients held and a field without coefficients is a point mass.
+type GravityField struct {
+	Mu          float64
+	RadiusEarth float64
+	Degree      int
+	Order       int
+	c           [][]float64
+	s           [][]float64
+}
+
+func newGravityField(mu, radius float64, degree, order int) GravityField {
+	field := GravityField{Mu: mu, RadiusEarth: radius, Degree: degree, Order: order}
+	field.c = make([][]float64, degree+1)
+	field.s = make([][]float64, degree+1)
+	for n := range field.c {
+		field.c[n] = make([]float64, n+1)
+		field.s[n] = make([]float64, n+1)
+	}
+	field.c[0][0] = 1
+	return field
+}
+
+// Returns the central body term only
+func PointMassGravityField(mu float64) GravityField {
+	return newGravityField(mu, EQUATOR_RADIUS, 0, 0)
+}
+
+// Returns the zonal J2, J3 and J4 field of a gravity model used by sgp4
+func ZonalGravityField(gravConst Gravity) (GravityField, error) {
+	grav, err := getGravConst(gravConst)
+	if err != nil {
+		return GravityField{}, fmt.Errorf("getGravConst: %w", err)
+	}
+	field := newGravityField(grav.mu, grav.radiusearthkm, 4, 0)
+	field.c[2][0] = -grav.j2
+	field.c[3][0] = -grav.j3
+	field.c[4][0] = -grav.j4
+	return field, nil
+}
+
+// normalization returns the factor converting a fully normalized coefficient of degree n and order m to an unnormalized one
+func normalization(n, m int) float64 {
+	// (n-m)!/(n+m)! as a running product to stay in range
+	ratio := 1.0
+	for k := n - m + 1; k <= n+m; k++ {
+		ratio /= float64(k)
+	}
+	delta := 2.0
+	if m == 0 {
+		delta = 1
+	}
+	return math.Sqrt(delta * float64(2*n+1) * ratio)
+}
+
+// Reads fully normalized coefficients up to the given degree and order.
+// Accepts the EGM96/EGM2008 ascii layout ("n m C S [sigmaC sigmaS]") and ICGEM .gfc files, where data lines start
+// with "gfc" and the header supplies earth_gravity_constant (m^3/s^2) and radius (m).
+// mu (km^3/s^2) and radius (km) are used unless the file header overrides them.
+// Unnormalized recursion limits the usable degree to about 150.
+func ReadGravityField(r io.Reader, degree, order int, mu, radius float64) (GravityField, error) {
+	if degree < 0 || order < 0 || order > degree {
+		return GravityField{}, fmt.Errorf("%w: degree %d order %d", ErrInvalidDegree, degree, order)
+	}
+	field := newGravityField(mu, radius, degree, order)
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		fields := strings.Fields(strings.ReplaceAll(scanner.Text(), "D", "E"))
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToLower(fields[0]) {
+		case "earth_gravity_constant":
+			if len(fields) > 1 {
+				gm, err := strconv.ParseFloat(fields[1], 64)
+				if err != nil {
+					return GravityField{}, fmt.Errorf("line %d: earth_gravity_constant: %w", lineNumber, err)
+				}
+				field.Mu = gm / 1e9
+			}
+			continue
+		case "radius":
+			if len(fields) > 1 {
+				re, err := strconv.ParseFloat(fields[1], 64)
+				if err != nil {
+					return GravityField{}, fmt.Errorf("line %d: radius: %w", lineNumber, err)
+				}
+				field.RadiusEarth = re / 1000
+			}
+			continue
+		case "gfc":
+			fields = fields[1:]
+		}
+		if len(fields) < 4 {
+			continue
+		}
+		n, errN := strconv.Atoi(fields[0])
+		m, errM := strconv.Atoi(fields[1])
+		if errN != nil || errM != nil {
+			// header line
+			continue
+		}
+		if n > degree || m > order || m > n || n < 0 || m < 0 {
+			continue
+		}
+		c, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return GravityField{}, fmt.Errorf("line %d: C%d,%d: %w", lineNumber, n, m, err)
+		}
+		s, err := strconv.ParseFloat(fields[3], 64)
+		if err != nil {
+			return GravityField{}, fmt.Errorf("line %d: S%d,%d: %w", lineNumber, n, m, err)
+		}
+		if n == 0 {
+			continue
+		}
+		factor := normalization(n, m)
+		field.c[n][m] = c * factor
+		field.s[n][m] = s * factor
+	}
+	if err := scanner.Err(); err != nil {
+		return GravityField{}, err
+	}
+	return field, nil
+}
+
+// Returns the unnormalized C and S coefficients of degree n and order m
+func (g GravityField) Coefficient(n, m int) (c, s float64, err error) {
+	if n < 0 || n > g.Degree || n >= len(g.c) || m < 0 || m > n {
+		return 0, 0, fmt.Errorf("%w: degree %d order %d", ErrInvalidDegree, n, m)
+	}
+	return g.c[n][m], g.s[n][m], nil
+}
+
+// Acceleration in km/s^2 at a position in the Earth fixed frame
+// Reference: Montenbruck and Gill, Satellite Orbits, section 3.2.5.
+func (g GravityField) EarthFixedAcceleration(pos Vector3) Vector3 {
+	if len(g.c) == 0 {
+		r := pos.Norm()
+		return pos.Scale(-g.Mu / (r * r * r))
+	}
+	nMax := max(min(g.Degree, len(g.c)-1), 0)
+	mMax := max(min(g.Order, nMax), 0)
+	rSqr := pos.Dot(pos)
+	rho := g.RadiusEarth * g.RadiusEarth / rSqr
+	x0 := g.RadiusEarth * pos.X / rSqr
+	y0 := g.RadiusEarth * pos.Y / rSqr
+	z0 := g.RadiusEarth * pos.Z / rSqr
+
+	size := nMax + 2
+	v := make([][]float64, size)
+	w := make([][]float64, size)
+	for n := range v {
+		v[n] = make([]float64, size)
+		w[n] = make([]float64, size)
+	}
+
+	v[0][0] = g.RadiusEarth / math.Sqrt(rSqr)
+	v[1][0] = z0 * v[0][0]
+	for n := 2; n <= nMax+1; n++ {
+		v[n][0] = (float64(2*n-1)*z0*v[n-1][0] - float64(n-1)*rho*v[n-2][0]) / float64(n)
+	}
+	for m := 1; m <= mMax+1; m++ {
+		v[m][m] = float64(2*m-1) * (x0*v[m-1][m-1] - y0*w[m-1][m-1])
+		w[m][m] = float64(2*m-1) * (x0*w[m-1][m-1] + y0*v[m-1][m-1])
+		if m <= nMax {
+			v[m+1][m] = float64(2*m+1) * z0 * v[m][m]
+			w[m+1][m] = float64(2*m+1) * z0 * w[m][m]
+		}
+		for n := m + 2; n <= nMax+1; n++ {
+			v[n][m] = (float64(2*n-1)*z0*v[n-1][m] - float64(n+m-1)*rho*v[n-2][m]) / float64(n-m)
+			w[n][m] = (float64(2*n-1)*z0*w[n-1][m] - float64(n+m-1)*rho*w[n-2][m]) / float64(n-m)
+		}
+	}
+
+	var ax, ay, az float64
+	for m := 0; m <= mMax; m++ {
+		for n := m; n <= nMax; n++ {
+			c := g.c[n][m]
+			s := g.s[n][m]
+			if m == 0 {
+				ax -= c * v[n+1][1]
+				ay -= c * w[n+1][1]
+				az -= float64(n+1) * c * v[n+1][0]
+			} else {
+				fac := 0.5 * float64(n-m+1) * float64(n-m+2)
+				ax += 0.5*(-c*v[n+1][m+1]-s*w[n+1][m+1]) + fac*(c*v[n+1][m-1]+s*w[n+1][m-1])
+				ay += 0.5*(-c*w[n+1][m+1]+s*v[n+1][m+1]) + fac*(-c*w[n+1][m-1]+s*v[n+1][m-1])
+				az += float64(n-m+1) * (-c*v[n+1][m] - s*w[n+1][m])
+			}
+		}
+	}
+
+	k := g.Mu / (g.RadiusEarth * g.RadiusEarth)
+	return Vector3{X: k * ax, Y: k * ay, Z: k * az}
+}
+
+// Acceleration in km/s^2 at an inertial position, the Earth fixed frame is reached by rotating with GMST
+func (g GravityField) Acceleration(t time.Time, pos, vel Vector3) (Vector3, error) {
+	if g.Order <= 0 || len(g.c) <= 1 {
+		// zonal fields are symmetric about the pole, no rotation needed
+		return g.EarthFixedAcceleration(pos), nil
+	}
+	gmst := GSTimeFromDate(t)
+	accel := g.EarthFixedAcceleration(ECIToECEF(pos, gmst))
+	return ECIToECEF(accel, -gmst), nil
+}
@@ -0,0 +1,93 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var ErrInvalidSampling = errors.New("sampling window or step is invalid")
+
+// GroundTrackPoint is a sub-satellite point, the longitude is within -180 to 180 degrees
+type GroundTrackPoint struct {
+	Time time.Time
+	Coordinates
+	// Sunlit is false while the satellite is in the Earth's shadow
+	Sunlit bool
+	// Ascending is true while the satellite moves north
+	Ascending bool
+}
+
+// GroundTrackSegment is a run of ground track points that does not cross the antimeridian
+type GroundTrackSegment []GroundTrackPoint
+
+// sampleTimes returns the times from start to end every step, end is always included
+func sampleTimes(start, end time.Time, step time.Duration) ([]time.Time, error) {
+	if step <= 0 || end.Before(start) {
+		return nil, fmt.Errorf("%w: %v to %v every %v", ErrInvalidSampling, start, end, step)
+	}
+	var times []time.Time
+	for t := start; t.Before(end); t = t.Add(step) {
+		times = append(times, t)
+	}
+	return append(times, end), nil
+}
+
+// Calculates the ground track of a propagator from start to end every step.
+// The track is split into segments where it crosses ±180 degrees longitude, both sides of a crossing end on the
+// antimeridian so the segments can be drawn as lines on a map.
+func GroundTrack(p Propagator, start, end time.Time, step time.Duration) ([]GroundTrackSegment, error) {
+	times, err := sampleTimes(start, end, step)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []GroundTrackSegment
+	var segment GroundTrackSegment
+	for _, t := range times {
+		pos, vel, err := p.PositionVelocity(t)
+		if err != nil {
+			return nil, fmt.Errorf("position at %v: %w", t, err)
+		}
+		_, lla := ECIToLLA(pos, GSTimeFromDate(t))
+		lla.Longitude = Radians(wrapPi(lla.Longitude.Radians()))
+		point := GroundTrackPoint{
+			Time:        t,
+			Coordinates: lla,
+			Sunlit:      !InEarthShadow(pos, t),
+			Ascending:   vel.Z > 0,
+		}
+
+		if len(segment) > 0 {
+			previous := segment[len(segment)-1]
+			if math.Abs(point.Longitude.Radians()-previous.Longitude.Radians()) > math.Pi {
+				before, after := antimeridianCrossing(previous, point)
+				segments = append(segments, append(segment, before))
+				segment = GroundTrackSegment{after}
+			}
+		}
+		segment = append(segment, point)
+	}
+	return append(segments, segment), nil
+}
+
+// antimeridianCrossing interpolates the points where the track from a to b meets the antimeridian, on the side of a and
+// on the side of b
+func antimeridianCrossing(a, b GroundTrackPoint) (GroundTrackPoint, GroundTrackPoint) {
+	lonA := a.Longitude.Radians()
+	boundary := math.Copysign(math.Pi, lonA)
+	lonB := lonA + wrapPi(b.Longitude.Radians()-lonA)
+	fraction := (boundary - lonA) / (lonB - lonA)
+
+	crossing := a
+	crossing.Time = a.Time.Add(time.Duration(fraction * float64(b.Time.Sub(a.Time))))
+	crossing.Latitude = Radians(a.Latitude.Radians() + fraction*(b.Latitude.Radians()-a.Latitude.Radians()))
+	crossing.Altitude = Kilometers(a.Altitude.Kilometers() + fraction*(b.Altitude.Kilometers()-a.Altitude.Kilometers()))
+
+	before := crossing
+	before.Longitude = Radians(boundary)
+	after := crossing
+	after.Longitude = Radians(-boundary)
+	return before, after
+}
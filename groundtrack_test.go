@@ -0,0 +1,77 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite/internal/testsat"
+)
+
+func TestGroundTrack(t *testing.T) {
+	sat := testsat.ISS(t, TLEToSat, GravityWGS72)
+	start := time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+	end := start.Add(3 * time.Hour)
+
+	segments, err := GroundTrack(sat, start, end, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// each revolution sweeps all longitudes, so two hours cross the antimeridian at least once
+	if len(segments) < 2 {
+		t.Fatalf("expected the track to cross the antimeridian, got %d segments", len(segments))
+	}
+
+	points := 0
+	var sunlit, eclipsed, ascending, descending bool
+	for i, segment := range segments {
+		points += len(segment)
+		for j, point := range segment {
+			lon := point.Longitude.Degrees()
+			if lon < -180 || lon > 180 {
+				t.Errorf("longitude %f out of range", lon)
+			}
+			if j > 0 && math.Abs(lon-segment[j-1].Longitude.Degrees()) > 180 {
+				t.Errorf("segment %d jumps from %f to %f", i, segment[j-1].Longitude.Degrees(), lon)
+			}
+			if math.Abs(point.Latitude.Degrees()) > 52 {
+				t.Errorf("latitude %f above the inclination", point.Latitude.Degrees())
+			}
+			sunlit = sunlit || point.Sunlit
+			eclipsed = eclipsed || !point.Sunlit
+			ascending = ascending || point.Ascending
+			descending = descending || !point.Ascending
+		}
+		if i > 0 {
+			last := segments[i-1][len(segments[i-1])-1]
+			first := segment[0]
+			if math.Abs(math.Abs(last.Longitude.Degrees())-180) > 1e-9 || last.Longitude.Radians() != -first.Longitude.Radians() {
+				t.Errorf("segments %d and %d do not meet on the antimeridian: %f %f", i-1, i, last.Longitude.Degrees(), first.Longitude.Degrees())
+			}
+			if !last.Time.Equal(first.Time) || last.Latitude != first.Latitude {
+				t.Errorf("crossing points differ: %v %v", last, first)
+			}
+		}
+	}
+	if want := 181 + 2*(len(segments)-1); points != want {
+		t.Errorf("expected %d points, got %d", want, points)
+	}
+	if !sunlit || !eclipsed || !ascending || !descending {
+		t.Errorf("expected every annotation over three revolutions, sunlit %v eclipsed %v ascending %v descending %v", sunlit, eclipsed, ascending, descending)
+	}
+	if !segments[0][0].Time.Equal(start) || !segments[len(segments)-1][len(segments[len(segments)-1])-1].Time.Equal(end) {
+		t.Errorf("track does not span the window")
+	}
+}
+
+func TestGroundTrackInvalidSampling(t *testing.T) {
+	sat := testsat.ISS(t, TLEToSat, GravityWGS72)
+	start := time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+	if _, err := GroundTrack(sat, start, start.Add(time.Hour), 0); !errors.Is(err, ErrInvalidSampling) {
+		t.Errorf("expected ErrInvalidSampling for a zero step, got %v", err)
+	}
+	if _, err := GroundTrack(sat, start, start.Add(-time.Hour), time.Minute); !errors.Is(err, ErrInvalidSampling) {
+		t.Errorf("expected ErrInvalidSampling for a reversed window, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrStepSizeUnderflow = errors.New("integration step size underflow")
+var ErrTooManySteps = errors.New("integration exceeded the maximum number of steps")
+
+// Dormand-Prince 5(4) tableau
+// Reference: Dormand and Prince, A family of embedded Runge-Kutta formulae, 1980.
+var (
+	dopriC = [7]float64{0, 1.0 / 5, 3.0 / 10, 4.0 / 5, 8.0 / 9, 1, 1}
+	dopriA = [7][6]float64{
+		{},
+		{1.0 / 5},
+		{3.0 / 40, 9.0 / 40},
+		{44.0 / 45, -56.0 / 15, 32.0 / 9},
+		{19372.0 / 6561, -25360.0 / 2187, 64448.0 / 6561, -212.0 / 729},
+		{9017.0 / 3168, -355.0 / 33, 46732.0 / 5247, 49.0 / 176, -5103.0 / 18656},
+		{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84},
+	}
+	// difference between the fifth and fourth order weights
+	dopriE = [7]float64{71.0 / 57600, 0, -71.0 / 16695, 71.0 / 1920, -17253.0 / 339200, 22.0 / 525, -1.0 / 40}
+)
+
+// derivative of a 6 element state at a time offset in seconds
+type derivative func(t float64, y [6]float64) ([6]float64, error)
+
+type integratorSettings struct {
+	// per component tolerance is absolute + relative*|y|
+	absolute float64
+	relative float64
+	// initial and largest step in seconds
+	initialStep float64
+	maxStep     float64
+	maxSteps    int
+}
+
+// integrate advances y from t0 to t1 seconds with an adaptive Dormand-Prince 5(4) method
+func integrate(f derivative, t0 float64, y [6]float64, t1 float64, settings integratorSettings) ([6]float64, error) {
+	if t1 == t0 {
+		return y, nil
+	}
+	direction := 1.0
+	if t1 < t0 {
+		direction = -1
+	}
+
+	h := math.Min(settings.initialStep, math.Abs(t1-t0)) * direction
+	t := t0
+	k1, err := f(t, y)
+	if err != nil {
+		return y, err
+	}
+
+	for steps := 0; ; steps++ {
+		if steps >= settings.maxSteps {
+			return y, fmt.Errorf("%w: %d steps at t %f", ErrTooManySteps, steps, t)
+		}
+		if (t+h-t1)*direction > 0 {
+			h = t1 - t
+		}
+
+		var k [7][6]float64
+		k[0] = k1
+		var yNew [6]float64
+		for stage := 1; stage < 7; stage++ {
+			var yStage [6]float64
+			for i := range yStage {
+				sum := 0.0
+				for j := 0; j < stage; j++ {
+					sum += dopriA[stage][j] * k[j][i]
+				}
+				yStage[i] = y[i] + h*sum
+			}
+			k[stage], err = f(t+dopriC[stage]*h, yStage)
+			if err != nil {
+				return y, err
+			}
+			if stage == 6 {
+				// the last stage is evaluated at the fifth order solution
+				yNew = yStage
+			}
+		}
+
+		errNorm := 0.0
+		for i := range y {
+			sum := 0.0
+			for j := range dopriE {
+				sum += dopriE[j] * k[j][i]
+			}
+			scale := settings.absolute + settings.relative*math.Max(math.Abs(y[i]), math.Abs(yNew[i]))
+			errNorm = math.Max(errNorm, math.Abs(h*sum)/scale)
+		}
+		if math.IsNaN(errNorm) {
+			return y, fmt.Errorf("%w: integration error estimate at t %f", ErrNonFiniteValue, t)
+		}
+
+		if errNorm <= 1 {
+			t += h
+			y = yNew
+			k1 = k[6]
+			if t == t1 {
+				return y, nil
+			}
+		}
+
+		factor := 5.0
+		if errNorm > 0 {
+			factor = math.Min(5, math.Max(0.2, 0.9*math.Pow(errNorm, -0.2)))
+		}
+		h *= factor
+		if math.Abs(h) > settings.maxStep {
+			h = settings.maxStep * direction
+		}
+		if math.Abs(h) < 1e-9 {
+			return y, fmt.Errorf("%w: at t %f", ErrStepSizeUnderflow, t)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package sgp4ver reads the SGP4 verification files published alongside
+// "Revisiting Spacetrack Report #3" (Vallado et al., AIAA 2006-6753):
+// SGP4-VER.TLE, the element sets with their start/stop/step columns, and
+// tcppver.out, the reference program's output for them.
+package sgp4ver
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/infostellarinc/go-satellite"
+)
+
+// Case is one element set from SGP4-VER.TLE
+type Case struct {
+	CatalogNumber int
+	Line1         string
+	Line2         string
+
+	// Start, Stop and Step are in minutes from epoch
+	Start float64
+	Stop  float64
+	Step  float64
+
+	// ErrorCode is the sgp4 error code the case is expected to end with, 0 if it propagates cleanly.
+	// See ErrorCodes for the matching errors.
+	ErrorCode int
+}
+
+// State is one line of tcppver.out
+type State struct {
+	Tsince   float64
+	Position [3]float64
+	Velocity [3]float64
+}
+
+// ErrorCodes maps the sgp4 error codes of the verification cases to the sentinel errors
+var ErrorCodes = map[int]error{
+	1: satellite.ErrInvalidMeanEccentricity,
+	2: satellite.ErrInvalidMeanMotion,
+	3: satellite.ErrInvalidPertubedEccentricity,
+	4: satellite.ErrInvalidSemilatusRectum,
+	6: satellite.ErrSatelliteDecay,
+}
+
+var errorCodeComment = regexp.MustCompile(`error code (\d+)\s*$`)
+
+// Times returns the minutes from epoch at which the reference program reports a state.
+// The epoch itself is always reported first, then start to stop by step with the last step clamped to stop.
+func (c Case) Times() []float64 {
+	times := []float64{0}
+	tsince := c.Start
+	if math.Abs(tsince) > 1.0e-8 {
+		tsince -= c.Step
+	}
+	for tsince < c.Stop {
+		tsince += c.Step
+		if tsince > c.Stop {
+			tsince = c.Stop
+		}
+		times = append(times, tsince)
+	}
+	return times
+}
+
+// ReadCases parses SGP4-VER.TLE
+func ReadCases(r io.Reader) ([]Case, error) {
+	var cases []Case
+	var line1 string
+	errorCode := 0
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), " \r")
+		switch {
+		case len(line) == 0:
+			continue
+		case strings.HasPrefix(line, "#"):
+			if m := errorCodeComment.FindStringSubmatch(line); m != nil {
+				errorCode, _ = strconv.Atoi(m[1])
+			}
+		case strings.HasPrefix(line, "1 "):
+			line1 = line
+		case strings.HasPrefix(line, "2 "):
+			if line1 == "" {
+				return nil, fmt.Errorf("line %d: line 2 without line 1", lineNumber)
+			}
+			if len(line) <= 69 {
+				return nil, fmt.Errorf("line %d: missing start, stop and step columns", lineNumber)
+			}
+			columns := strings.Fields(line[69:])
+			if len(columns) != 3 {
+				return nil, fmt.Errorf("line %d: expected start, stop and step, got %q", lineNumber, line[69:])
+			}
+			times := make([]float64, 3)
+			for i, column := range columns {
+				f, err := strconv.ParseFloat(column, 64)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+				}
+				times[i] = f
+			}
+			catalogNumber, err := strconv.Atoi(strings.TrimSpace(line1[2:7]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: catalog number: %w", lineNumber, err)
+			}
+			cases = append(cases, Case{
+				CatalogNumber: catalogNumber,
+				Line1:         line1,
+				Line2:         line[:69],
+				Start:         times[0],
+				Stop:          times[1],
+				Step:          times[2],
+				ErrorCode:     errorCode,
+			})
+			line1 = ""
+			errorCode = 0
+		default:
+			return nil, fmt.Errorf("line %d: unexpected %q", lineNumber, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
+
+// ReadOutput parses tcppver.out into the reported states keyed by catalog number
+func ReadOutput(r io.Reader) (map[int][]State, error) {
+	output := make(map[int][]State)
+	current := -1
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) == 2 && fields[1] == "xx" {
+			catalogNumber, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: catalog number: %w", lineNumber, err)
+			}
+			current = catalogNumber
+			output[current] = nil
+			continue
+		}
+		if current < 0 {
+			return nil, fmt.Errorf("line %d: state before any catalog header", lineNumber)
+		}
+		if len(fields) < 7 {
+			return nil, fmt.Errorf("line %d: expected at least 7 columns, got %d", lineNumber, len(fields))
+		}
+		values := make([]float64, 7)
+		for i := range values {
+			f, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			values[i] = f
+		}
+		output[current] = append(output[current], State{
+			Tsince:   values[0],
+			Position: [3]float64{values[1], values[2], values[3]},
+			Velocity: [3]float64{values[4], values[5], values[6]},
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
@@ -0,0 +1,34 @@
+// Package testsat holds the ISS element set the tests of this module share, read from testdata/iss.tle.
+package testsat
+
+import (
+	_ "embed"
+	"strings"
+	"testing"
+)
+
+//go:embed testdata/iss.tle
+var issTLE string
+
+// The two lines of the ISS element set, epoch 2020-05-19T08:15:38Z
+var ISSLine1, ISSLine2 = issLines()
+
+func issLines() (string, string) {
+	lines := strings.Split(strings.TrimSpace(issTLE), "\n")
+	if len(lines) != 2 {
+		panic("testsat: testdata/iss.tle does not hold two lines")
+	}
+	return strings.TrimSpace(lines[0]), strings.TrimSpace(lines[1])
+}
+
+// Parses the ISS element set with the given parser, failing the test on an error.
+// The parser is passed in so the tests of the satellite package itself can use this without an import cycle, e.g.
+// testsat.ISS(t, satellite.TLEToSat, satellite.GravityWGS72).
+func ISS[S, G any](t testing.TB, parse func(line1, line2 string, grav G) (S, error), grav G) S {
+	t.Helper()
+	sat, err := parse(ISSLine1, ISSLine2, grav)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return sat
+}
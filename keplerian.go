@@ -0,0 +1,378 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrDegenerateState = errors.New("state has zero radius or zero angular momentum")
+var ErrInvalidGravitationalParameter = errors.New("gravitational parameter is not positive")
+var ErrParabolicOrbit = errors.New("orbit is parabolic")
+
+// Eccentricity and inclination below this are treated as circular and equatorial
+const keplerianSingularityTolerance = 1.0e-10
+
+type OrbitType int
+
+const (
+	EllipticalInclined OrbitType = iota
+	CircularInclined
+	EllipticalEquatorial
+	CircularEquatorial
+)
+
+// Classical orbital elements, angles in radians and distances in km.
+// For circular and equatorial orbits the undefined angles are zero and the alternate angle takes their place, so
+// TrueAnomaly holds the argument of latitude for circular inclined orbits, ArgumentOfPerigee holds the true longitude
+// of perigee for elliptical equatorial orbits and TrueAnomaly holds the true longitude for circular equatorial orbits.
+type KeplerianElements struct {
+	// negative for hyperbolic orbits
+	SemiMajorAxis   float64
+	SemilatusRectum float64
+	Eccentricity    float64
+
+	Inclination                   float64
+	RightAscensionOfAscendingNode float64
+	ArgumentOfPerigee             float64
+
+	TrueAnomaly float64
+	MeanAnomaly float64
+	// hyperbolic anomaly for hyperbolic orbits
+	EccentricAnomaly float64
+
+	// Alternate angles, set only for the orbit type they belong to
+	ArgumentOfLatitude     float64
+	TrueLongitudeOfPerigee float64
+	TrueLongitude          float64
+
+	Type OrbitType
+}
+
+// Equinoctial elements, non-singular for circular and equatorial orbits.
+// K and H are the eccentricity vector components e cos(w + fr*RAAN) and e sin(w + fr*RAAN),
+// Q and P are the node vector components tan(i/2)^fr cos(RAAN) and tan(i/2)^fr sin(RAAN),
+// where fr is -1 for retrograde element sets and +1 otherwise.
+type EquinoctialElements struct {
+	SemiMajorAxis float64
+	K             float64
+	H             float64
+	Q             float64
+	P             float64
+	MeanLongitude float64
+	Retrograde    bool
+}
+
+// wrapTwoPi returns angle in 0 to 2pi
+func wrapTwoPi(angle float64) float64 {
+	angle = math.Mod(angle, TWOPI)
+	if angle < 0 {
+		angle += TWOPI
+	}
+	return angle
+}
+
+// Converts a position (km) and velocity (km/s) into classical orbital elements.
+// mu is the gravitational parameter in km^3/s^2, e.g. GRAVITY_EARTH.
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, algorithm 9 (rv2coe).
+func StateToKeplerian(pos, vel Vector3, mu float64) (KeplerianElements, error) {
+	var el KeplerianElements
+	if mu <= 0 {
+		return el, fmt.Errorf("%w: mu is %f", ErrInvalidGravitationalParameter, mu)
+	}
+
+	r := pos.Norm()
+	v := vel.Norm()
+	h := pos.Cross(vel)
+	hMag := h.Norm()
+	if r == 0 || hMag == 0 {
+		return el, fmt.Errorf("%w: radius %f angular momentum %f", ErrDegenerateState, r, hMag)
+	}
+	node := Vector3{X: -h.Y, Y: h.X}
+
+	rdotv := pos.Dot(vel)
+	eVec := pos.Scale(v*v - mu/r).Add(vel.Scale(-rdotv)).Scale(1 / mu)
+	el.Eccentricity = eVec.Norm()
+	el.SemilatusRectum = hMag * hMag / mu
+
+	energy := v*v/2 - mu/r
+	if math.Abs(el.Eccentricity-1) > keplerianSingularityTolerance {
+		el.SemiMajorAxis = -mu / (2 * energy)
+	} else {
+		el.SemiMajorAxis = math.Inf(1)
+	}
+
+	el.Inclination = math.Acos(math.Max(-1, math.Min(1, h.Z/hMag)))
+
+	circular := el.Eccentricity < keplerianSingularityTolerance
+	equatorial := el.Inclination < keplerianSingularityTolerance || math.Abs(el.Inclination-math.Pi) < keplerianSingularityTolerance
+	switch {
+	case circular && equatorial:
+		el.Type = CircularEquatorial
+	case circular:
+		el.Type = CircularInclined
+	case equatorial:
+		el.Type = EllipticalEquatorial
+	default:
+		el.Type = EllipticalInclined
+	}
+
+	if !equatorial {
+		el.RightAscensionOfAscendingNode = wrapTwoPi(math.Atan2(node.Y, node.X))
+	}
+
+	switch el.Type {
+	case EllipticalInclined:
+		el.ArgumentOfPerigee = node.Angle(eVec)
+		if eVec.Z < 0 {
+			el.ArgumentOfPerigee = TWOPI - el.ArgumentOfPerigee
+		}
+		el.TrueAnomaly = eVec.Angle(pos)
+		if rdotv < 0 {
+			el.TrueAnomaly = TWOPI - el.TrueAnomaly
+		}
+	case CircularInclined:
+		el.ArgumentOfLatitude = node.Angle(pos)
+		if pos.Z < 0 {
+			el.ArgumentOfLatitude = TWOPI - el.ArgumentOfLatitude
+		}
+		el.TrueAnomaly = el.ArgumentOfLatitude
+	case EllipticalEquatorial:
+		el.TrueLongitudeOfPerigee = wrapTwoPi(math.Atan2(eVec.Y, eVec.X))
+		if el.Inclination > math.Pi/2 {
+			el.TrueLongitudeOfPerigee = TWOPI - el.TrueLongitudeOfPerigee
+		}
+		el.ArgumentOfPerigee = el.TrueLongitudeOfPerigee
+		el.TrueAnomaly = eVec.Angle(pos)
+		if rdotv < 0 {
+			el.TrueAnomaly = TWOPI - el.TrueAnomaly
+		}
+	case CircularEquatorial:
+		el.TrueLongitude = wrapTwoPi(math.Atan2(pos.Y, pos.X))
+		if el.Inclination > math.Pi/2 {
+			el.TrueLongitude = TWOPI - el.TrueLongitude
+		}
+		el.TrueAnomaly = el.TrueLongitude
+	}
+
+	el.EccentricAnomaly, el.MeanAnomaly = anomaliesFromTrue(el.TrueAnomaly, el.Eccentricity)
+	return el, nil
+}
+
+// anomaliesFromTrue returns the eccentric (hyperbolic, parabolic) and mean anomaly for a true anomaly
+func anomaliesFromTrue(nu, e float64) (eccentric, mean float64) {
+	switch {
+	case math.Abs(e-1) <= keplerianSingularityTolerance:
+		// Barker's equation
+		eccentric = math.Tan(nu / 2)
+		mean = eccentric + eccentric*eccentric*eccentric/3
+	case e < 1:
+		sinE := math.Sqrt(1-e*e) * math.Sin(nu) / (1 + e*math.Cos(nu))
+		cosE := (e + math.Cos(nu)) / (1 + e*math.Cos(nu))
+		eccentric = wrapTwoPi(math.Atan2(sinE, cosE))
+		mean = wrapTwoPi(eccentric - e*math.Sin(eccentric))
+	default:
+		sinhH := math.Sqrt(e*e-1) * math.Sin(nu) / (1 + e*math.Cos(nu))
+		eccentric = math.Asinh(sinhH)
+		mean = e*sinhH - eccentric
+	}
+	return
+}
+
+// Solves Kepler's equation for the true anomaly given a mean anomaly, both in radians.
+// Hyperbolic orbits (e > 1) take the hyperbolic mean anomaly.
+func MeanToTrueAnomaly(meanAnomaly, e float64) (float64, error) {
+	if e < 0 || !isFinite(meanAnomaly) {
+		return 0, fmt.Errorf("%w: eccentricity is %f mean anomaly is %f", ErrInvalidMeanEccentricity, e, meanAnomaly)
+	}
+	if math.Abs(e-1) <= keplerianSingularityTolerance {
+		return 0, ErrParabolicOrbit
+	}
+
+	if e < 1 {
+		m := math.Remainder(meanAnomaly, TWOPI)
+		eccentric := m
+		if e > 0.8 {
+			eccentric = math.Pi
+			if m < 0 {
+				eccentric = -math.Pi
+			}
+		}
+		for i := 0; i < 50; i++ {
+			delta := (eccentric - e*math.Sin(eccentric) - m) / (1 - e*math.Cos(eccentric))
+			eccentric -= delta
+			if math.Abs(delta) < 1e-14 {
+				break
+			}
+		}
+		nu := 2 * math.Atan2(math.Sqrt(1+e)*math.Sin(eccentric/2), math.Sqrt(1-e)*math.Cos(eccentric/2))
+		return wrapTwoPi(nu), nil
+	}
+
+	hyperbolic := math.Asinh(meanAnomaly / e)
+	for i := 0; i < 50; i++ {
+		delta := (e*math.Sinh(hyperbolic) - hyperbolic - meanAnomaly) / (e*math.Cosh(hyperbolic) - 1)
+		hyperbolic -= delta
+		if math.Abs(delta) < 1e-14 {
+			break
+		}
+	}
+	return 2 * math.Atan2(math.Sqrt(e+1)*math.Sinh(hyperbolic/2), math.Sqrt(e-1)*math.Cosh(hyperbolic/2)), nil
+}
+
+// Returns the mean anomaly for a true anomaly, both in radians
+func TrueToMeanAnomaly(trueAnomaly, e float64) (float64, error) {
+	if e < 0 || !isFinite(trueAnomaly) {
+		return 0, fmt.Errorf("%w: eccentricity is %f true anomaly is %f", ErrInvalidMeanEccentricity, e, trueAnomaly)
+	}
+	if math.Abs(e-1) <= keplerianSingularityTolerance {
+		return 0, ErrParabolicOrbit
+	}
+	_, mean := anomaliesFromTrue(trueAnomaly, e)
+	return mean, nil
+}
+
+// Converts classical orbital elements into position (km) and velocity (km/s).
+// Uses SemilatusRectum when it is set, otherwise derives it from SemiMajorAxis; the anomaly used is TrueAnomaly.
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, algorithm 10 (coe2rv).
+func KeplerianToState(el KeplerianElements, mu float64) (position, velocity Vector3, err error) {
+	if mu <= 0 {
+		return position, velocity, fmt.Errorf("%w: mu is %f", ErrInvalidGravitationalParameter, mu)
+	}
+	if el.Eccentricity < 0 {
+		return position, velocity, fmt.Errorf("%w: eccentricity is %f", ErrInvalidMeanEccentricity, el.Eccentricity)
+	}
+
+	p := el.SemilatusRectum
+	if p == 0 {
+		p = el.SemiMajorAxis * (1 - el.Eccentricity*el.Eccentricity)
+	}
+	if !(p > 0) || math.IsInf(p, 0) {
+		return position, velocity, fmt.Errorf("%w: semilatus rectum is %f", ErrInvalidSemilatusRectum, p)
+	}
+
+	e := el.Eccentricity
+	nu := el.TrueAnomaly
+	denominator := 1 + e*math.Cos(nu)
+	if denominator <= 0 {
+		return position, velocity, fmt.Errorf("%w: true anomaly %f is beyond the asymptote", ErrDegenerateState, nu)
+	}
+
+	r := p / denominator
+	perifocalPos := Vector3{X: r * math.Cos(nu), Y: r * math.Sin(nu)}
+	sqrtMuP := math.Sqrt(mu / p)
+	perifocalVel := Vector3{X: -sqrtMuP * math.Sin(nu), Y: sqrtMuP * (e + math.Cos(nu))}
+
+	position = perifocalToInertial(perifocalPos, el.RightAscensionOfAscendingNode, el.Inclination, el.ArgumentOfPerigee)
+	velocity = perifocalToInertial(perifocalVel, el.RightAscensionOfAscendingNode, el.Inclination, el.ArgumentOfPerigee)
+	return position, velocity, nil
+}
+
+// perifocalToInertial rotates a perifocal vector by R3(-raan) R1(-incl) R3(-argp)
+func perifocalToInertial(v Vector3, raan, incl, argp float64) Vector3 {
+	return R3(-raan).Mul(R1(-incl)).Mul(R3(-argp)).MulVec(v)
+}
+
+// equinoctialFrame returns the f and g unit vectors of the equinoctial reference frame
+func equinoctialFrame(p, q, fr float64) (f, g Vector3) {
+	s := 1 + p*p + q*q
+	f = Vector3{X: (1 - p*p + q*q) / s, Y: 2 * p * q / s, Z: -2 * fr * p / s}
+	g = Vector3{X: 2 * fr * p * q / s, Y: (1 + p*p - q*q) * fr / s, Z: 2 * q / s}
+	return f, g
+}
+
+// Converts a position (km) and velocity (km/s) into equinoctial elements.
+// Element sets with inclination above 90 degrees use the retrograde factor to stay non-singular.
+// Reference: Danielson et al., Semianalytic Satellite Theory, 1995, section 2.1.2.
+func StateToEquinoctial(pos, vel Vector3, mu float64) (EquinoctialElements, error) {
+	var eq EquinoctialElements
+	if mu <= 0 {
+		return eq, fmt.Errorf("%w: mu is %f", ErrInvalidGravitationalParameter, mu)
+	}
+
+	r := pos.Norm()
+	v := vel.Norm()
+	h := pos.Cross(vel)
+	hMag := h.Norm()
+	if r == 0 || hMag == 0 {
+		return eq, fmt.Errorf("%w: radius %f angular momentum %f", ErrDegenerateState, r, hMag)
+	}
+
+	eq.SemiMajorAxis = 1 / (2/r - v*v/mu)
+	if !(eq.SemiMajorAxis > 0) {
+		return eq, fmt.Errorf("%w: equinoctial elements need an elliptical orbit, semi-major axis is %f", ErrInvalidMeanEccentricity, eq.SemiMajorAxis)
+	}
+
+	w := h.Scale(1 / hMag)
+	fr := 1.0
+	if w.Z < 0 {
+		fr = -1
+		eq.Retrograde = true
+	}
+	eq.P = w.X / (1 + fr*w.Z)
+	eq.Q = -w.Y / (1 + fr*w.Z)
+
+	f, g := equinoctialFrame(eq.P, eq.Q, fr)
+	eVec := pos.Scale(v*v - mu/r).Add(vel.Scale(-pos.Dot(vel))).Scale(1 / mu)
+	eq.K = eVec.Dot(f)
+	eq.H = eVec.Dot(g)
+
+	// true longitude to eccentric longitude through the anomalies measured from perigee
+	e := math.Hypot(eq.H, eq.K)
+	longitudeOfPerigee := math.Atan2(eq.H, eq.K)
+	trueLongitude := math.Atan2(pos.Dot(g), pos.Dot(f))
+	nu := trueLongitude - longitudeOfPerigee
+	eccentric := 2 * math.Atan2(math.Sqrt(1-e)*math.Sin(nu/2), math.Sqrt(1+e)*math.Cos(nu/2))
+	eccentricLongitude := eccentric + longitudeOfPerigee
+	eq.MeanLongitude = wrapTwoPi(eccentricLongitude + eq.H*math.Cos(eccentricLongitude) - eq.K*math.Sin(eccentricLongitude))
+	return eq, nil
+}
+
+// Converts equinoctial elements into position (km) and velocity (km/s)
+func EquinoctialToState(eq EquinoctialElements, mu float64) (position, velocity Vector3, err error) {
+	if mu <= 0 {
+		return position, velocity, fmt.Errorf("%w: mu is %f", ErrInvalidGravitationalParameter, mu)
+	}
+	if !(eq.SemiMajorAxis > 0) || eq.H*eq.H+eq.K*eq.K >= 1 {
+		return position, velocity, fmt.Errorf("%w: semi-major axis %f h %f k %f", ErrInvalidMeanEccentricity, eq.SemiMajorAxis, eq.H, eq.K)
+	}
+
+	fr := 1.0
+	if eq.Retrograde {
+		fr = -1
+	}
+	a, h, k := eq.SemiMajorAxis, eq.H, eq.K
+
+	// Kepler's equation in equinoctial form: MeanLongitude = F + h cos F - k sin F
+	eccentricLongitude := eq.MeanLongitude
+	for i := 0; i < 50; i++ {
+		sinF, cosF := math.Sincos(eccentricLongitude)
+		delta := (eccentricLongitude + h*cosF - k*sinF - eq.MeanLongitude) / (1 - h*sinF - k*cosF)
+		eccentricLongitude -= delta
+		if math.Abs(delta) < 1e-14 {
+			break
+		}
+	}
+	sinF, cosF := math.Sincos(eccentricLongitude)
+	e := math.Hypot(h, k)
+	longitudeOfPerigee := math.Atan2(h, k)
+	sinW, cosW := math.Sincos(longitudeOfPerigee)
+	sinE, cosE := math.Sincos(eccentricLongitude - longitudeOfPerigee)
+
+	beta := math.Sqrt(1 - e*e)
+	n := math.Sqrt(mu / (a * a * a))
+	r := a * (1 - k*cosF - h*sinF)
+
+	// perifocal state rotated by the longitude of perigee into the equinoctial frame
+	xw, yw := a*(cosE-e), a*beta*sinE
+	xwDot, ywDot := -a*a*n/r*sinE, a*a*n/r*beta*cosE
+	x := xw*cosW - yw*sinW
+	y := xw*sinW + yw*cosW
+	xDot := xwDot*cosW - ywDot*sinW
+	yDot := xwDot*sinW + ywDot*cosW
+
+	f, g := equinoctialFrame(eq.P, eq.Q, fr)
+	position = f.Scale(x).Add(g.Scale(y))
+	velocity = f.Scale(xDot).Add(g.Scale(yDot))
+	return position, velocity, nil
+}
@@ -0,0 +1,199 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestStateToKeplerian(t *testing.T) {
+	// Vallado, Fundamentals of Astrodynamics and Applications, example 2-5
+	pos := Vector3{X: 6524.834, Y: 6862.875, Z: 6448.296}
+	vel := Vector3{X: 4.901327, Y: 5.533756, Z: -1.976341}
+
+	el, err := StateToKeplerian(pos, vel, GRAVITY_EARTH)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	checks := []struct {
+		name      string
+		got, want float64
+		tolerance float64
+	}{
+		{"semilatus rectum", el.SemilatusRectum, 11067.790, 1e-2},
+		{"semi-major axis", el.SemiMajorAxis, 36127.343, 1e-2},
+		{"eccentricity", el.Eccentricity, 0.832853, 1e-6},
+		{"inclination", el.Inclination * RAD2DEG, 87.870, 1e-3},
+		{"raan", el.RightAscensionOfAscendingNode * RAD2DEG, 227.898, 1e-3},
+		{"argument of perigee", el.ArgumentOfPerigee * RAD2DEG, 53.38, 1e-2},
+		{"true anomaly", el.TrueAnomaly * RAD2DEG, 92.335, 1e-3},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > c.tolerance {
+			t.Errorf("expected %s %f, got %f", c.name, c.want, c.got)
+		}
+	}
+	if el.Type != EllipticalInclined {
+		t.Errorf("expected type %v, got %v", EllipticalInclined, el.Type)
+	}
+}
+
+func TestKeplerianRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements KeplerianElements
+		wantType OrbitType
+	}{
+		{
+			name:     "elliptical inclined",
+			elements: KeplerianElements{SemiMajorAxis: 26600, Eccentricity: 0.74, Inclination: 63.4 * DEG2RAD, RightAscensionOfAscendingNode: 1.2, ArgumentOfPerigee: 4.71, TrueAnomaly: 0.3},
+			wantType: EllipticalInclined,
+		},
+		{
+			name:     "circular inclined",
+			elements: KeplerianElements{SemiMajorAxis: 6778, Inclination: 51.6 * DEG2RAD, RightAscensionOfAscendingNode: 2.5, TrueAnomaly: 4.0},
+			wantType: CircularInclined,
+		},
+		{
+			name:     "elliptical equatorial",
+			elements: KeplerianElements{SemiMajorAxis: 24400, Eccentricity: 0.73, ArgumentOfPerigee: 3.1, TrueAnomaly: 5.5},
+			wantType: EllipticalEquatorial,
+		},
+		{
+			name:     "circular equatorial",
+			elements: KeplerianElements{SemiMajorAxis: 42164, TrueAnomaly: 1.7},
+			wantType: CircularEquatorial,
+		},
+		{
+			name:     "retrograde circular equatorial",
+			elements: KeplerianElements{SemiMajorAxis: 42164, Inclination: math.Pi, TrueAnomaly: 1.7},
+			wantType: CircularEquatorial,
+		},
+		{
+			name:     "hyperbolic",
+			elements: KeplerianElements{SemiMajorAxis: -20000, Eccentricity: 1.4, Inclination: 0.5, RightAscensionOfAscendingNode: 0.1, ArgumentOfPerigee: 0.2, TrueAnomaly: 0.4},
+			wantType: EllipticalInclined,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos, vel, err := KeplerianToState(tt.elements, GRAVITY_EARTH)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			el, err := StateToKeplerian(pos, vel, GRAVITY_EARTH)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if el.Type != tt.wantType {
+				t.Errorf("expected type %v, got %v", tt.wantType, el.Type)
+			}
+			if math.Abs(el.SemiMajorAxis-tt.elements.SemiMajorAxis) > 1e-6*math.Abs(tt.elements.SemiMajorAxis) {
+				t.Errorf("expected semi-major axis %f, got %f", tt.elements.SemiMajorAxis, el.SemiMajorAxis)
+			}
+			if math.Abs(el.Eccentricity-tt.elements.Eccentricity) > 1e-9 {
+				t.Errorf("expected eccentricity %f, got %f", tt.elements.Eccentricity, el.Eccentricity)
+			}
+
+			pos2, vel2, err := KeplerianToState(el, GRAVITY_EARTH)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !pos2.Equals(pos) || !vel2.Equals(vel) {
+				t.Errorf("expected state %v %v, got %v %v", pos, vel, pos2, vel2)
+			}
+		})
+	}
+}
+
+func TestKeplerianAlternateAngles(t *testing.T) {
+	pos, vel, err := KeplerianToState(KeplerianElements{SemiMajorAxis: 7000, Inclination: 0.9, RightAscensionOfAscendingNode: 0.4, TrueAnomaly: 2.2}, GRAVITY_EARTH)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	el, err := StateToKeplerian(pos, vel, GRAVITY_EARTH)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(el.ArgumentOfLatitude-2.2) > 1e-9 || el.ArgumentOfPerigee != 0 {
+		t.Errorf("expected argument of latitude 2.2 and no argument of perigee, got %f %f", el.ArgumentOfLatitude, el.ArgumentOfPerigee)
+	}
+
+	pos, vel, err = KeplerianToState(KeplerianElements{SemiMajorAxis: 7000, TrueAnomaly: 0.5}, GRAVITY_EARTH)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	el, err = StateToKeplerian(pos, vel, GRAVITY_EARTH)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(el.TrueLongitude-0.5) > 1e-9 || el.RightAscensionOfAscendingNode != 0 {
+		t.Errorf("expected true longitude 0.5 and no raan, got %f %f", el.TrueLongitude, el.RightAscensionOfAscendingNode)
+	}
+}
+
+func TestAnomalyConversions(t *testing.T) {
+	for _, e := range []float64{0, 0.1, 0.7, 0.99, 1.5, 4} {
+		for _, nu := range []float64{0.1, 1, 2, 3} {
+			if 1+e*math.Cos(nu) <= 0 {
+				// beyond the hyperbolic asymptote
+				continue
+			}
+			mean, err := TrueToMeanAnomaly(nu, e)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := MeanToTrueAnomaly(mean, e)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(math.Remainder(got-nu, TWOPI)) > 1e-9 {
+				t.Errorf("e %f: expected true anomaly %f, got %f", e, nu, got)
+			}
+		}
+	}
+
+	if _, err := MeanToTrueAnomaly(1, 1); !errors.Is(err, ErrParabolicOrbit) {
+		t.Errorf("expected %v, got %v", ErrParabolicOrbit, err)
+	}
+}
+
+func TestEquinoctialRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements KeplerianElements
+	}{
+		{"elliptical inclined", KeplerianElements{SemiMajorAxis: 26600, Eccentricity: 0.74, Inclination: 63.4 * DEG2RAD, RightAscensionOfAscendingNode: 1.2, ArgumentOfPerigee: 4.71, TrueAnomaly: 0.3}},
+		{"near circular near equatorial", KeplerianElements{SemiMajorAxis: 42164, Eccentricity: 1e-12, Inclination: 1e-12, RightAscensionOfAscendingNode: 1.2, ArgumentOfPerigee: 0.7, TrueAnomaly: 2.9}},
+		{"retrograde", KeplerianElements{SemiMajorAxis: 7100, Eccentricity: 0.01, Inclination: 98.7 * DEG2RAD, RightAscensionOfAscendingNode: 5.1, ArgumentOfPerigee: 0.7, TrueAnomaly: 2.9}},
+		{"retrograde equatorial", KeplerianElements{SemiMajorAxis: 7100, Eccentricity: 0.01, Inclination: math.Pi, ArgumentOfPerigee: 0.7, TrueAnomaly: 2.9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos, vel, err := KeplerianToState(tt.elements, GRAVITY_EARTH)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			eq, err := StateToEquinoctial(pos, vel, GRAVITY_EARTH)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(eq.SemiMajorAxis-tt.elements.SemiMajorAxis) > 1e-6 {
+				t.Errorf("expected semi-major axis %f, got %f", tt.elements.SemiMajorAxis, eq.SemiMajorAxis)
+			}
+			if got := math.Hypot(eq.H, eq.K); math.Abs(got-tt.elements.Eccentricity) > 1e-9 {
+				t.Errorf("expected eccentricity %g, got %g", tt.elements.Eccentricity, got)
+			}
+			pos2, vel2, err := EquinoctialToState(eq, GRAVITY_EARTH)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !pos2.Equals(pos) || !vel2.Equals(vel) {
+				t.Errorf("expected state %v %v, got %v %v", pos, vel, pos2, vel2)
+			}
+		})
+	}
+}
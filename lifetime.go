@@ -0,0 +1,276 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var ErrNoReentry = errors.New("no reentry within the maximum duration")
+var ErrInvalidBallisticCoefficient = errors.New("ballistic coefficient must be positive")
+
+// Number of points along the orbit at which drag is averaged
+const lifetimeOrbitSamples = 36
+
+// Longest step of the decay integration, short enough to follow solar activity changes
+const lifetimeMaxStep = 5 * SECONDS_IN_DAY
+
+// LifetimeSettings configures an orbital lifetime estimate
+type LifetimeSettings struct {
+	// Cd*A/m in m^2/kg, derived from BSTAR when zero and estimating from a TLE
+	BallisticCoefficient float64
+	// Relative uncertainty of the ballistic coefficient bounding the reentry window, e.g. 0.2 for 20%
+	BallisticUncertainty float64
+
+	// Density for the nominal solar activity projection
+	Atmosphere Atmosphere
+	// Optional densities for the high and low solar activity projections bounding the reentry window
+	HighActivity Atmosphere
+	LowActivity  Atmosphere
+
+	// Perigee height in km at which the object is considered to reenter, defaults to 120 km
+	ReentryAltitude float64
+	// Longest time to search for reentry, defaults to 25 years
+	MaxDuration time.Duration
+}
+
+// LifetimeEstimate is a predicted reentry epoch with the window from the high and low decay projections
+type LifetimeEstimate struct {
+	Epoch    time.Time
+	Reentry  time.Time
+	Earliest time.Time
+	Latest   time.Time
+}
+
+// Returns the nominal time from epoch to reentry
+func (l LifetimeEstimate) Lifetime() time.Duration {
+	return l.Reentry.Sub(l.Epoch)
+}
+
+// Converts a TLE BSTAR drag term (1/earth radii) to a ballistic coefficient Cd*A/m in m^2/kg
+func BallisticCoefficientFromBStar(bstar float64) float64 {
+	return 2 * bstar / BSTAR_REFERENCE_DENSITY
+}
+
+// decayingOrbit holds the slowly varying elements of a decaying orbit, angles in radians
+type decayingOrbit struct {
+	semiMajorAxis float64
+	// squared eccentricity, which stays smooth as the orbit circularizes
+	eccentricitySqr float64
+	inclination     float64
+	raan            float64
+	argp            float64
+}
+
+func (o decayingOrbit) eccentricity() float64 {
+	return math.Sqrt(o.eccentricitySqr)
+}
+
+func (o decayingOrbit) perigee() float64 {
+	return o.semiMajorAxis * (1 - o.eccentricity())
+}
+
+// Estimates the reentry epoch of a TLE by integrating the orbit averaged decay of its mean elements
+func EstimateLifetimeFromTLE(line1, line2 string, gravConst Gravity, settings LifetimeSettings) (LifetimeEstimate, error) {
+	sat, err := TLEToSat(line1, line2, gravConst)
+	if err != nil {
+		return LifetimeEstimate{}, fmt.Errorf("TLEToSat: %w", err)
+	}
+	if settings.BallisticCoefficient == 0 {
+		settings.BallisticCoefficient = BallisticCoefficientFromBStar(sat.bstar)
+	}
+
+	grav := sat.GravityConst
+	orbit := decayingOrbit{
+		semiMajorAxis:   sat.meanSemiMajorAxis(),
+		eccentricitySqr: sat.ecco * sat.ecco,
+		inclination:     sat.inclo,
+		raan:            sat.nodeo,
+		argp:            sat.argpo,
+	}
+	return estimateLifetime(sat.Tle.EpochTime(), orbit, grav, settings)
+}
+
+// Estimates the reentry epoch from a position (km) and velocity (km/s) at epoch.
+// The osculating elements are used as mean elements, so the estimate carries the short periodic J2 error in the
+// semi-major axis.
+func EstimateLifetime(epoch time.Time, pos, vel Vector3, gravConst Gravity, settings LifetimeSettings) (LifetimeEstimate, error) {
+	grav, err := getGravConst(gravConst)
+	if err != nil {
+		return LifetimeEstimate{}, fmt.Errorf("getGravConst: %w", err)
+	}
+	el, err := StateToKeplerian(pos, vel, grav.mu)
+	if err != nil {
+		return LifetimeEstimate{}, fmt.Errorf("StateToKeplerian: %w", err)
+	}
+	if el.Eccentricity >= 1 {
+		return LifetimeEstimate{}, fmt.Errorf("%w: eccentricity is %f", ErrInvalidMeanEccentricity, el.Eccentricity)
+	}
+	orbit := decayingOrbit{
+		semiMajorAxis:   el.SemiMajorAxis,
+		eccentricitySqr: el.Eccentricity * el.Eccentricity,
+		inclination:     el.Inclination,
+		raan:            el.RightAscensionOfAscendingNode,
+		argp:            el.ArgumentOfPerigee,
+	}
+	return estimateLifetime(epoch, orbit, grav, settings)
+}
+
+func estimateLifetime(epoch time.Time, orbit decayingOrbit, grav GravConst, settings LifetimeSettings) (LifetimeEstimate, error) {
+	b := settings.BallisticCoefficient
+	if !(b > 0) || math.IsInf(b, 0) {
+		return LifetimeEstimate{}, fmt.Errorf("%w: %f m^2/kg", ErrInvalidBallisticCoefficient, b)
+	}
+	if settings.Atmosphere == nil {
+		return LifetimeEstimate{}, errors.New("no atmosphere for the lifetime estimate")
+	}
+	if settings.ReentryAltitude == 0 {
+		settings.ReentryAltitude = 120
+	}
+	if settings.MaxDuration == 0 {
+		settings.MaxDuration = 25 * 365 * 24 * time.Hour
+	}
+	high := settings.HighActivity
+	if high == nil {
+		high = settings.Atmosphere
+	}
+	low := settings.LowActivity
+	if low == nil {
+		low = settings.Atmosphere
+	}
+	u := settings.BallisticUncertainty
+
+	estimate := LifetimeEstimate{Epoch: epoch}
+	var err error
+	estimate.Reentry, err = reentryTime(epoch, orbit, grav, b, settings.Atmosphere, settings)
+	if err != nil {
+		return estimate, fmt.Errorf("nominal: %w", err)
+	}
+	estimate.Earliest, err = reentryTime(epoch, orbit, grav, b*(1+u), high, settings)
+	if err != nil {
+		return estimate, fmt.Errorf("high decay: %w", err)
+	}
+	if u >= 1 {
+		// no lower bound on the drag, the window stays open
+		estimate.Latest = epoch.Add(settings.MaxDuration)
+		return estimate, nil
+	}
+	estimate.Latest, err = reentryTime(epoch, orbit, grav, b*(1-u), low, settings)
+	if errors.Is(err, ErrNoReentry) {
+		estimate.Latest = epoch.Add(settings.MaxDuration)
+		return estimate, nil
+	}
+	if err != nil {
+		return estimate, fmt.Errorf("low decay: %w", err)
+	}
+	return estimate, nil
+}
+
+// reentryTime integrates the orbit averaged decay with a midpoint rule until the perigee drops below the reentry height
+func reentryTime(epoch time.Time, orbit decayingOrbit, grav GravConst, b float64, atmosphere Atmosphere, settings LifetimeSettings) (time.Time, error) {
+	reentryRadius := grav.radiusearthkm + settings.ReentryAltitude
+	maxSeconds := settings.MaxDuration.Seconds()
+	at := func(seconds float64) time.Time {
+		return epoch.Add(time.Duration(seconds * float64(time.Second)))
+	}
+
+	seconds := 0.0
+	for seconds < maxSeconds {
+		if orbit.perigee() < reentryRadius {
+			return at(seconds), nil
+		}
+		rates, err := decayRates(at(seconds), orbit, grav, b, atmosphere)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		period := TWOPI * math.Sqrt(orbit.semiMajorAxis*orbit.semiMajorAxis*orbit.semiMajorAxis/grav.mu)
+		dt := lifetimeMaxStep
+		if rates.semiMajorAxis < 0 {
+			dt = math.Min(dt, 0.01*(orbit.semiMajorAxis-reentryRadius)/-rates.semiMajorAxis)
+		}
+		dt = math.Max(dt, period)
+
+		midRates, err := decayRates(at(seconds+dt/2), rates.step(orbit, dt/2), grav, b, atmosphere)
+		if err != nil {
+			return time.Time{}, err
+		}
+		next := midRates.step(orbit, dt)
+
+		if next.perigee() < reentryRadius {
+			// linear interpolation of the perigee within the last step
+			fraction := (orbit.perigee() - reentryRadius) / (orbit.perigee() - next.perigee())
+			return at(seconds + fraction*dt), nil
+		}
+		orbit = next
+		seconds += dt
+	}
+	return time.Time{}, fmt.Errorf("%w: perigee height is %f km after %v", ErrNoReentry, orbit.perigee()-grav.radiusearthkm, settings.MaxDuration)
+}
+
+// decayingOrbitRates are the time derivatives of a decayingOrbit per second
+type decayingOrbitRates struct {
+	semiMajorAxis   float64
+	eccentricitySqr float64
+	raan            float64
+	argp            float64
+}
+
+func (r decayingOrbitRates) step(orbit decayingOrbit, dt float64) decayingOrbit {
+	orbit.semiMajorAxis += r.semiMajorAxis * dt
+	orbit.eccentricitySqr = math.Max(0, orbit.eccentricitySqr+r.eccentricitySqr*dt)
+	orbit.raan = wrapTwoPi(orbit.raan + r.raan*dt)
+	orbit.argp = wrapTwoPi(orbit.argp + r.argp*dt)
+	return orbit
+}
+
+// decayRates averages the drag rates of the semi-major axis and squared eccentricity over the mean anomaly and adds
+// the secular J2 rates of the node and argument of perigee
+func decayRates(t time.Time, orbit decayingOrbit, grav GravConst, b float64, atmosphere Atmosphere) (decayingOrbitRates, error) {
+	mu := grav.mu
+	a := orbit.semiMajorAxis
+	e := orbit.eccentricity()
+	el := KeplerianElements{
+		SemiMajorAxis:                 a,
+		Eccentricity:                  e,
+		Inclination:                   orbit.inclination,
+		RightAscensionOfAscendingNode: orbit.raan,
+		ArgumentOfPerigee:             orbit.argp,
+	}
+	drag := Drag{Atmosphere: atmosphere, BallisticCoefficient: b}
+
+	var rates decayingOrbitRates
+	for k := 0; k < lifetimeOrbitSamples; k++ {
+		var err error
+		el.TrueAnomaly, err = MeanToTrueAnomaly(TWOPI*float64(k)/lifetimeOrbitSamples, e)
+		if err != nil {
+			return rates, fmt.Errorf("MeanToTrueAnomaly: %w", err)
+		}
+		pos, vel, err := KeplerianToState(el, mu)
+		if err != nil {
+			return rates, fmt.Errorf("KeplerianToState: %w", err)
+		}
+		accel, err := drag.Acceleration(t, pos, vel)
+		if err != nil {
+			return rates, fmt.Errorf("drag: %w", err)
+		}
+
+		// energy gives da/dt, angular momentum with e^2 = 1 - h^2/(mu a) gives de^2/dt
+		aDot := 2 * a * a / mu * vel.Dot(accel)
+		h := pos.Cross(vel)
+		hDot := pos.Cross(accel)
+		rates.semiMajorAxis += aDot
+		rates.eccentricitySqr += -2*h.Dot(hDot)/(mu*a) + h.Dot(h)/(mu*a*a)*aDot
+	}
+	rates.semiMajorAxis /= lifetimeOrbitSamples
+	rates.eccentricitySqr /= lifetimeOrbitSamples
+
+	p := a * (1 - e*e)
+	n := math.Sqrt(mu / (a * a * a))
+	k := grav.j2 * (grav.radiusearthkm / p) * (grav.radiusearthkm / p)
+	sinI2 := math.Sin(orbit.inclination) * math.Sin(orbit.inclination)
+	rates.raan = -1.5 * n * k * math.Cos(orbit.inclination)
+	rates.argp = 0.75 * n * k * (4 - 5*sinI2)
+	return rates, nil
+}
@@ -0,0 +1,113 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite/internal/testsat"
+)
+
+// sphericalExponentialAtmosphere has a single scale height above a spherical Earth
+type sphericalExponentialAtmosphere struct {
+	rho0, height0, scaleHeight float64
+}
+
+func (s sphericalExponentialAtmosphere) Density(pos Vector3, t time.Time) (float64, error) {
+	height := pos.Norm() - EQUATOR_RADIUS
+	return s.rho0 * math.Exp(-(height-s.height0)/s.scaleHeight), nil
+}
+
+func TestEstimateLifetimeCircular(t *testing.T) {
+	grav, err := getGravConst(GravityWGS84)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	atmosphere := sphericalExponentialAtmosphere{rho0: 3.7e-12, height0: 400, scaleHeight: 58}
+	b := 0.02
+	a0 := EQUATOR_RADIUS + 400
+	reentryRadius := EQUATOR_RADIUS + 120
+
+	// polar circular orbit, the rotating atmosphere barely changes the relative speed
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	speed := math.Sqrt(grav.mu / a0)
+	pos := Vector3{X: a0}
+	vel := Vector3{Z: speed}
+	estimate, err := EstimateLifetime(epoch, pos, vel, GravityWGS84, LifetimeSettings{
+		BallisticCoefficient: b,
+		BallisticUncertainty: 0.2,
+		Atmosphere:           atmosphere,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// integrate dt = -da / (B rho sqrt(mu a)), the equatorial and WGS84 radii differ by 1 m
+	decay := func(a float64) float64 {
+		rho, _ := atmosphere.Density(Vector3{X: a}, epoch)
+		return b * rho * math.Sqrt(grav.mu*a) * 1e3
+	}
+	steps := 10000
+	h := (a0 - reentryRadius) / float64(steps)
+	sum := 1/decay(reentryRadius) + 1/decay(a0)
+	for i := 1; i < steps; i++ {
+		weight := 2.0
+		if i%2 == 1 {
+			weight = 4
+		}
+		sum += weight / decay(reentryRadius+float64(i)*h)
+	}
+	expected := sum * h / 3
+
+	lifetime := estimate.Lifetime().Seconds()
+	if math.Abs(lifetime-expected)/expected > 0.02 {
+		t.Errorf("expected lifetime %.1f days, got %.1f days", expected/SECONDS_IN_DAY, lifetime/SECONDS_IN_DAY)
+	}
+	if !(estimate.Earliest.Before(estimate.Reentry) && estimate.Reentry.Before(estimate.Latest)) {
+		t.Errorf("reentry %v outside window %v to %v", estimate.Reentry, estimate.Earliest, estimate.Latest)
+	}
+	// lifetime scales with 1/B
+	earliest := estimate.Earliest.Sub(epoch).Seconds()
+	if math.Abs(earliest*1.2-lifetime)/lifetime > 0.02 {
+		t.Errorf("expected earliest reentry after %.1f days, got %.1f days", lifetime/1.2/SECONDS_IN_DAY, earliest/SECONDS_IN_DAY)
+	}
+}
+
+func TestEstimateLifetimeFromTLE(t *testing.T) {
+	line1 := testsat.ISSLine1
+	line2 := testsat.ISSLine2
+	settings := LifetimeSettings{
+		BallisticCoefficient: 0.005,
+		Atmosphere:           ExponentialAtmosphere{},
+	}
+	estimate, err := EstimateLifetimeFromTLE(line1, line2, GravityWGS72, settings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// an unboosted space station at 420 km decays within a few years
+	lifetime := estimate.Lifetime()
+	if lifetime < 100*24*time.Hour || lifetime > 5*365*24*time.Hour {
+		t.Errorf("unexpected lifetime %v", lifetime)
+	}
+
+	settings.BallisticCoefficient *= 2
+	shorter, err := EstimateLifetimeFromTLE(line1, line2, GravityWGS72, settings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !shorter.Reentry.Before(estimate.Reentry) {
+		t.Errorf("expected earlier reentry with more drag, %v and %v", shorter.Reentry, estimate.Reentry)
+	}
+
+	settings.MaxDuration = 30 * 24 * time.Hour
+	if _, err := EstimateLifetimeFromTLE(line1, line2, GravityWGS72, settings); !errors.Is(err, ErrNoReentry) {
+		t.Errorf("expected ErrNoReentry, got %v", err)
+	}
+
+	settings = LifetimeSettings{Atmosphere: ExponentialAtmosphere{}}
+	line1 = "1 25544U 98067A   20140.34419374 -.00000374  00000-0  00000-0 0  9990"
+	if _, err := EstimateLifetimeFromTLE(line1, line2, GravityWGS72, settings); !errors.Is(err, ErrInvalidBallisticCoefficient) {
+		t.Errorf("expected ErrInvalidBallisticCoefficient, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package satellite
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrNoForces = errors.New("numerical propagator has no force models")
+
+// NumericalPropagator integrates the equations of motion in Cowell's form with an adaptive Dormand-Prince 5(4)
+// integrator. Every call integrates from the epoch state to the requested time.
+// Forces must include a gravity field for the central body, e.g. PointMassGravityField or ReadGravityField.
+type NumericalPropagator struct {
+	Epoch    time.Time
+	Position Vector3
+	Velocity Vector3
+	Forces   []ForceModel
+
+	// Per component error tolerance in km and km/s, defaults to 1e-9 relative and 1e-9 absolute
+	RelativeTolerance float64
+	AbsoluteTolerance float64
+	// Largest integration step, defaults to 300 seconds
+	MaxStep time.Duration
+}
+
+// Builds a numerical propagator from a position (km) and velocity (km/s) at epoch with the given forces
+func NewNumericalPropagator(epoch time.Time, pos, vel Vector3, forces ...ForceModel) NumericalPropagator {
+	return NumericalPropagator{
+		Epoch:             epoch,
+		Position:          pos,
+		Velocity:          vel,
+		Forces:            forces,
+		RelativeTolerance: 1e-9,
+		AbsoluteTolerance: 1e-9,
+		MaxStep:           300 * time.Second,
+	}
+}
+
+// Returns the sum of all force model accelerations in km/s^2
+func (p NumericalPropagator) Acceleration(t time.Time, pos, vel Vector3) (Vector3, error) {
+	var total Vector3
+	for i, force := range p.Forces {
+		accel, err := force.Acceleration(t, pos, vel)
+		if err != nil {
+			return Vector3{}, fmt.Errorf("force %d %T: %w", i, force, err)
+		}
+		total = total.Add(accel)
+	}
+	return total, nil
+}
+
+// Calculates position and velocity vectors for given time
+func (p NumericalPropagator) PositionVelocity(t time.Time) (position, velocity Vector3, err error) {
+	if len(p.Forces) == 0 {
+		return position, velocity, ErrNoForces
+	}
+
+	settings := integratorSettings{
+		absolute:    p.AbsoluteTolerance,
+		relative:    p.RelativeTolerance,
+		initialStep: 10,
+		maxStep:     p.MaxStep.Seconds(),
+		maxSteps:    1000000,
+	}
+	if settings.absolute <= 0 {
+		settings.absolute = 1e-9
+	}
+	if settings.relative <= 0 {
+		settings.relative = 1e-9
+	}
+	if settings.maxStep <= 0 {
+		settings.maxStep = 300
+	}
+
+	f := func(seconds float64, y [6]float64) ([6]float64, error) {
+		pos := Vector3{X: y[0], Y: y[1], Z: y[2]}
+		vel := Vector3{X: y[3], Y: y[4], Z: y[5]}
+		accel, err := p.Acceleration(p.Epoch.Add(time.Duration(seconds*float64(time.Second))), pos, vel)
+		if err != nil {
+			return [6]float64{}, err
+		}
+		return [6]float64{vel.X, vel.Y, vel.Z, accel.X, accel.Y, accel.Z}, nil
+	}
+
+	y0 := [6]float64{p.Position.X, p.Position.Y, p.Position.Z, p.Velocity.X, p.Velocity.Y, p.Velocity.Z}
+	y, err := integrate(f, 0, y0, t.Sub(p.Epoch).Seconds(), settings)
+	if err != nil {
+		return position, velocity, err
+	}
+	position = Vector3{X: y[0], Y: y[1], Z: y[2]}
+	velocity = Vector3{X: y[3], Y: y[4], Z: y[5]}
+	if !position.IsFinite() || !velocity.IsFinite() {
+		return Vector3{}, Vector3{}, fmt.Errorf("%w: position %v velocity %v", ErrNonFiniteState, position, velocity)
+	}
+	return position, velocity, nil
+}
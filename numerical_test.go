@@ -0,0 +1,197 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestNumericalPropagatorTwoBody(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	pos, vel, err := KeplerianToState(KeplerianElements{SemiMajorAxis: 26600, Eccentricity: 0.74, Inclination: 63.4 * DEG2RAD, RightAscensionOfAscendingNode: 1.2, ArgumentOfPerigee: 4.71, TrueAnomaly: 0.3}, GRAVITY_EARTH)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kepler, err := NewKeplerPropagator(epoch, pos, vel, GRAVITY_EARTH)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	numerical := NewNumericalPropagator(epoch, pos, vel, PointMassGravityField(GRAVITY_EARTH))
+	numerical.RelativeTolerance = 1e-12
+	numerical.AbsoluteTolerance = 1e-12
+
+	for _, offset := range []time.Duration{-6 * time.Hour, time.Hour, 24 * time.Hour} {
+		want, wantVel, err := kepler.PositionVelocity(epoch.Add(offset))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, gotVel, err := numerical.PositionVelocity(epoch.Add(offset))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d := got.Sub(want).Norm(); d > 1e-3 {
+			t.Errorf("%v: expected position within 1 m of two-body, got %f km", offset, d)
+		}
+		if d := gotVel.Sub(wantVel).Norm(); d > 1e-6 {
+			t.Errorf("%v: expected velocity within 1 mm/s of two-body, got %g km/s", offset, d)
+		}
+	}
+}
+
+func TestNumericalPropagatorZonal(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	field, err := ZonalGravityField(GravityWGS72)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pos, vel, err := KeplerianToState(KeplerianElements{SemiMajorAxis: 7078, Eccentricity: 0.001, Inclination: 98.19 * DEG2RAD, RightAscensionOfAscendingNode: 0.5, ArgumentOfPerigee: 1, TrueAnomaly: 2}, field.Mu)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p := NewNumericalPropagator(epoch, pos, vel, field)
+
+	endPos, endVel, err := p.PositionVelocity(epoch.Add(24 * time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// axial symmetry conserves the polar component of angular momentum
+	hz0 := pos.Cross(vel).Z
+	hz1 := endPos.Cross(endVel).Z
+	if math.Abs(hz1-hz0) > 1e-8*math.Abs(hz0) {
+		t.Errorf("expected angular momentum z %f, got %f", hz0, hz1)
+	}
+
+	el, err := StateToKeplerian(endPos, endVel, field.Mu)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// node regression of a sun synchronous orbit, short periodic terms allow some slack
+	drift := (el.RightAscensionOfAscendingNode - 0.5) * RAD2DEG
+	if math.Abs(drift-0.9856) > 0.02 {
+		t.Errorf("expected node drift of 0.9856 deg/day, got %f", drift)
+	}
+}
+
+func TestNumericalPropagatorDrag(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	pos, vel, err := KeplerianToState(KeplerianElements{SemiMajorAxis: EQUATOR_RADIUS + 300, Eccentricity: 0.0001, Inclination: 51.6 * DEG2RAD, TrueAnomaly: 1}, GRAVITY_EARTH)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p := NewNumericalPropagator(epoch, pos, vel,
+		PointMassGravityField(GRAVITY_EARTH),
+		Drag{Atmosphere: ExponentialAtmosphere{}, BallisticCoefficient: 2.2 * 0.01},
+		SolarRadiationPressure{Coefficient: 1.3 * 0.01},
+		SunThirdBody,
+		MoonThirdBody,
+	)
+
+	endPos, endVel, err := p.PositionVelocity(epoch.Add(24 * time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	el, err := StateToKeplerian(endPos, endVel, GRAVITY_EARTH)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// circular orbit decay per revolution is 2 pi B rho a^2
+	a := (EQUATOR_RADIUS + 300) * 1000
+	revolutions := 86400 / (TWOPI * math.Sqrt(a*a*a/(GRAVITY_EARTH*1e9)))
+	want := TWOPI * 2.2 * 0.01 * 2.418e-11 * a * a * revolutions / 1000
+	decay := EQUATOR_RADIUS + 300 - el.SemiMajorAxis
+	if decay < 0.5*want || decay > 1.5*want {
+		t.Errorf("expected semi-major axis to decay by about %f km in a day, got %f km", want, decay)
+	}
+}
+
+func TestSunMoonPosition(t *testing.T) {
+	// Vallado, Fundamentals of Astrodynamics and Applications, example 5-1
+	sun := SunPosition(time.Date(2006, 4, 2, 0, 0, 0, 0, time.UTC))
+	wantSun := Vector3{X: 0.9771945, Y: 0.1924424, Z: 0.0834308}
+	if d := sun.Scale(1 / ASTRONOMICAL_UNIT).Sub(wantSun).Norm(); d > 1e-5 {
+		t.Errorf("expected sun %v AU, got %v", wantSun, sun.Scale(1/ASTRONOMICAL_UNIT))
+	}
+
+	// Vallado, Fundamentals of Astrodynamics and Applications, example 5-3
+	moon := MoonPosition(time.Date(1994, 4, 28, 0, 0, 0, 0, time.UTC))
+	wantMoon := Vector3{X: -134240.626, Y: -311571.590, Z: -126693.785}
+	if d := moon.Sub(wantMoon).Norm(); d > 1 {
+		t.Errorf("expected moon %v km, got %v", wantMoon, moon)
+	}
+}
+
+func TestGravityField(t *testing.T) {
+	file, err := os.Open("testdata/egm96_to4.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer file.Close()
+	field, err := ReadGravityField(file, 4, 4, 398600.4415, 6378.1363)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c20, _, err := field.Coefficient(2, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(-c20-1.0826267e-3) > 1e-9 {
+		t.Errorf("expected J2 1.0826267e-3, got %g", -c20)
+	}
+
+	// the zonal part alone must match the closed form J2 acceleration
+	j2Only := newGravityField(field.Mu, field.RadiusEarth, 2, 0)
+	j2Only.c[2][0] = c20
+	pos := Vector3{X: 4000, Y: -3000, Z: 5000}
+	got := j2Only.EarthFixedAcceleration(pos)
+	r := pos.Norm()
+	k := 1.5 * -c20 * (field.RadiusEarth / r) * (field.RadiusEarth / r)
+	z2 := pos.Z * pos.Z / (r * r)
+	m := -field.Mu / (r * r * r)
+	want := Vector3{
+		X: m * pos.X * (1 - k*(5*z2-1)),
+		Y: m * pos.Y * (1 - k*(5*z2-1)),
+		Z: m * pos.Z * (1 - k*(5*z2-3)),
+	}
+	if d := got.Sub(want).Norm(); d > 1e-15 {
+		t.Errorf("expected acceleration %v, got %v", want, got)
+	}
+
+	// the tesseral terms are a small perturbation on top of that
+	full := field.EarthFixedAcceleration(pos)
+	d := full.Sub(want).Norm() / want.Norm()
+	if d == 0 || d > 1e-4 {
+		t.Errorf("expected degree 4 field to differ from J2 by a small fraction, got %g", d)
+	}
+
+	// degree and order out of range are clamped to the coefficients held
+	for _, limits := range [][2]int{{4, 9}, {9, 4}, {9, 9}} {
+		clamped := field
+		clamped.Degree, clamped.Order = limits[0], limits[1]
+		if got := clamped.EarthFixedAcceleration(pos); got != full {
+			t.Errorf("degree %d order %d: expected acceleration %v, got %v", limits[0], limits[1], full, got)
+		}
+	}
+	truncated := field
+	truncated.Degree, truncated.Order = 2, -1
+	if got := truncated.EarthFixedAcceleration(pos); got.Sub(want).Norm() > 1e-15 {
+		t.Errorf("expected the field truncated to J2 to give %v, got %v", want, got)
+	}
+	if _, _, err := truncated.Coefficient(3, 0); !errors.Is(err, ErrInvalidDegree) {
+		t.Errorf("expected ErrInvalidDegree above the truncated degree, got %v", err)
+	}
+	handBuilt := GravityField{Mu: field.Mu, RadiusEarth: field.RadiusEarth, Degree: 4, Order: 4}
+	if got := handBuilt.EarthFixedAcceleration(pos); got.Sub(pos.Scale(m)).Norm() > 1e-15 {
+		t.Errorf("expected a field without coefficients to be a point mass, got %v", got)
+	}
+	if _, _, err := handBuilt.Coefficient(2, 0); !errors.Is(err, ErrInvalidDegree) {
+		t.Errorf("expected ErrInvalidDegree without coefficients, got %v", err)
+	}
+
+	if _, err := ReadGravityField(file, 2, 3, 398600.4415, 6378.1363); err == nil {
+		t.Errorf("expected error for order above degree")
+	}
+}
@@ -0,0 +1,125 @@
+package oem
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Reads an OEM in keyword value notation
+func ReadKVN(r io.Reader) (OEM, error) {
+	var o OEM
+	var segment *Segment
+	inMetadata := false
+	inCovariance := false
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case line == "COVARIANCE_START":
+			inCovariance = true
+			continue
+		case line == "COVARIANCE_STOP":
+			inCovariance = false
+			continue
+		case inCovariance:
+			continue
+		case line == "META_START":
+			o.Segments = append(o.Segments, Segment{})
+			segment = &o.Segments[len(o.Segments)-1]
+			inMetadata = true
+			continue
+		case line == "META_STOP":
+			if !inMetadata {
+				return OEM{}, fmt.Errorf("%w: line %d: META_STOP without META_START", ErrInvalidOEM, lineNumber)
+			}
+			inMetadata = false
+			continue
+		case strings.HasPrefix(line, "COMMENT"):
+			comment := strings.TrimSpace(strings.TrimPrefix(line, "COMMENT"))
+			switch {
+			case segment == nil:
+				o.Comments = append(o.Comments, comment)
+			case inMetadata:
+				segment.Metadata.Comments = append(segment.Metadata.Comments, comment)
+			default:
+				segment.Comments = append(segment.Comments, comment)
+			}
+			continue
+		}
+
+		if equals := strings.Index(line, "="); equals >= 0 {
+			if segment != nil && !inMetadata {
+				return OEM{}, fmt.Errorf("%w: line %d: keyword outside of metadata", ErrInvalidOEM, lineNumber)
+			}
+			key := strings.TrimSpace(line[:equals])
+			value := strings.TrimSpace(line[equals+1:])
+			var metadata *Metadata
+			if segment != nil {
+				metadata = &segment.Metadata
+			}
+			if err := o.set(metadata, key, value); err != nil {
+				return OEM{}, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			continue
+		}
+
+		if segment == nil || inMetadata {
+			return OEM{}, fmt.Errorf("%w: line %d: state outside of a data section", ErrInvalidOEM, lineNumber)
+		}
+		state, err := parseState(strings.Fields(line))
+		if err != nil {
+			return OEM{}, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		segment.States = append(segment.States, state)
+	}
+	if err := scanner.Err(); err != nil {
+		return OEM{}, err
+	}
+	if err := o.validate(); err != nil {
+		return OEM{}, err
+	}
+	return o, nil
+}
+
+// Writes an OEM in keyword value notation
+func WriteKVN(w io.Writer, o OEM) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	comments := func(lines []string) {
+		for _, line := range lines {
+			fmt.Fprintf(bw, "COMMENT %s\n", line)
+		}
+	}
+	fields := func(kvs []keyValue) {
+		for _, kv := range kvs {
+			fmt.Fprintf(bw, "%-20s = %s\n", kv.key, kv.value)
+		}
+	}
+
+	header := o.headerFields()
+	fields(header[:1])
+	comments(o.Comments)
+	fields(header[1:])
+
+	for _, s := range o.Segments {
+		fmt.Fprintln(bw)
+		fmt.Fprintln(bw, "META_START")
+		comments(s.Metadata.Comments)
+		fields(s.Metadata.fields())
+		fmt.Fprintln(bw, "META_STOP")
+		fmt.Fprintln(bw)
+		comments(s.Comments)
+		for _, state := range s.States {
+			fmt.Fprintln(bw, strings.Join(state.values(), " "))
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,429 @@
+// Package oem reads and writes CCSDS Orbit Ephemeris Messages (CCSDS 502.0-B-2) in KVN and XML.
+//
+// States are in km and km/s. Covariance sections are skipped when reading and never written.
+package oem
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+)
+
+var ErrInvalidOEM = errors.New("invalid orbit ephemeris message")
+var ErrUnsupportedFrame = errors.New("unsupported reference frame")
+
+// Reference frames the package can convert to and from TEME
+const (
+	FrameTEME    = "TEME"
+	FrameEME2000 = "EME2000"
+	// Earth fixed, converted with the polar motion and UT1-UTC of a satellite.EarthOrientation
+	FrameITRF = "ITRF2000"
+)
+
+type OEM struct {
+	Version      string
+	CreationDate time.Time
+	Originator   string
+	// Optional, introduced with version 3.0
+	MessageID string
+	Comments  []string
+
+	Segments []Segment
+}
+
+type Segment struct {
+	Metadata Metadata
+	// Comments at the start of the data section
+	Comments []string
+	States   []State
+}
+
+type Metadata struct {
+	Comments []string
+
+	ObjectName string
+	ObjectID   string
+	CenterName string
+	RefFrame   string
+	// Epoch of the frame, only for frames that need one
+	RefFrameEpoch string
+	TimeSystem    string
+
+	StartTime time.Time
+	// Optional span over which the ephemeris is usable
+	UseableStartTime time.Time
+	UseableStopTime  time.Time
+	StopTime         time.Time
+
+	// Optional recommended interpolation method and degree
+	Interpolation       string
+	InterpolationDegree int
+}
+
+type State struct {
+	Epoch time.Time
+	// km
+	Position satellite.Vector3
+	// km/s
+	Velocity satellite.Vector3
+	// Optional, km/s^2
+	Acceleration *satellite.Vector3
+}
+
+// SampleSettings configures the sampling of a propagator into a segment
+type SampleSettings struct {
+	ObjectName string
+	ObjectID   string
+	Start      time.Time
+	Stop       time.Time
+	Step       time.Duration
+	// One of FrameTEME, FrameEME2000 or FrameITRF, defaults to TEME
+	RefFrame string
+	// Polar motion and UT1-UTC, such as a satellite.EOP read from CelesTrak, required for FrameITRF
+	EarthOrientation satellite.EarthOrientation
+	// Recommended interpolation written to the metadata, defaults to LAGRANGE of degree 7
+	Interpolation       string
+	InterpolationDegree int
+}
+
+// Samples a propagator returning TEME states, such as a satellite.Satellite, into a segment in the requested frame.
+// The time system is UTC and the stop time is always sampled.
+func Sample(p satellite.Propagator, settings SampleSettings) (Segment, error) {
+	if settings.Step <= 0 || !settings.Stop.After(settings.Start) {
+		return Segment{}, fmt.Errorf("%w: %v to %v every %v", satellite.ErrInvalidSampling, settings.Start, settings.Stop, settings.Step)
+	}
+	if settings.RefFrame == "" {
+		settings.RefFrame = FrameTEME
+	}
+	if settings.Interpolation == "" {
+		settings.Interpolation = "LAGRANGE"
+		if settings.InterpolationDegree == 0 {
+			settings.InterpolationDegree = 7
+		}
+	}
+
+	segment := Segment{Metadata: Metadata{
+		ObjectName:          settings.ObjectName,
+		ObjectID:            settings.ObjectID,
+		CenterName:          "EARTH",
+		RefFrame:            settings.RefFrame,
+		TimeSystem:          "UTC",
+		StartTime:           settings.Start.UTC(),
+		StopTime:            settings.Stop.UTC(),
+		Interpolation:       settings.Interpolation,
+		InterpolationDegree: settings.InterpolationDegree,
+	}}
+	for t := settings.Start; ; t = t.Add(settings.Step) {
+		if t.After(settings.Stop) {
+			t = settings.Stop
+		}
+		pos, vel, err := p.PositionVelocity(t)
+		if err != nil {
+			return Segment{}, fmt.Errorf("position at %v: %w", t, err)
+		}
+		pos, vel, err = fromTEME(settings.RefFrame, pos, vel, t, settings.EarthOrientation)
+		if err != nil {
+			return Segment{}, err
+		}
+		segment.States = append(segment.States, State{Epoch: t.UTC(), Position: pos, Velocity: vel})
+		if !t.Before(settings.Stop) {
+			break
+		}
+	}
+	return segment, nil
+}
+
+func fromTEME(frame string, pos, vel satellite.Vector3, t time.Time, eop satellite.EarthOrientation) (satellite.Vector3, satellite.Vector3, error) {
+	switch frame {
+	case FrameTEME:
+		return pos, vel, nil
+	case FrameEME2000:
+		pos, vel = satellite.TEMEToJ2000(pos, vel, t)
+		return pos, vel, nil
+	case FrameITRF:
+		if eop == nil {
+			return satellite.Vector3{}, satellite.Vector3{}, fmt.Errorf("%w: %s needs earth orientation parameters", satellite.ErrNoEOP, frame)
+		}
+		return satellite.TEMEToITRF(pos, vel, t, eop)
+	}
+	return satellite.Vector3{}, satellite.Vector3{}, fmt.Errorf("%w: %q", ErrUnsupportedFrame, frame)
+}
+
+func toTEME(frame string, pos, vel satellite.Vector3, t time.Time, eop satellite.EarthOrientation) (satellite.Vector3, satellite.Vector3, error) {
+	switch {
+	case frame == FrameTEME:
+		return pos, vel, nil
+	case frame == FrameEME2000:
+		pos, vel = satellite.J2000ToTEME(pos, vel, t)
+		return pos, vel, nil
+	case strings.HasPrefix(frame, "ITRF"):
+		if eop == nil {
+			return satellite.Vector3{}, satellite.Vector3{}, fmt.Errorf("%w: %s needs earth orientation parameters", satellite.ErrNoEOP, frame)
+		}
+		return satellite.ITRFToTEME(pos, vel, t, eop)
+	}
+	return satellite.Vector3{}, satellite.Vector3{}, fmt.Errorf("%w: %q", ErrUnsupportedFrame, frame)
+}
+
+// Returns the states of the segment converted to TEME, the frame of satellite.ECIToLookAngles.
+// EME2000, TEME and ITRF frames centered on the Earth with UTC epochs are supported, ITRF needs the earth orientation
+// parameters and they are ignored, and may be nil, for the other frames.
+func (s Segment) TEMEStates(eop satellite.EarthOrientation) ([]State, error) {
+	if s.Metadata.CenterName != "EARTH" {
+		return nil, fmt.Errorf("%w: center %q", ErrUnsupportedFrame, s.Metadata.CenterName)
+	}
+	if s.Metadata.TimeSystem != "UTC" {
+		return nil, fmt.Errorf("%w: time system %q", ErrUnsupportedFrame, s.Metadata.TimeSystem)
+	}
+	states := make([]State, len(s.States))
+	for i, state := range s.States {
+		pos, vel, err := toTEME(s.Metadata.RefFrame, state.Position, state.Velocity, state.Epoch, eop)
+		if err != nil {
+			return nil, err
+		}
+		states[i] = State{Epoch: state.Epoch, Position: pos, Velocity: vel}
+	}
+	return states, nil
+}
+
+// Returns an ephemeris of the segment in TEME so it can be used as a satellite.Propagator, e.g. for look angles.
+// The recommended interpolation of the metadata is used, defaulting to Lagrange of degree 7 and Hermite of degree 5.
+// The earth orientation parameters are used as in TEMEStates.
+func (s Segment) Ephemeris(eop satellite.EarthOrientation) (satellite.Ephemeris, error) {
+	states, err := s.TEMEStates(eop)
+	if err != nil {
+		return satellite.Ephemeris{}, err
+	}
+	method := satellite.InterpolationLagrange
+	degree := s.Metadata.InterpolationDegree
+	if s.Metadata.Interpolation == "HERMITE" {
+		method = satellite.InterpolationHermite
+		if degree == 0 {
+			degree = 5
+		}
+	}
+	if degree == 0 {
+		degree = 7
+	}
+	samples := make([]satellite.EphemerisState, len(states))
+	for i, state := range states {
+		samples[i] = satellite.EphemerisState{Time: state.Epoch, Position: state.Position, Velocity: state.Velocity}
+	}
+	return satellite.NewEphemeris(samples, method, degree)
+}
+
+// Time layouts of CCSDS ASCII time codes A and B
+var timeLayouts = []string{"2006-01-02T15:04:05.999999999", "2006-002T15:04:05.999999999"}
+
+func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "Z")
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: time %q", ErrInvalidOEM, s)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000")
+}
+
+// formatOptionalTime leaves unset times empty
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return formatTime(t)
+}
+
+func parseFloat(s string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%w: %q", satellite.ErrNonFiniteValue, s)
+	}
+	return f, nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// keyValue is a keyword as written in the message
+type keyValue struct {
+	key   string
+	value string
+}
+
+func (o OEM) headerFields() []keyValue {
+	version := o.Version
+	if version == "" {
+		version = "2.0"
+	}
+	kvs := []keyValue{
+		{"CCSDS_OEM_VERS", version},
+		{"CREATION_DATE", formatTime(o.CreationDate)},
+		{"ORIGINATOR", o.Originator},
+	}
+	if o.MessageID != "" {
+		kvs = append(kvs, keyValue{"MESSAGE_ID", o.MessageID})
+	}
+	return kvs
+}
+
+func (m Metadata) fields() []keyValue {
+	kvs := []keyValue{
+		{"OBJECT_NAME", m.ObjectName},
+		{"OBJECT_ID", m.ObjectID},
+		{"CENTER_NAME", m.CenterName},
+		{"REF_FRAME", m.RefFrame},
+	}
+	if m.RefFrameEpoch != "" {
+		kvs = append(kvs, keyValue{"REF_FRAME_EPOCH", m.RefFrameEpoch})
+	}
+	kvs = append(kvs, keyValue{"TIME_SYSTEM", m.TimeSystem}, keyValue{"START_TIME", formatTime(m.StartTime)})
+	if useable := formatOptionalTime(m.UseableStartTime); useable != "" {
+		kvs = append(kvs, keyValue{"USEABLE_START_TIME", useable})
+	}
+	if useable := formatOptionalTime(m.UseableStopTime); useable != "" {
+		kvs = append(kvs, keyValue{"USEABLE_STOP_TIME", useable})
+	}
+	kvs = append(kvs, keyValue{"STOP_TIME", formatTime(m.StopTime)})
+	if m.Interpolation != "" {
+		kvs = append(kvs, keyValue{"INTERPOLATION", m.Interpolation})
+		if m.InterpolationDegree > 0 {
+			kvs = append(kvs, keyValue{"INTERPOLATION_DEGREE", strconv.Itoa(m.InterpolationDegree)})
+		}
+	}
+	return kvs
+}
+
+// set assigns a header keyword when metadata is nil, otherwise a metadata keyword
+func (o *OEM) set(metadata *Metadata, key, value string) error {
+	var err error
+	timestamp := func(dst *time.Time) {
+		*dst, err = parseTime(value)
+	}
+	if metadata == nil {
+		switch key {
+		case "CCSDS_OEM_VERS":
+			o.Version = value
+		case "CREATION_DATE":
+			timestamp(&o.CreationDate)
+		case "ORIGINATOR":
+			o.Originator = value
+		case "MESSAGE_ID":
+			o.MessageID = value
+		default:
+			return fmt.Errorf("%w: unexpected header keyword %s", ErrInvalidOEM, key)
+		}
+		return wrapField(key, err)
+	}
+
+	switch key {
+	case "OBJECT_NAME":
+		metadata.ObjectName = value
+	case "OBJECT_ID":
+		metadata.ObjectID = value
+	case "CENTER_NAME":
+		metadata.CenterName = value
+	case "REF_FRAME":
+		metadata.RefFrame = value
+	case "REF_FRAME_EPOCH":
+		metadata.RefFrameEpoch = value
+	case "TIME_SYSTEM":
+		metadata.TimeSystem = value
+	case "START_TIME":
+		timestamp(&metadata.StartTime)
+	case "USEABLE_START_TIME":
+		timestamp(&metadata.UseableStartTime)
+	case "USEABLE_STOP_TIME":
+		timestamp(&metadata.UseableStopTime)
+	case "STOP_TIME":
+		timestamp(&metadata.StopTime)
+	case "INTERPOLATION":
+		metadata.Interpolation = value
+	case "INTERPOLATION_DEGREE":
+		metadata.InterpolationDegree, err = strconv.Atoi(value)
+	default:
+		return fmt.Errorf("%w: unexpected metadata keyword %s", ErrInvalidOEM, key)
+	}
+	return wrapField(key, err)
+}
+
+func wrapField(key string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, ErrInvalidOEM) {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return fmt.Errorf("%w: %s: %v", ErrInvalidOEM, key, err)
+}
+
+// parseState reads the epoch, position, velocity and optional acceleration of a state
+func parseState(values []string) (State, error) {
+	if len(values) != 7 && len(values) != 10 {
+		return State{}, fmt.Errorf("%w: state has %d values, want 7 or 10", ErrInvalidOEM, len(values))
+	}
+	epoch, err := parseTime(values[0])
+	if err != nil {
+		return State{}, err
+	}
+	var numbers [9]float64
+	for i, v := range values[1:] {
+		if numbers[i], err = parseFloat(v); err != nil {
+			return State{}, fmt.Errorf("%w: %v", ErrInvalidOEM, err)
+		}
+	}
+	state := State{
+		Epoch:    epoch,
+		Position: satellite.Vector3{X: numbers[0], Y: numbers[1], Z: numbers[2]},
+		Velocity: satellite.Vector3{X: numbers[3], Y: numbers[4], Z: numbers[5]},
+	}
+	if len(values) == 10 {
+		state.Acceleration = &satellite.Vector3{X: numbers[6], Y: numbers[7], Z: numbers[8]}
+	}
+	return state, nil
+}
+
+func (s State) values() []string {
+	values := []string{
+		formatTime(s.Epoch),
+		formatFloat(s.Position.X), formatFloat(s.Position.Y), formatFloat(s.Position.Z),
+		formatFloat(s.Velocity.X), formatFloat(s.Velocity.Y), formatFloat(s.Velocity.Z),
+	}
+	if s.Acceleration != nil {
+		values = append(values, formatFloat(s.Acceleration.X), formatFloat(s.Acceleration.Y), formatFloat(s.Acceleration.Z))
+	}
+	return values
+}
+
+func (o OEM) validate() error {
+	if len(o.Segments) == 0 {
+		return fmt.Errorf("%w: no segments", ErrInvalidOEM)
+	}
+	for i, s := range o.Segments {
+		m := s.Metadata
+		if m.ObjectName == "" || m.ObjectID == "" || m.CenterName == "" || m.RefFrame == "" || m.TimeSystem == "" {
+			return fmt.Errorf("%w: segment %d: missing mandatory metadata", ErrInvalidOEM, i)
+		}
+		if m.StartTime.IsZero() || m.StopTime.IsZero() {
+			return fmt.Errorf("%w: segment %d: missing start or stop time", ErrInvalidOEM, i)
+		}
+		for j, state := range s.States {
+			if j > 0 && !state.Epoch.After(s.States[j-1].Epoch) {
+				return fmt.Errorf("%w: segment %d: epochs are not increasing at state %d", ErrInvalidOEM, i, j)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package oem
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+	"github.com/infostellarinc/go-satellite/internal/testsat"
+)
+
+func readSample(t *testing.T) OEM {
+	t.Helper()
+	f, err := os.Open("testdata/sample.oem")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	o, err := ReadKVN(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return o
+}
+
+func TestReadKVN(t *testing.T) {
+	o := readSample(t)
+	if o.Version != "2.0" || o.Originator != "PARTNER" || len(o.Comments) != 1 {
+		t.Errorf("unexpected header %+v", o)
+	}
+	if len(o.Segments) != 2 {
+		t.Fatalf("expected 2 segments, got %d", len(o.Segments))
+	}
+	s := o.Segments[0]
+	m := s.Metadata
+	if m.ObjectName != "ISS (ZARYA)" || m.RefFrame != FrameEME2000 || m.Interpolation != "HERMITE" || m.InterpolationDegree != 5 {
+		t.Errorf("unexpected metadata %+v", m)
+	}
+	if !m.UseableStopTime.Equal(time.Date(2020, 5, 23, 0, 2, 0, 0, time.UTC)) {
+		t.Errorf("unexpected useable stop time %v", m.UseableStopTime)
+	}
+	if len(m.Comments) != 1 || len(s.Comments) != 1 {
+		t.Errorf("expected metadata and data comments, got %v and %v", m.Comments, s.Comments)
+	}
+	if len(s.States) != 3 {
+		t.Fatalf("expected 3 states, got %d", len(s.States))
+	}
+	last := s.States[2]
+	if !last.Epoch.Equal(time.Date(2020, 5, 23, 0, 2, 0, 0, time.UTC)) {
+		t.Errorf("expected a day of year epoch to parse, got %v", last.Epoch)
+	}
+	if last.Acceleration == nil || last.Acceleration.Z != -0.005530 || s.States[0].Acceleration != nil {
+		t.Errorf("expected an acceleration on the last state only")
+	}
+	if s.States[0].Position.X != -4085.172963 || s.States[0].Velocity.Z != -0.029384 {
+		t.Errorf("unexpected first state %+v", s.States[0])
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	o := readSample(t)
+	for _, format := range []struct {
+		name  string
+		write func(*bytes.Buffer, OEM) error
+		read  func(*bytes.Buffer) (OEM, error)
+	}{
+		{"kvn", func(b *bytes.Buffer, o OEM) error { return WriteKVN(b, o) }, func(b *bytes.Buffer) (OEM, error) { return ReadKVN(b) }},
+		{"xml", func(b *bytes.Buffer, o OEM) error { return WriteXML(b, o) }, func(b *bytes.Buffer) (OEM, error) { return ReadXML(b) }},
+	} {
+		t.Run(format.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := format.write(&buf, o); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := format.read(&buf)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got.Segments) != len(o.Segments) || len(got.Comments) != len(o.Comments) {
+				t.Fatalf("expected %d segments, got %d", len(o.Segments), len(got.Segments))
+			}
+			for i := range o.Segments {
+				want, have := o.Segments[i], got.Segments[i]
+				if have.Metadata.RefFrame != want.Metadata.RefFrame || !have.Metadata.StartTime.Equal(want.Metadata.StartTime) ||
+					have.Metadata.InterpolationDegree != want.Metadata.InterpolationDegree ||
+					len(have.Metadata.Comments) != len(want.Metadata.Comments) || len(have.Comments) != len(want.Comments) {
+					t.Errorf("segment %d metadata %+v, want %+v", i, have.Metadata, want.Metadata)
+				}
+				if len(have.States) != len(want.States) {
+					t.Fatalf("segment %d has %d states, want %d", i, len(have.States), len(want.States))
+				}
+				for j := range want.States {
+					if !have.States[j].Epoch.Equal(want.States[j].Epoch) || have.States[j].Position != want.States[j].Position ||
+						have.States[j].Velocity != want.States[j].Velocity ||
+						(have.States[j].Acceleration == nil) != (want.States[j].Acceleration == nil) {
+						t.Errorf("segment %d state %d is %+v, want %+v", i, j, have.States[j], want.States[j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSample(t *testing.T) {
+	sat := testsat.ISS(t, satellite.TLEToSat, satellite.GravityWGS72)
+	start := time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+	eop := satellite.ConstantOrientation{X: 0.1, Y: 0.4, UT1MinusUTC: -0.2}
+	for _, frame := range []string{FrameTEME, FrameEME2000, FrameITRF} {
+		t.Run(frame, func(t *testing.T) {
+			segment, err := Sample(sat, SampleSettings{
+				ObjectName:       "ISS (ZARYA)",
+				ObjectID:         "1998-067A",
+				Start:            start,
+				Stop:             start.Add(10*time.Minute + 30*time.Second),
+				Step:             time.Minute,
+				RefFrame:         frame,
+				EarthOrientation: eop,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(segment.States) != 12 || segment.Metadata.RefFrame != frame || segment.Metadata.InterpolationDegree != 7 {
+				t.Fatalf("expected 12 states in %s, got %d in %+v", frame, len(segment.States), segment.Metadata)
+			}
+			if !segment.States[11].Epoch.Equal(segment.Metadata.StopTime) {
+				t.Errorf("expected the stop time to be sampled")
+			}
+
+			states, err := segment.TEMEStates(eop)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, state := range states {
+				pos, vel, err := satellite.Propagate(sat, state.Epoch)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !state.Position.EqualsWithin(pos, 1e-8) || !state.Velocity.EqualsWithin(vel, 1e-11) {
+					t.Errorf("TEME state at %v is %v %v, want %v %v", state.Epoch, state.Position, state.Velocity, pos, vel)
+				}
+			}
+
+			var buf bytes.Buffer
+			if err := WriteKVN(&buf, OEM{CreationDate: start, Originator: "TEST", Segments: []Segment{segment}}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(buf.String(), "REF_FRAME            = "+frame+"\n") {
+				t.Errorf("expected the frame in the metadata:\n%s", buf.String())
+			}
+		})
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	sat := testsat.ISS(t, satellite.TLEToSat, satellite.GravityWGS72)
+	start := time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+	if _, err := Sample(sat, SampleSettings{Start: start, Stop: start.Add(time.Hour), Step: time.Minute, RefFrame: "GCRF"}); !errors.Is(err, ErrUnsupportedFrame) {
+		t.Errorf("expected ErrUnsupportedFrame, got %v", err)
+	}
+	if _, err := Sample(sat, SampleSettings{Start: start, Stop: start.Add(time.Hour), Step: time.Minute, RefFrame: FrameITRF}); !errors.Is(err, satellite.ErrNoEOP) {
+		t.Errorf("expected ErrNoEOP, got %v", err)
+	}
+	if _, err := Sample(sat, SampleSettings{Start: start, Stop: start, Step: time.Minute}); !errors.Is(err, satellite.ErrInvalidSampling) {
+		t.Errorf("expected ErrInvalidSampling, got %v", err)
+	}
+	for _, input := range []string{
+		"CCSDS_OEM_VERS = 2.0\n",
+		"CCSDS_OEM_VERS = 2.0\nMETA_START\nOBJECT_NAME = X\nMETA_STOP\n",
+		"CCSDS_OEM_VERS = 2.0\nMETA_START\nOBJECT_NAME = X\nOBJECT_ID = X\nCENTER_NAME = EARTH\nREF_FRAME = TEME\nTIME_SYSTEM = UTC\nSTART_TIME = 2020-01-01T00:00:00\nSTOP_TIME = 2020-01-01T00:00:00\nMETA_STOP\n2020-01-01T00:00:00 1 2 3\n",
+		"CCSDS_OEM_VERS = 2.0\nBOGUS = 1\n",
+	} {
+		if _, err := ReadKVN(strings.NewReader(input)); !errors.Is(err, ErrInvalidOEM) {
+			t.Errorf("expected ErrInvalidOEM for %q, got %v", input, err)
+		}
+	}
+	if _, err := ReadXML(strings.NewReader("<oem>")); !errors.Is(err, ErrInvalidOEM) {
+		t.Errorf("expected ErrInvalidOEM, got %v", err)
+	}
+}
+
+func TestSegmentEphemeris(t *testing.T) {
+	sat := testsat.ISS(t, satellite.TLEToSat, satellite.GravityWGS72)
+	start := time.Date(2020, 5, 23, 20, 0, 0, 0, time.UTC)
+	segment, err := Sample(sat, SampleSettings{
+		ObjectName: "ISS (ZARYA)",
+		ObjectID:   "1998-067A",
+		Start:      start,
+		Stop:       start.Add(time.Hour),
+		Step:       time.Minute,
+		RefFrame:   FrameEME2000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ephemeris, err := segment.Ephemeris(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	station := satellite.Geodetic(55.6167, 12.65, 0.005)
+	at := time.Date(2020, 5, 23, 20, 23, 37, 0, time.UTC)
+	got, err := satellite.PropagatorLookAngles(ephemeris, station, at, sat.GravityConst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want, err := satellite.PropagatorLookAngles(sat, station, at, sat.GravityConst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(got.Azimuth.Degrees()-want.Azimuth.Degrees()) > 1e-4 || math.Abs(got.Elevation.Degrees()-want.Elevation.Degrees()) > 1e-4 {
+		t.Errorf("look angles from the ephemeris %v %v, want %v %v", got.Azimuth, got.Elevation, want.Azimuth, want.Elevation)
+	}
+}
@@ -0,0 +1,206 @@
+package oem
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type xmlOEM struct {
+	XMLName xml.Name     `xml:"oem"`
+	ID      string       `xml:"id,attr"`
+	Version string       `xml:"version,attr"`
+	Header  xmlHeader    `xml:"header"`
+	Body    []xmlSegment `xml:"body>segment"`
+}
+
+type xmlHeader struct {
+	Comments     []string `xml:"COMMENT"`
+	CreationDate string   `xml:"CREATION_DATE"`
+	Originator   string   `xml:"ORIGINATOR"`
+	MessageID    string   `xml:"MESSAGE_ID,omitempty"`
+}
+
+type xmlSegment struct {
+	Metadata xmlMetadata `xml:"metadata"`
+	Data     xmlData     `xml:"data"`
+}
+
+type xmlMetadata struct {
+	Comments            []string `xml:"COMMENT"`
+	ObjectName          string   `xml:"OBJECT_NAME"`
+	ObjectID            string   `xml:"OBJECT_ID"`
+	CenterName          string   `xml:"CENTER_NAME"`
+	RefFrame            string   `xml:"REF_FRAME"`
+	RefFrameEpoch       string   `xml:"REF_FRAME_EPOCH,omitempty"`
+	TimeSystem          string   `xml:"TIME_SYSTEM"`
+	StartTime           string   `xml:"START_TIME"`
+	UseableStartTime    string   `xml:"USEABLE_START_TIME,omitempty"`
+	UseableStopTime     string   `xml:"USEABLE_STOP_TIME,omitempty"`
+	StopTime            string   `xml:"STOP_TIME"`
+	Interpolation       string   `xml:"INTERPOLATION,omitempty"`
+	InterpolationDegree string   `xml:"INTERPOLATION_DEGREE,omitempty"`
+}
+
+type xmlData struct {
+	Comments     []string         `xml:"COMMENT"`
+	StateVectors []xmlStateVector `xml:"stateVector"`
+}
+
+type xmlStateVector struct {
+	Epoch string     `xml:"EPOCH"`
+	X     xmlNumber  `xml:"X"`
+	Y     xmlNumber  `xml:"Y"`
+	Z     xmlNumber  `xml:"Z"`
+	XDot  xmlNumber  `xml:"X_DOT"`
+	YDot  xmlNumber  `xml:"Y_DOT"`
+	ZDot  xmlNumber  `xml:"Z_DOT"`
+	XDDot *xmlNumber `xml:"X_DDOT"`
+	YDDot *xmlNumber `xml:"Y_DDOT"`
+	ZDDot *xmlNumber `xml:"Z_DDOT"`
+}
+
+type xmlNumber struct {
+	Units string `xml:"units,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+// Reads an OEM in the CCSDS NDM/XML schema
+func ReadXML(r io.Reader) (OEM, error) {
+	var doc xmlOEM
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return OEM{}, fmt.Errorf("%w: %v", ErrInvalidOEM, err)
+	}
+
+	o := OEM{Version: doc.Version, Comments: doc.Header.Comments}
+	header := []keyValue{
+		{"CREATION_DATE", doc.Header.CreationDate},
+		{"ORIGINATOR", doc.Header.Originator},
+		{"MESSAGE_ID", doc.Header.MessageID},
+	}
+	if err := setAll(&o, nil, header); err != nil {
+		return OEM{}, err
+	}
+
+	for _, xs := range doc.Body {
+		segment := Segment{Comments: xs.Data.Comments}
+		m := xs.Metadata
+		segment.Metadata.Comments = m.Comments
+		metadata := []keyValue{
+			{"OBJECT_NAME", m.ObjectName},
+			{"OBJECT_ID", m.ObjectID},
+			{"CENTER_NAME", m.CenterName},
+			{"REF_FRAME", m.RefFrame},
+			{"REF_FRAME_EPOCH", m.RefFrameEpoch},
+			{"TIME_SYSTEM", m.TimeSystem},
+			{"START_TIME", m.StartTime},
+			{"USEABLE_START_TIME", m.UseableStartTime},
+			{"USEABLE_STOP_TIME", m.UseableStopTime},
+			{"STOP_TIME", m.StopTime},
+			{"INTERPOLATION", m.Interpolation},
+			{"INTERPOLATION_DEGREE", m.InterpolationDegree},
+		}
+		if err := setAll(&o, &segment.Metadata, metadata); err != nil {
+			return OEM{}, err
+		}
+
+		for _, sv := range xs.Data.StateVectors {
+			values := []string{sv.Epoch, sv.X.Value, sv.Y.Value, sv.Z.Value, sv.XDot.Value, sv.YDot.Value, sv.ZDot.Value}
+			if sv.XDDot != nil && sv.YDDot != nil && sv.ZDDot != nil {
+				values = append(values, sv.XDDot.Value, sv.YDDot.Value, sv.ZDDot.Value)
+			}
+			state, err := parseState(values)
+			if err != nil {
+				return OEM{}, err
+			}
+			segment.States = append(segment.States, state)
+		}
+		o.Segments = append(o.Segments, segment)
+	}
+	if err := o.validate(); err != nil {
+		return OEM{}, err
+	}
+	return o, nil
+}
+
+// setAll assigns the keywords that are present
+func setAll(o *OEM, metadata *Metadata, kvs []keyValue) error {
+	for _, kv := range kvs {
+		if kv.value == "" {
+			continue
+		}
+		if err := o.set(metadata, kv.key, kv.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writes an OEM in the CCSDS NDM/XML schema
+func WriteXML(w io.Writer, o OEM) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+	header := o.headerFields()
+	doc := xmlOEM{
+		ID:      "CCSDS_OEM_VERS",
+		Version: header[0].value,
+		Header: xmlHeader{
+			Comments:     o.Comments,
+			CreationDate: formatTime(o.CreationDate),
+			Originator:   o.Originator,
+			MessageID:    o.MessageID,
+		},
+	}
+	for _, s := range o.Segments {
+		m := s.Metadata
+		xs := xmlSegment{
+			Metadata: xmlMetadata{
+				Comments:         m.Comments,
+				ObjectName:       m.ObjectName,
+				ObjectID:         m.ObjectID,
+				CenterName:       m.CenterName,
+				RefFrame:         m.RefFrame,
+				RefFrameEpoch:    m.RefFrameEpoch,
+				TimeSystem:       m.TimeSystem,
+				StartTime:        formatTime(m.StartTime),
+				UseableStartTime: formatOptionalTime(m.UseableStartTime),
+				UseableStopTime:  formatOptionalTime(m.UseableStopTime),
+				StopTime:         formatTime(m.StopTime),
+				Interpolation:    m.Interpolation,
+			},
+			Data: xmlData{Comments: s.Comments},
+		}
+		if m.Interpolation != "" && m.InterpolationDegree > 0 {
+			xs.Metadata.InterpolationDegree = strconv.Itoa(m.InterpolationDegree)
+		}
+		for _, state := range s.States {
+			values := state.values()
+			km := func(v string) xmlNumber { return xmlNumber{Units: "km", Value: v} }
+			kms := func(v string) xmlNumber { return xmlNumber{Units: "km/s", Value: v} }
+			sv := xmlStateVector{
+				Epoch: values[0],
+				X:     km(values[1]), Y: km(values[2]), Z: km(values[3]),
+				XDot: kms(values[4]), YDot: kms(values[5]), ZDot: kms(values[6]),
+			}
+			if len(values) == 10 {
+				kms2 := func(v string) *xmlNumber { return &xmlNumber{Units: "km/s**2", Value: v} }
+				sv.XDDot, sv.YDDot, sv.ZDDot = kms2(values[7]), kms2(values[8]), kms2(values[9])
+			}
+			xs.Data.StateVectors = append(xs.Data.StateVectors, sv)
+		}
+		doc.Body = append(doc.Body, xs)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
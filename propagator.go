@@ -0,0 +1,120 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Propagator is any source of position (km) and velocity (km/s) in an Earth centered inertial frame.
+// Satellite propagates with sgp4 and returns TEME states, KeplerPropagator and J2Propagator return states in the frame
+// of the state they were built from.
+type Propagator interface {
+	PositionVelocity(t time.Time) (position, velocity Vector3, err error)
+}
+
+// Calculates position and velocity vectors with sgp4 for given time
+func (sat Satellite) PositionVelocity(t time.Time) (position, velocity Vector3, err error) {
+	return Propagate(sat, t)
+}
+
+// Calculate look angles from an observer to any propagator at the given time
+func PropagatorLookAngles(p Propagator, obsCoords Coordinates, t time.Time, grav GravConst) (LookAngles, error) {
+	pos, _, err := p.PositionVelocity(t)
+	if err != nil {
+		return LookAngles{}, err
+	}
+	return ECIToLookAngles(pos, obsCoords, JDayTime(t), grav), nil
+}
+
+// KeplerPropagator propagates an orbit as a two-body problem, only the anomaly changes with time
+type KeplerPropagator struct {
+	Epoch    time.Time
+	Elements KeplerianElements
+	Mu       float64
+}
+
+// Builds a two-body propagator from a position (km) and velocity (km/s) at epoch
+func NewKeplerPropagator(epoch time.Time, pos, vel Vector3, mu float64) (KeplerPropagator, error) {
+	el, err := StateToKeplerian(pos, vel, mu)
+	if err != nil {
+		return KeplerPropagator{}, fmt.Errorf("StateToKeplerian: %w", err)
+	}
+	if math.IsInf(el.SemiMajorAxis, 0) {
+		return KeplerPropagator{}, ErrParabolicOrbit
+	}
+	return KeplerPropagator{Epoch: epoch, Elements: el, Mu: mu}, nil
+}
+
+// Calculates position and velocity vectors for given time
+func (p KeplerPropagator) PositionVelocity(t time.Time) (position, velocity Vector3, err error) {
+	el := p.Elements
+	n := math.Sqrt(p.Mu / math.Abs(el.SemiMajorAxis*el.SemiMajorAxis*el.SemiMajorAxis))
+	el.MeanAnomaly += n * t.Sub(p.Epoch).Seconds()
+	el.TrueAnomaly, err = MeanToTrueAnomaly(el.MeanAnomaly, el.Eccentricity)
+	if err != nil {
+		return position, velocity, fmt.Errorf("MeanToTrueAnomaly: %w", err)
+	}
+	return KeplerianToState(el, p.Mu)
+}
+
+// J2Propagator propagates an orbit with the secular effect of J2 on the node, argument of perigee and mean anomaly.
+// The elements at epoch are taken as mean elements.
+type J2Propagator struct {
+	Epoch    time.Time
+	Elements KeplerianElements
+
+	GravityConst GravConst
+
+	// secular rates in radians per second
+	raanDot float64
+	argpDot float64
+	meanDot float64
+}
+
+// Builds a secular J2 propagator from a position (km) and velocity (km/s) at epoch.
+// Only elliptical orbits are supported.
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, equations 9-41.
+func NewJ2Propagator(epoch time.Time, pos, vel Vector3, gravConst Gravity) (J2Propagator, error) {
+	grav, err := getGravConst(gravConst)
+	if err != nil {
+		return J2Propagator{}, fmt.Errorf("getGravConst: %w", err)
+	}
+	el, err := StateToKeplerian(pos, vel, grav.mu)
+	if err != nil {
+		return J2Propagator{}, fmt.Errorf("StateToKeplerian: %w", err)
+	}
+	if el.Eccentricity >= 1 {
+		return J2Propagator{}, fmt.Errorf("%w: eccentricity is %f", ErrInvalidMeanEccentricity, el.Eccentricity)
+	}
+
+	a := el.SemiMajorAxis
+	e := el.Eccentricity
+	n := math.Sqrt(grav.mu / (a * a * a))
+	reOverP := grav.radiusearthkm / el.SemilatusRectum
+	k := grav.j2 * reOverP * reOverP
+	sinI2 := math.Sin(el.Inclination) * math.Sin(el.Inclination)
+
+	return J2Propagator{
+		Epoch:        epoch,
+		Elements:     el,
+		GravityConst: grav,
+		raanDot:      -1.5 * n * k * math.Cos(el.Inclination),
+		argpDot:      0.75 * n * k * (4 - 5*sinI2),
+		meanDot:      n * (1 + 1.5*k*math.Sqrt(1-e*e)*(1-1.5*sinI2)),
+	}, nil
+}
+
+// Calculates position and velocity vectors for given time
+func (p J2Propagator) PositionVelocity(t time.Time) (position, velocity Vector3, err error) {
+	dt := t.Sub(p.Epoch).Seconds()
+	el := p.Elements
+	el.RightAscensionOfAscendingNode = wrapTwoPi(el.RightAscensionOfAscendingNode + p.raanDot*dt)
+	el.ArgumentOfPerigee = wrapTwoPi(el.ArgumentOfPerigee + p.argpDot*dt)
+	el.MeanAnomaly += p.meanDot * dt
+	el.TrueAnomaly, err = MeanToTrueAnomaly(el.MeanAnomaly, el.Eccentricity)
+	if err != nil {
+		return position, velocity, fmt.Errorf("MeanToTrueAnomaly: %w", err)
+	}
+	return KeplerianToState(el, p.GravityConst.mu)
+}
@@ -0,0 +1,99 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite/internal/testsat"
+)
+
+var _ Propagator = Satellite{}
+var _ Propagator = KeplerPropagator{}
+var _ Propagator = J2Propagator{}
+
+func TestKeplerPropagatorPeriod(t *testing.T) {
+	epoch := time.Date(2020, 5, 23, 20, 23, 37, 0, time.UTC)
+	pos, vel, err := KeplerianToState(KeplerianElements{SemiMajorAxis: 26600, Eccentricity: 0.74, Inclination: 63.4 * DEG2RAD, RightAscensionOfAscendingNode: 1.2, ArgumentOfPerigee: 4.71, TrueAnomaly: 0.3}, GRAVITY_EARTH)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p, err := NewKeplerPropagator(epoch, pos, vel, GRAVITY_EARTH)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	period := TWOPI * math.Sqrt(26600*26600*26600/GRAVITY_EARTH)
+	got, gotVel, err := p.PositionVelocity(epoch.Add(time.Duration(3 * period * float64(time.Second))))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Equals(pos) || !gotVel.Equals(vel) {
+		t.Errorf("expected state %v %v after three periods, got %v %v", pos, vel, got, gotVel)
+	}
+
+	half, _, err := p.PositionVelocity(epoch.Add(time.Duration(period / 2 * float64(time.Second))))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if half.Equals(pos) {
+		t.Errorf("expected state to move after half a period")
+	}
+}
+
+func TestKeplerPropagatorMatchesSGP4NearEpoch(t *testing.T) {
+	sat := testsat.ISS(t, TLEToSat, GravityWGS72)
+	epoch := time.Date(2020, 5, 23, 20, 0, 0, 0, time.UTC)
+	pos, vel, err := sat.PositionVelocity(epoch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p, err := NewKeplerPropagator(epoch, pos, vel, sat.GravityConst.mu)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// two-body drifts from sgp4 mostly through J2, a minute out both should agree within a few km
+	later := epoch.Add(time.Minute)
+	want, _, err := sat.PositionVelocity(later)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, _, err := p.PositionVelocity(later)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := got.Sub(want).Norm(); d > 2 {
+		t.Errorf("expected kepler within 2 km of sgp4, got %f km", d)
+	}
+}
+
+func TestJ2PropagatorSunSynchronous(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := 7078.0
+	// inclination giving a node rate of one revolution per year at this altitude
+	pos, vel, err := KeplerianToState(KeplerianElements{SemiMajorAxis: a, Eccentricity: 0.001, Inclination: 98.19 * DEG2RAD, RightAscensionOfAscendingNode: 0.5, ArgumentOfPerigee: 1, TrueAnomaly: 2}, 398600.8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p, err := NewJ2Propagator(epoch, pos, vel, GravityWGS72)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pos, vel, err = p.PositionVelocity(epoch.Add(24 * time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	el, err := StateToKeplerian(pos, vel, 398600.8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	drift := (el.RightAscensionOfAscendingNode - 0.5) * RAD2DEG
+	if math.Abs(drift-0.9856) > 0.01 {
+		t.Errorf("expected node drift of 0.9856 deg/day, got %f", drift)
+	}
+	if math.Abs(el.SemiMajorAxis-a) > 1e-6 {
+		t.Errorf("expected semi-major axis %f, got %f", a, el.SemiMajorAxis)
+	}
+}
@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Duration is a time.Duration written to JSON as a string such as "2m30s", numbers are read as seconds
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case float64:
+		*d = Duration(v * float64(time.Second))
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidProblem, err)
+		}
+		*d = Duration(parsed)
+	default:
+		return fmt.Errorf("%w: duration %s", ErrInvalidProblem, data)
+	}
+	return nil
+}
+
+func (m Mode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Mode) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "", "greedy":
+		*m = ModeGreedy
+	case "optimize":
+		*m = ModeOptimize
+	default:
+		return fmt.Errorf("%w: mode %q", ErrInvalidProblem, text)
+	}
+	return nil
+}
+
+// Reads a problem from JSON
+func ReadProblem(r io.Reader) (Problem, error) {
+	var p Problem
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&p); err != nil {
+		return Problem{}, fmt.Errorf("%w: %v", ErrInvalidProblem, err)
+	}
+	if _, err := p.validate(); err != nil {
+		return Problem{}, err
+	}
+	return p, nil
+}
+
+// Writes a schedule as indented JSON
+func WriteSchedule(w io.Writer, s Schedule) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
@@ -0,0 +1,407 @@
+// Package scheduler plans conflict free ground station contacts from access windows.
+//
+// A station serves one satellite at a time and needs its setup time before and teardown time after every contact.
+// Contacts fill their window or, when a neighbouring contact is in the way, the longest free part of it that still
+// meets the minimum duration. Satellites are contacted at most their quota of times.
+//
+// The greedy mode schedules windows by satellite priority, then earliest start. The optimizing mode starts from the
+// greedy schedule and searches for a schedule of higher score, where each second of contact is worth one plus the
+// satellite's priority. The search is a heuristic: each window either gets the longest free part left by the earlier
+// contacts or is skipped, so schedules that need an earlier contact cut short to make room for a later one are not
+// found and the result is not guaranteed to be optimal.
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+)
+
+var ErrInvalidProblem = errors.New("invalid scheduling problem")
+
+// Mode selects the scheduling algorithm
+type Mode int
+
+const (
+	ModeGreedy Mode = iota
+	ModeOptimize
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeGreedy:
+		return "greedy"
+	case ModeOptimize:
+		return "optimize"
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+type Satellite struct {
+	ID string `json:"id"`
+	// Higher is scheduled first, 0 is the lowest
+	Priority int `json:"priority,omitempty"`
+	// Largest number of contacts, 0 for no limit
+	MaxContacts int `json:"maxContacts,omitempty"`
+}
+
+type Station struct {
+	ID string `json:"id"`
+	// Reserved before and after each contact
+	SetupTime    Duration `json:"setupTime,omitempty"`
+	TeardownTime Duration `json:"teardownTime,omitempty"`
+}
+
+// Window is a span during which a satellite is visible from a station
+type Window struct {
+	Satellite string    `json:"satellite"`
+	Station   string    `json:"station"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+}
+
+type Constraints struct {
+	// Shortest contact worth scheduling
+	MinDuration Duration `json:"minDuration,omitempty"`
+	// Whether a satellite can only be in contact with one station at a time
+	SatelliteExclusive bool `json:"satelliteExclusive,omitempty"`
+}
+
+type Options struct {
+	Mode Mode `json:"mode"`
+	// Largest number of search steps of the optimizing mode, defaults to 100000
+	SearchLimit int `json:"searchLimit,omitempty"`
+}
+
+type Problem struct {
+	Satellites  []Satellite `json:"satellites"`
+	Stations    []Station   `json:"stations"`
+	Windows     []Window    `json:"windows"`
+	Constraints Constraints `json:"constraints"`
+	Options     Options     `json:"options"`
+}
+
+// Contact is a scheduled pass, the station is reserved from SetupStart to TeardownEnd
+type Contact struct {
+	Satellite   string    `json:"satellite"`
+	Station     string    `json:"station"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	SetupStart  time.Time `json:"setupStart"`
+	TeardownEnd time.Time `json:"teardownEnd"`
+}
+
+// Returns the length of the contact
+func (c Contact) Duration() time.Duration {
+	return c.End.Sub(c.Start)
+}
+
+type Schedule struct {
+	// Ordered by start, then satellite and station
+	Contacts []Contact `json:"contacts"`
+	// Windows without a contact
+	Unscheduled []Window `json:"unscheduled"`
+	Score       float64  `json:"score"`
+}
+
+// Returns a window for every access of the matrix, in the matrix order
+func WindowsFromAccess(m satellite.AccessMatrix) []Window {
+	var windows []Window
+	for _, access := range m.All() {
+		windows = append(windows, Window{Satellite: access.Satellite, Station: access.Station, Start: access.Start, End: access.End})
+	}
+	return windows
+}
+
+// span is a stretch of time, blocked spans are open so contacts can touch them
+type span struct {
+	start, end time.Time
+}
+
+// window is a window with its satellite and station as indices
+type window struct {
+	Window
+	index     int
+	satellite int
+	station   int
+	// score of the whole window
+	value int64
+}
+
+// state is a partial schedule
+type state struct {
+	problem  *Problem
+	windows  []window
+	contacts []Contact
+	// whether each window has a contact
+	scheduled         []bool
+	stationContacts   [][]span
+	satelliteContacts [][]span
+	// in weighted milliseconds so undoing a contact restores it exactly
+	score int64
+}
+
+func (p *Problem) validate() ([]window, error) {
+	satellites := map[string]int{}
+	for i, sat := range p.Satellites {
+		if _, ok := satellites[sat.ID]; ok || sat.ID == "" {
+			return nil, fmt.Errorf("%w: satellite id %q", ErrInvalidProblem, sat.ID)
+		}
+		if sat.Priority < 0 || sat.MaxContacts < 0 {
+			return nil, fmt.Errorf("%w: satellite %s: negative priority or quota", ErrInvalidProblem, sat.ID)
+		}
+		satellites[sat.ID] = i
+	}
+	stations := map[string]int{}
+	for i, station := range p.Stations {
+		if _, ok := stations[station.ID]; ok || station.ID == "" {
+			return nil, fmt.Errorf("%w: station id %q", ErrInvalidProblem, station.ID)
+		}
+		if station.SetupTime < 0 || station.TeardownTime < 0 {
+			return nil, fmt.Errorf("%w: station %s: negative setup or teardown time", ErrInvalidProblem, station.ID)
+		}
+		stations[station.ID] = i
+	}
+	if p.Constraints.MinDuration < 0 || p.Options.SearchLimit < 0 {
+		return nil, fmt.Errorf("%w: negative minimum duration or search limit", ErrInvalidProblem)
+	}
+	if p.Options.Mode != ModeGreedy && p.Options.Mode != ModeOptimize {
+		return nil, fmt.Errorf("%w: mode %v", ErrInvalidProblem, p.Options.Mode)
+	}
+
+	windows := make([]window, len(p.Windows))
+	for i, w := range p.Windows {
+		sat, ok := satellites[w.Satellite]
+		if !ok {
+			return nil, fmt.Errorf("%w: window %d: unknown satellite %q", ErrInvalidProblem, i, w.Satellite)
+		}
+		station, ok := stations[w.Station]
+		if !ok {
+			return nil, fmt.Errorf("%w: window %d: unknown station %q", ErrInvalidProblem, i, w.Station)
+		}
+		if w.End.Before(w.Start) {
+			return nil, fmt.Errorf("%w: window %d ends before it starts", ErrInvalidProblem, i)
+		}
+		windows[i] = window{Window: w, index: i, satellite: sat, station: station}
+		windows[i].value = p.value(sat, w.End.Sub(w.Start))
+	}
+	return windows, nil
+}
+
+// value is the score of a contact of a satellite in weighted milliseconds
+func (p *Problem) value(satellite int, d time.Duration) int64 {
+	return int64(1+p.Satellites[satellite].Priority) * d.Milliseconds()
+}
+
+// Schedules contacts in the windows of the problem
+func Solve(p Problem) (Schedule, error) {
+	windows, err := p.validate()
+	if err != nil {
+		return Schedule{}, err
+	}
+	s := newState(&p, windows)
+	s.greedy()
+	if p.Options.Mode == ModeGreedy {
+		return s.schedule(), nil
+	}
+
+	limit := p.Options.SearchLimit
+	if limit == 0 {
+		limit = 100000
+	}
+	search := searcher{state: newState(&p, windows), best: s, limit: limit}
+	search.prepare()
+	search.branch(0)
+	return search.best.schedule(), nil
+}
+
+func newState(p *Problem, windows []window) *state {
+	return &state{
+		problem:           p,
+		windows:           windows,
+		scheduled:         make([]bool, len(windows)),
+		stationContacts:   make([][]span, len(p.Stations)),
+		satelliteContacts: make([][]span, len(p.Satellites)),
+	}
+}
+
+// greedy schedules the windows by satellite priority, earliest start, longest window, then input order
+func (s *state) greedy() {
+	order := make([]window, len(s.windows))
+	copy(order, s.windows)
+	sort.SliceStable(order, func(a, b int) bool {
+		wa, wb := order[a], order[b]
+		pa, pb := s.problem.Satellites[wa.satellite].Priority, s.problem.Satellites[wb.satellite].Priority
+		switch {
+		case pa != pb:
+			return pa > pb
+		case !wa.Start.Equal(wb.Start):
+			return wa.Start.Before(wb.Start)
+		}
+		return wa.End.After(wb.End)
+	})
+	for _, w := range order {
+		if contact, ok := s.fit(w); ok {
+			s.add(w, contact)
+		}
+	}
+}
+
+// fit returns the longest contact in the window that is free of the scheduled contacts
+func (s *state) fit(w window) (Contact, bool) {
+	sat := s.problem.Satellites[w.satellite]
+	if sat.MaxContacts > 0 && len(s.satelliteContacts[w.satellite]) >= sat.MaxContacts {
+		return Contact{}, false
+	}
+	station := s.problem.Stations[w.station]
+	setup, teardown := time.Duration(station.SetupTime), time.Duration(station.TeardownTime)
+
+	// a contact at the station cannot start before the previous teardown and the next setup
+	var blocked []span
+	for _, c := range s.stationContacts[w.station] {
+		blocked = append(blocked, span{c.start.Add(-setup - teardown), c.end.Add(teardown + setup)})
+	}
+	if s.problem.Constraints.SatelliteExclusive {
+		blocked = append(blocked, s.satelliteContacts[w.satellite]...)
+	}
+	free, ok := longestFree(span{w.Start, w.End}, blocked)
+	if !ok || free.end.Sub(free.start) < time.Duration(s.problem.Constraints.MinDuration) {
+		return Contact{}, false
+	}
+	return Contact{
+		Satellite:   w.Satellite,
+		Station:     w.Station,
+		Start:       free.start,
+		End:         free.end,
+		SetupStart:  free.start.Add(-setup),
+		TeardownEnd: free.end.Add(teardown),
+	}, true
+}
+
+// longestFree returns the longest part of a span outside of the open blocked spans, the earliest of equal parts.
+// Parts of no length are not returned.
+func longestFree(within span, blocked []span) (span, bool) {
+	sorted := append([]span(nil), blocked...)
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a].start.Before(sorted[b].start) })
+
+	var best span
+	found := false
+	consider := func(candidate span) {
+		if candidate.end.Sub(candidate.start) > best.end.Sub(best.start) {
+			best, found = candidate, true
+		}
+	}
+	cursor := within.start
+	for _, b := range sorted {
+		if !b.end.After(cursor) {
+			continue
+		}
+		if !b.start.Before(within.end) {
+			break
+		}
+		consider(span{cursor, b.start})
+		cursor = b.end
+	}
+	consider(span{cursor, within.end})
+	return best, found
+}
+
+func (s *state) add(w window, c Contact) {
+	s.contacts = append(s.contacts, c)
+	s.scheduled[w.index] = true
+	s.stationContacts[w.station] = append(s.stationContacts[w.station], span{c.Start, c.End})
+	s.satelliteContacts[w.satellite] = append(s.satelliteContacts[w.satellite], span{c.Start, c.End})
+	s.score += s.problem.value(w.satellite, c.Duration())
+}
+
+// removeLast undoes the last add of the window
+func (s *state) removeLast(w window) {
+	c := s.contacts[len(s.contacts)-1]
+	s.contacts = s.contacts[:len(s.contacts)-1]
+	s.scheduled[w.index] = false
+	s.stationContacts[w.station] = s.stationContacts[w.station][:len(s.stationContacts[w.station])-1]
+	s.satelliteContacts[w.satellite] = s.satelliteContacts[w.satellite][:len(s.satelliteContacts[w.satellite])-1]
+	s.score -= s.problem.value(w.satellite, c.Duration())
+}
+
+// clone copies the contacts and score, enough to build a schedule
+func (s *state) clone() *state {
+	c := &state{problem: s.problem, windows: s.windows, score: s.score}
+	c.contacts = append([]Contact(nil), s.contacts...)
+	c.scheduled = append([]bool(nil), s.scheduled...)
+	return c
+}
+
+func (s *state) schedule() Schedule {
+	schedule := Schedule{Contacts: make([]Contact, len(s.contacts)), Unscheduled: []Window{}, Score: float64(s.score) / 1000}
+	copy(schedule.Contacts, s.contacts)
+	satellites, stations := map[string]int{}, map[string]int{}
+	for i, sat := range s.problem.Satellites {
+		satellites[sat.ID] = i
+	}
+	for i, station := range s.problem.Stations {
+		stations[station.ID] = i
+	}
+	sort.SliceStable(schedule.Contacts, func(a, b int) bool {
+		ca, cb := schedule.Contacts[a], schedule.Contacts[b]
+		switch {
+		case !ca.Start.Equal(cb.Start):
+			return ca.Start.Before(cb.Start)
+		case ca.Satellite != cb.Satellite:
+			return satellites[ca.Satellite] < satellites[cb.Satellite]
+		}
+		return stations[ca.Station] < stations[cb.Station]
+	})
+	for i, scheduled := range s.scheduled {
+		if !scheduled {
+			schedule.Unscheduled = append(schedule.Unscheduled, s.windows[i].Window)
+		}
+	}
+	return schedule
+}
+
+// searcher is a depth first branch and bound over the windows in chronological order, each window is either given
+// its longest free contact or skipped. Contacts are never trimmed to make room for later windows.
+type searcher struct {
+	*state
+	best  *state
+	order []window
+	// remaining[i] bounds the score of the windows from i on
+	remaining []int64
+	steps     int
+	limit     int
+}
+
+// prepare orders the windows and bounds the score of the windows left at each depth
+func (s *searcher) prepare() {
+	s.order = make([]window, len(s.windows))
+	copy(s.order, s.windows)
+	sort.SliceStable(s.order, func(a, b int) bool { return s.order[a].Start.Before(s.order[b].Start) })
+	s.remaining = make([]int64, len(s.order)+1)
+	for i := len(s.order) - 1; i >= 0; i-- {
+		s.remaining[i] = s.remaining[i+1] + s.order[i].value
+	}
+}
+
+func (s *searcher) branch(i int) {
+	if s.score > s.best.score {
+		s.best = s.clone()
+	}
+	if i == len(s.order) || s.score+s.remaining[i] <= s.best.score {
+		return
+	}
+	s.steps++
+	if s.steps > s.limit {
+		return
+	}
+	w := s.order[i]
+	if contact, ok := s.fit(w); ok {
+		s.add(w, contact)
+		s.branch(i + 1)
+		s.removeLast(w)
+	}
+	s.branch(i + 1)
+}
@@ -0,0 +1,286 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+	"github.com/infostellarinc/go-satellite/internal/testsat"
+)
+
+var testStart = time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+
+func at(minutes float64) time.Time {
+	return testStart.Add(time.Duration(minutes * float64(time.Minute)))
+}
+
+// checkSchedule fails on contacts outside their windows, over their quota or overlapping at a station
+func checkSchedule(t *testing.T, p Problem, s Schedule) {
+	t.Helper()
+	counts := map[string]int{}
+	for i, c := range s.Contacts {
+		counts[c.Satellite]++
+		inWindow := false
+		for _, w := range p.Windows {
+			if w.Satellite == c.Satellite && w.Station == c.Station && !c.Start.Before(w.Start) && !c.End.After(w.End) {
+				inWindow = true
+			}
+		}
+		if !inWindow || !c.End.After(c.Start) || c.Duration() < time.Duration(p.Constraints.MinDuration) {
+			t.Errorf("contact %d %+v is not within a window", i, c)
+		}
+		for j, other := range s.Contacts[:i] {
+			if c.Station == other.Station && c.SetupStart.Before(other.TeardownEnd) && other.SetupStart.Before(c.TeardownEnd) {
+				t.Errorf("contacts %d and %d overlap at %s", j, i, c.Station)
+			}
+			if p.Constraints.SatelliteExclusive && c.Satellite == other.Satellite && c.Start.Before(other.End) && other.Start.Before(c.End) {
+				t.Errorf("contacts %d and %d of %s overlap", j, i, c.Satellite)
+			}
+		}
+		if i > 0 && c.Start.Before(s.Contacts[i-1].Start) {
+			t.Errorf("contact %d is out of order", i)
+		}
+	}
+	for _, sat := range p.Satellites {
+		if sat.MaxContacts > 0 && counts[sat.ID] > sat.MaxContacts {
+			t.Errorf("%s has %d contacts over its quota of %d", sat.ID, counts[sat.ID], sat.MaxContacts)
+		}
+	}
+}
+
+func TestGreedy(t *testing.T) {
+	p := Problem{
+		Satellites: []Satellite{{ID: "low"}, {ID: "high", Priority: 2}, {ID: "quota", MaxContacts: 1}},
+		Stations:   []Station{{ID: "a", SetupTime: Duration(2 * time.Minute), TeardownTime: Duration(time.Minute)}, {ID: "b"}},
+		Windows: []Window{
+			{Satellite: "low", Station: "a", Start: at(0), End: at(12)},
+			{Satellite: "high", Station: "a", Start: at(5), End: at(10)},
+			{Satellite: "low", Station: "a", Start: at(11), End: at(14)},
+			{Satellite: "quota", Station: "b", Start: at(0), End: at(10)},
+			{Satellite: "quota", Station: "b", Start: at(20), End: at(30)},
+		},
+		Constraints: Constraints{MinDuration: Duration(90 * time.Second)},
+	}
+	s, err := Solve(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkSchedule(t, p, s)
+
+	want := []Contact{
+		// the lower priority pass before is cut by the setup and teardown of the higher one
+		{Satellite: "low", Station: "a", Start: at(0), End: at(2), SetupStart: at(-2), TeardownEnd: at(3)},
+		{Satellite: "quota", Station: "b", Start: at(0), End: at(10), SetupStart: at(0), TeardownEnd: at(10)},
+		{Satellite: "high", Station: "a", Start: at(5), End: at(10), SetupStart: at(3), TeardownEnd: at(11)},
+	}
+	if len(s.Contacts) != len(want) {
+		t.Fatalf("expected %d contacts, got %+v", len(want), s.Contacts)
+	}
+	for i := range want {
+		if s.Contacts[i] != want[i] {
+			t.Errorf("contact %d is %+v, want %+v", i, s.Contacts[i], want[i])
+		}
+	}
+	// one minute is left after the teardown and setup, under the minimum duration
+	if len(s.Unscheduled) != 2 || s.Unscheduled[0] != p.Windows[2] || s.Unscheduled[1] != p.Windows[4] {
+		t.Errorf("unexpected unscheduled windows %+v", s.Unscheduled)
+	}
+	if s.Score != 2*60+10*60+3*5*60 {
+		t.Errorf("unexpected score %v", s.Score)
+	}
+}
+
+func TestSatelliteExclusive(t *testing.T) {
+	p := Problem{
+		Satellites: []Satellite{{ID: "sat"}},
+		Stations:   []Station{{ID: "a"}, {ID: "b"}},
+		Windows: []Window{
+			{Satellite: "sat", Station: "a", Start: at(0), End: at(10)},
+			{Satellite: "sat", Station: "b", Start: at(5), End: at(20)},
+		},
+	}
+	s, err := Solve(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(s.Contacts) != 2 {
+		t.Fatalf("expected both stations without exclusivity, got %+v", s.Contacts)
+	}
+
+	p.Constraints.SatelliteExclusive = true
+	s, err = Solve(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkSchedule(t, p, s)
+	if len(s.Contacts) != 2 || !s.Contacts[1].Start.Equal(at(10)) || !s.Contacts[1].End.Equal(at(20)) {
+		t.Errorf("expected the second contact to start after the first, got %+v", s.Contacts)
+	}
+}
+
+func TestOptimize(t *testing.T) {
+	// greedy gives the only contact of "quota" the short window and leaves nothing for "other"
+	p := Problem{
+		Satellites: []Satellite{{ID: "quota", MaxContacts: 1}, {ID: "other"}},
+		Stations:   []Station{{ID: "a"}, {ID: "b"}},
+		Windows: []Window{
+			{Satellite: "quota", Station: "a", Start: at(0), End: at(10)},
+			{Satellite: "other", Station: "a", Start: at(0), End: at(10)},
+			{Satellite: "quota", Station: "b", Start: at(5), End: at(35)},
+		},
+	}
+	greedy, err := Solve(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if greedy.Score != 600 {
+		t.Errorf("expected the greedy schedule to score 600, got %v", greedy.Score)
+	}
+
+	p.Options.Mode = ModeOptimize
+	optimized, err := Solve(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkSchedule(t, p, optimized)
+	if optimized.Score != 2400 || len(optimized.Unscheduled) != 1 || optimized.Unscheduled[0] != p.Windows[0] {
+		t.Errorf("unexpected optimized schedule %+v", optimized)
+	}
+
+	// out of search steps the greedy schedule is kept
+	p.Options.SearchLimit = 1
+	limited, err := Solve(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limited.Score < greedy.Score {
+		t.Errorf("unexpected limited schedule %+v", limited)
+	}
+}
+
+func TestOptimizeDoesNotTrim(t *testing.T) {
+	// cutting the contact of "a" to 0-5 lets "b" take 5-100 and "l" take 0-50, a score of 625 minutes. The search only
+	// gives "a" its whole window or skips it, so it finds 620.
+	p := Problem{
+		Satellites: []Satellite{{ID: "h", Priority: 1}, {ID: "l"}, {ID: "a"}, {ID: "b", Priority: 5}},
+		Stations:   []Station{{ID: "s1", SetupTime: Duration(30 * time.Minute), TeardownTime: Duration(30 * time.Minute)}, {ID: "s2"}},
+		Windows: []Window{
+			{Satellite: "h", Station: "s1", Start: at(45), End: at(55)},
+			{Satellite: "l", Station: "s1", Start: at(0), End: at(50)},
+			{Satellite: "l", Station: "s1", Start: at(50), End: at(100)},
+			{Satellite: "a", Station: "s2", Start: at(0), End: at(10)},
+			{Satellite: "b", Station: "s2", Start: at(5), End: at(100)},
+		},
+		Options: Options{Mode: ModeOptimize},
+	}
+	s, err := Solve(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkSchedule(t, p, s)
+	if s.Score != 620*60 {
+		t.Errorf("expected the heuristic score of 620 minutes, got %v", s.Score/60)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	input := `{
+		"satellites": [{"id": "iss", "priority": 1}, {"id": "noaa", "maxContacts": 2}],
+		"stations": [{"id": "svalbard", "setupTime": "2m", "teardownTime": 30}],
+		"windows": [
+			{"satellite": "iss", "station": "svalbard", "start": "2020-05-23T00:00:00Z", "end": "2020-05-23T00:10:00Z"},
+			{"satellite": "noaa", "station": "svalbard", "start": "2020-05-23T00:05:00Z", "end": "2020-05-23T00:20:00Z"}
+		],
+		"constraints": {"minDuration": "1m"},
+		"options": {"mode": "optimize"}
+	}`
+	p, err := ReadProblem(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Stations[0].TeardownTime != Duration(30*time.Second) || p.Options.Mode != ModeOptimize {
+		t.Errorf("unexpected problem %+v", p)
+	}
+	s, err := Solve(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkSchedule(t, p, s)
+
+	var buf bytes.Buffer
+	if err := WriteSchedule(&buf, s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded Schedule
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(decoded.Contacts) != 2 || decoded.Contacts[1] != s.Contacts[1] || decoded.Score != s.Score {
+		t.Errorf("decoded schedule %+v, want %+v", decoded, s)
+	}
+	if !strings.Contains(buf.String(), `"teardownEnd": "2020-05-23T00:10:30Z"`) {
+		t.Errorf("unexpected json:\n%s", buf.String())
+	}
+
+	encoded, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := ReadProblem(bytes.NewReader(encoded)); err != nil {
+		t.Errorf("unexpected error reading the problem back: %v", err)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	for _, input := range []string{
+		`{"satellites": [{"id": "a"}, {"id": "a"}]}`,
+		`{"satellites": [{"id": "a"}], "stations": [{"id": "s"}], "windows": [{"satellite": "a", "station": "t"}]}`,
+		`{"satellites": [{"id": "a"}], "stations": [{"id": "s"}], "windows": [{"satellite": "a", "station": "s", "start": "2020-05-23T00:10:00Z", "end": "2020-05-23T00:00:00Z"}]}`,
+		`{"stations": [{"id": "s", "setupTime": "soon"}]}`,
+		`{"options": {"mode": "fastest"}}`,
+		`{"bogus": 1}`,
+	} {
+		if _, err := ReadProblem(strings.NewReader(input)); !errors.Is(err, ErrInvalidProblem) {
+			t.Errorf("expected ErrInvalidProblem for %s, got %v", input, err)
+		}
+	}
+	if _, err := Solve(Problem{Satellites: []Satellite{{ID: "a", Priority: -1}}}); !errors.Is(err, ErrInvalidProblem) {
+		t.Errorf("expected ErrInvalidProblem, got %v", err)
+	}
+}
+
+func TestWindowsFromAccess(t *testing.T) {
+	iss := testsat.ISS(t, satellite.TLEToSat, satellite.GravityWGS72)
+	// close stations see the same passes
+	stations := []satellite.AccessStation{
+		{ID: "copenhagen", Coordinates: satellite.Geodetic(55.6167, 12.65, 0.005), MinElevation: satellite.Degrees(5)},
+		{ID: "malmo", Coordinates: satellite.Geodetic(55.605, 13.0038, 0.012), MinElevation: satellite.Degrees(5)},
+	}
+	matrix, err := satellite.ComputeAccess([]satellite.AccessSatellite{{ID: "iss", Propagator: iss}}, stations, satellite.AccessSettings{
+		Start: testStart, End: testStart.Add(24 * time.Hour), Step: time.Minute, Gravity: satellite.GravityWGS72,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p := Problem{
+		Satellites:  []Satellite{{ID: "iss"}},
+		Stations:    []Station{{ID: "copenhagen"}, {ID: "malmo"}},
+		Windows:     WindowsFromAccess(matrix),
+		Constraints: Constraints{SatelliteExclusive: true},
+	}
+	if len(p.Windows) != len(matrix.All()) || len(p.Windows) == 0 {
+		t.Fatalf("expected a window per access, got %d", len(p.Windows))
+	}
+	s, err := Solve(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkSchedule(t, p, s)
+	if len(s.Contacts) == 0 {
+		t.Errorf("expected contacts")
+	}
+}
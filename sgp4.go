@@ -12,18 +12,12 @@ var ErrInvalidMeanMotion = errors.New("mean motion is less than 0")
 var ErrInvalidPertubedEccentricity = errors.New("perturbed eccentricity is not within range 0 <= e < 1")
 var ErrInvalidSemilatusRectum = errors.New("semilatus rectum is less than 0")
 var ErrSatelliteDecay = errors.New("mrt is less than 1.0 indicating decay")
+var ErrInvalidTimeSinceEpoch = errors.New("time since epoch is not finite or out of range")
+var ErrNonFiniteState = errors.New("position or velocity is not finite")
 
-type Vector3 struct {
-	X, Y, Z float64
-}
-
-func (v Vector3) Equals(v2 Vector3) bool {
-	return closeFloat(v.X, v2.X) && closeFloat(v.Y, v2.Y) && closeFloat(v.Z, v2.Z)
-}
-
-func closeFloat(a, b float64) bool {
-	return math.Abs(a-b) < 1e-4
-}
+// Largest time since epoch in minutes sgp4 accepts, about 190 years.
+// The model is meaningless long before this and the deep space resonance integration steps 720 minutes at a time.
+const maxTimeSinceEpoch = 1.0e8
 
 // this procedure initializes variables for sgp4.
 func sgp4init(epoch float64, satrec *Satellite) (position, velocity Vector3, err error) {
@@ -317,6 +311,11 @@ func Propagate(sat Satellite, date time.Time) (position, velocity Vector3, err e
 	return sgp4(&sat, timeSince)
 }
 
+// Calculates position and velocity vectors for the given number of minutes since the TLE epoch
+func PropagateMinutes(sat Satellite, tsince float64) (position, velocity Vector3, err error) {
+	return sgp4(&sat, tsince)
+}
+
 // this procedure is the sgp4 prediction model from space command. this is an updated and combined version of sgp4 and sdp4, which were originally published separately in spacetrack report #3. this version follows the methodology from the aiaa paper (2006) describing the history and development of the code.
 // satrec - initialized Satellite struct from sgp4init
 // tsince - time since epoch in minutes
@@ -336,6 +335,10 @@ func sgp4(satrec *Satellite, tsince float64) (Vector3, Vector3, error) {
 
 	vkmpersec := radiusearthkm * xke / 60.0
 
+	if !isFinite(tsince) || math.Abs(tsince) > maxTimeSinceEpoch {
+		return position, velocity, fmt.Errorf("%w: tsince is %f", ErrInvalidTimeSinceEpoch, tsince)
+	}
+
 	satrec.t = tsince
 
 	xmdf = satrec.mo + satrec.mdot*satrec.t
@@ -532,6 +535,10 @@ func sgp4(satrec *Satellite, tsince float64) (Vector3, Vector3, error) {
 	velocity.Y = (mvt*uy + rvdot*vy) * vkmpersec
 	velocity.Z = (mvt*uz + rvdot*vz) * vkmpersec
 
+	if !position.IsFinite() || !velocity.IsFinite() {
+		return Vector3{}, Vector3{}, fmt.Errorf("%w: position %v velocity %v", ErrNonFiniteState, position, velocity)
+	}
+
 	if mrt < 1.0 {
 		return position, velocity, fmt.Errorf("%w: mrt is %f", ErrSatelliteDecay, mrt)
 	}
@@ -1,9 +1,11 @@
 package satellite
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestPropagation(t *testing.T) {
@@ -244,3 +246,27 @@ func floatEach(t *testing.T, input []string) []float64 {
 	}
 	return output
 }
+
+func FuzzPropagate(f *testing.F) {
+	for _, seed := range fuzzSeedTLEs {
+		f.Add(seed[0], seed[1], 0.0, int64(0))
+		f.Add(seed[0], seed[1], 1440.0, int64(-86400))
+	}
+	f.Fuzz(func(t *testing.T, line1, line2 string, minutes float64, seconds int64) {
+		sat, err := TLEToSat(line1, line2, GravityWGS72)
+		if err != nil {
+			return
+		}
+
+		pos, vel, err := PropagateMinutes(sat, minutes)
+		if (err == nil || errors.Is(err, ErrSatelliteDecay)) && (!pos.IsFinite() || !vel.IsFinite()) {
+			t.Fatalf("PropagateMinutes(%v) position %v velocity %v error %v", minutes, pos, vel, err)
+		}
+
+		date := sat.Tle.EpochTime().Add(time.Duration(seconds) * time.Second)
+		pos, vel, err = Propagate(sat, date)
+		if (err == nil || errors.Is(err, ErrSatelliteDecay)) && (!pos.IsFinite() || !vel.IsFinite()) {
+			t.Fatalf("Propagate(%v) position %v velocity %v error %v", date, pos, vel, err)
+		}
+	})
+}
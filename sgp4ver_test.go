@@ -0,0 +1,113 @@
+package satellite_test
+
+import (
+	"errors"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/infostellarinc/go-satellite"
+	"github.com/infostellarinc/go-satellite/internal/sgp4ver"
+)
+
+func readCases(t *testing.T, path string) []sgp4ver.Case {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	cases, err := sgp4ver.ReadCases(f)
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", path, err)
+	}
+	return cases
+}
+
+func TestVerificationSuite(t *testing.T) {
+	// the published cases followed by the local error cases, which have no reference output
+	published := readCases(t, "testdata/SGP4-VER.TLE")
+	local := readCases(t, "testdata/sgp4ver-local.TLE")
+	cases := append(published, local...)
+
+	outFile, err := os.Open("testdata/tcppver.out")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer outFile.Close()
+	output, err := sgp4ver.ReadOutput(outFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, c := range cases {
+		isLocal := i >= len(published)
+		t.Run(c.Line1[2:7], func(t *testing.T) {
+			var wantErr error
+			if c.ErrorCode != 0 {
+				var ok bool
+				wantErr, ok = sgp4ver.ErrorCodes[c.ErrorCode]
+				if !ok {
+					t.Fatalf("unknown error code %d", c.ErrorCode)
+				}
+			}
+
+			states, ok := output[c.CatalogNumber]
+			switch {
+			case !ok && !isLocal:
+				t.Fatalf("missing from tcppver.out")
+			case !ok && wantErr == nil:
+				t.Fatalf("a local case without reference output must end in an error code")
+			}
+			expected := make(map[float64]sgp4ver.State)
+			for _, state := range states {
+				expected[state.Tsince] = state
+			}
+
+			sat, err := satellite.TLEToSat(c.Line1, c.Line2, satellite.GravityWGS72)
+			if err != nil {
+				if wantErr != nil && errors.Is(err, wantErr) {
+					return
+				}
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, tsince := range c.Times() {
+				pos, vel, err := satellite.PropagateMinutes(sat, tsince)
+				if err != nil {
+					if wantErr != nil && errors.Is(err, wantErr) {
+						return
+					}
+					t.Fatalf("tsince %f: unexpected error: %v", tsince, err)
+				}
+				if math.IsNaN(pos.X) || math.IsNaN(vel.X) {
+					t.Fatalf("tsince %f: got NaN state", tsince)
+				}
+				if isLocal {
+					// only the error code is checked
+					continue
+				}
+				want, ok := expected[tsince]
+				if !ok {
+					t.Fatalf("tsince %f: missing from tcppver.out", tsince)
+				}
+				delete(expected, tsince)
+				wantPos := satellite.Vector3{X: want.Position[0], Y: want.Position[1], Z: want.Position[2]}
+				wantVel := satellite.Vector3{X: want.Velocity[0], Y: want.Velocity[1], Z: want.Velocity[2]}
+				if !pos.Equals(wantPos) {
+					t.Errorf("tsince %f: expected position %v, got %v", tsince, wantPos, pos)
+				}
+				if !vel.Equals(wantVel) {
+					t.Errorf("tsince %f: expected velocity %v, got %v", tsince, wantVel, vel)
+				}
+			}
+
+			if wantErr != nil {
+				t.Fatalf("expected error %v, propagated cleanly", wantErr)
+			}
+			for tsince := range expected {
+				t.Errorf("tsince %f: in tcppver.out but not propagated", tsince)
+			}
+		})
+	}
+}
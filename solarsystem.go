@@ -0,0 +1,77 @@
+package satellite
+
+import (
+	"math"
+	"time"
+)
+
+// Calculate the position of the Sun in km, Earth centered and in the mean equator of date.
+// Accurate to about 0.01 degrees between 1950 and 2050.
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, algorithm 29.
+func SunPosition(t time.Time) Vector3 {
+	tut1 := (JDayTime(t) - JULIAN_DAY_JAN_1_2000) / JULIAN_CENTURY
+
+	meanLongitude := 280.460 + 36000.771*tut1
+	meanAnomaly := (357.5291092 + 35999.05034*tut1) * DEG2RAD
+	eclipticLongitude := (meanLongitude + 1.914666471*math.Sin(meanAnomaly) + 0.019994643*math.Sin(2*meanAnomaly)) * DEG2RAD
+	obliquity := (23.439291 - 0.0130042*tut1) * DEG2RAD
+
+	r := (1.000140612 - 0.016708617*math.Cos(meanAnomaly) - 0.000139589*math.Cos(2*meanAnomaly)) * ASTRONOMICAL_UNIT
+
+	return Vector3{
+		X: r * math.Cos(eclipticLongitude),
+		Y: r * math.Cos(obliquity) * math.Sin(eclipticLongitude),
+		Z: r * math.Sin(obliquity) * math.Sin(eclipticLongitude),
+	}
+}
+
+// Calculate the position of the Moon in km, Earth centered and in the mean equator of date.
+// Accurate to about 0.3 degrees in longitude and 0.2 degrees in latitude.
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, algorithm 31.
+func MoonPosition(t time.Time) Vector3 {
+	ttdb := (JDayTime(t) - JULIAN_DAY_JAN_1_2000) / JULIAN_CENTURY
+
+	sinDeg := func(deg float64) float64 { return math.Sin(deg * DEG2RAD) }
+	cosDeg := func(deg float64) float64 { return math.Cos(deg * DEG2RAD) }
+
+	eclipticLongitude := (218.32 + 481267.8813*ttdb +
+		6.29*sinDeg(134.9+477198.85*ttdb) -
+		1.27*sinDeg(259.2-413335.38*ttdb) +
+		0.66*sinDeg(235.7+890534.23*ttdb) +
+		0.21*sinDeg(269.9+954397.70*ttdb) -
+		0.19*sinDeg(357.5+35999.05*ttdb) -
+		0.11*sinDeg(186.6+966404.05*ttdb)) * DEG2RAD
+	eclipticLatitude := (5.13*sinDeg(93.3+483202.03*ttdb) +
+		0.28*sinDeg(228.2+960400.87*ttdb) -
+		0.28*sinDeg(318.3+6003.18*ttdb) -
+		0.17*sinDeg(217.6-407332.20*ttdb)) * DEG2RAD
+	parallax := (0.9508 +
+		0.0518*cosDeg(134.9+477198.85*ttdb) +
+		0.0095*cosDeg(259.2-413335.38*ttdb) +
+		0.0078*cosDeg(235.7+890534.23*ttdb) +
+		0.0028*cosDeg(269.9+954397.70*ttdb)) * DEG2RAD
+	obliquity := (23.439291 - 0.0130042*ttdb) * DEG2RAD
+
+	r := EQUATOR_RADIUS / math.Sin(parallax)
+	cosLat, sinLat := math.Cos(eclipticLatitude), math.Sin(eclipticLatitude)
+	cosLon, sinLon := math.Cos(eclipticLongitude), math.Sin(eclipticLongitude)
+	cosObl, sinObl := math.Cos(obliquity), math.Sin(obliquity)
+
+	return Vector3{
+		X: r * cosLat * cosLon,
+		Y: r * (cosObl*cosLat*sinLon - sinObl*sinLat),
+		Z: r * (sinObl*cosLat*sinLon + cosObl*sinLat),
+	}
+}
+
+// Reports whether a position (km, Earth centered inertial) is in the Earth's shadow using a cylindrical shadow model
+func InEarthShadow(pos Vector3, t time.Time) bool {
+	sun := SunPosition(t)
+	sunUnit := sun.Unit()
+	along := pos.Dot(sunUnit)
+	if along >= 0 {
+		return false
+	}
+	perpendicular := pos.Add(sunUnit.Scale(-along))
+	return perpendicular.Norm() < EQUATOR_RADIUS
+}
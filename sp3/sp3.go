@@ -0,0 +1,351 @@
+// Package sp3 writes precise orbit files in the SP3-c and SP3-d formats.
+//
+// Positions are written in km and velocities in dm/s in ITRF, converted from TEME with the polar motion and UT1-UTC
+// of a satellite.EarthOrientation. Clock values are always written as unknown.
+package sp3
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+)
+
+var ErrInvalidSP3 = errors.New("invalid sp3 file")
+
+// Versions of the format
+const (
+	VersionC = 'c'
+	VersionD = 'd'
+)
+
+// Time systems of the epochs
+const (
+	TimeSystemGPS = "GPS"
+	TimeSystemUTC = "UTC"
+)
+
+// Clock value written for unknown clocks and clock rates
+const badClock = 999999.999999
+
+// Largest satellite count of each version
+var maxSatellites = map[byte]int{VersionC: 85, VersionD: 999}
+
+// Satellite is an orbit to sample, the ID is a system letter and two digits such as "L01" for a low Earth orbiter
+type Satellite struct {
+	ID         string
+	Propagator satellite.Propagator
+}
+
+type SP3 struct {
+	// VersionC or VersionD, defaults to VersionD
+	Version byte
+	// TimeSystemGPS or TimeSystemUTC, epochs are converted from UTC when writing GPS time
+	TimeSystem string
+	// Written in the first line, such as "ORBIT"
+	DataUsed string
+	// Label of the coordinate system, such as "ITRF"
+	CoordinateSystem string
+	// Orbit type, "FIT", "EXT" (extrapolated or predicted), "BCT" or "HLM"
+	OrbitType string
+	Agency    string
+	Comments  []string
+	// Interval between epochs
+	Interval time.Duration
+	// Whether velocity records follow the position records
+	Velocities bool
+	// Satellite IDs in the order of the header
+	Satellites []string
+	Epochs     []Epoch
+}
+
+type Epoch struct {
+	// UTC
+	Time    time.Time
+	Records []Record
+}
+
+type Record struct {
+	ID string
+	// km
+	Position satellite.Vector3
+	// km/s
+	Velocity satellite.Vector3
+}
+
+type SampleSettings struct {
+	Start time.Time
+	// Epochs are sampled every step up to and including the stop time when it falls on a step
+	Stop time.Time
+	Step time.Duration
+	// Defaults to VersionD
+	Version byte
+	// Defaults to TimeSystemGPS
+	TimeSystem string
+	// Defaults to "XXX"
+	Agency     string
+	Comments   []string
+	Velocities bool
+	// Polar motion and UT1-UTC, such as a satellite.EOP read from CelesTrak, required
+	EarthOrientation satellite.EarthOrientation
+}
+
+// Samples propagators returning TEME states, such as satellite.Satellite, at uniform epochs in ITRF.
+// Unlike the other writers the stop time is only sampled when it falls on a step, the format needs a fixed interval.
+func Sample(satellites []Satellite, settings SampleSettings) (SP3, error) {
+	if settings.Step <= 0 || settings.Stop.Before(settings.Start) {
+		return SP3{}, fmt.Errorf("%w: %v to %v every %v", satellite.ErrInvalidSampling, settings.Start, settings.Stop, settings.Step)
+	}
+	if settings.EarthOrientation == nil {
+		return SP3{}, fmt.Errorf("%w: ITRF needs earth orientation parameters", satellite.ErrNoEOP)
+	}
+	if settings.Agency == "" {
+		settings.Agency = "XXX"
+	}
+	s := SP3{
+		Version:          settings.Version,
+		TimeSystem:       settings.TimeSystem,
+		DataUsed:         "ORBIT",
+		CoordinateSystem: "ITRF",
+		OrbitType:        "EXT",
+		Agency:           settings.Agency,
+		Comments:         settings.Comments,
+		Interval:         settings.Step,
+		Velocities:       settings.Velocities,
+	}
+	for _, sat := range satellites {
+		s.Satellites = append(s.Satellites, sat.ID)
+	}
+	for t := settings.Start; !t.After(settings.Stop); t = t.Add(settings.Step) {
+		epoch := Epoch{Time: t.UTC()}
+		for _, sat := range satellites {
+			pos, vel, err := sat.Propagator.PositionVelocity(t)
+			if err != nil {
+				return SP3{}, fmt.Errorf("%s: position at %v: %w", sat.ID, t, err)
+			}
+			pos, vel, err = satellite.TEMEToITRF(pos, vel, t, settings.EarthOrientation)
+			if err != nil {
+				return SP3{}, fmt.Errorf("%s: %w", sat.ID, err)
+			}
+			epoch.Records = append(epoch.Records, Record{ID: sat.ID, Position: pos, Velocity: vel})
+		}
+		s.Epochs = append(s.Epochs, epoch)
+	}
+	if err := s.validate(); err != nil {
+		return SP3{}, err
+	}
+	return s, nil
+}
+
+// GPS-UTC in seconds from each date
+var leapSeconds = []struct {
+	since   time.Time
+	seconds int
+}{
+	{time.Date(1981, 7, 1, 0, 0, 0, 0, time.UTC), 1},
+	{time.Date(1982, 7, 1, 0, 0, 0, 0, time.UTC), 2},
+	{time.Date(1983, 7, 1, 0, 0, 0, 0, time.UTC), 3},
+	{time.Date(1985, 7, 1, 0, 0, 0, 0, time.UTC), 4},
+	{time.Date(1988, 1, 1, 0, 0, 0, 0, time.UTC), 5},
+	{time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), 6},
+	{time.Date(1991, 1, 1, 0, 0, 0, 0, time.UTC), 7},
+	{time.Date(1992, 7, 1, 0, 0, 0, 0, time.UTC), 8},
+	{time.Date(1993, 7, 1, 0, 0, 0, 0, time.UTC), 9},
+	{time.Date(1994, 7, 1, 0, 0, 0, 0, time.UTC), 10},
+	{time.Date(1996, 1, 1, 0, 0, 0, 0, time.UTC), 11},
+	{time.Date(1997, 7, 1, 0, 0, 0, 0, time.UTC), 12},
+	{time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC), 13},
+	{time.Date(2006, 1, 1, 0, 0, 0, 0, time.UTC), 14},
+	{time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC), 15},
+	{time.Date(2012, 7, 1, 0, 0, 0, 0, time.UTC), 16},
+	{time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC), 17},
+	{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), 18},
+}
+
+// Start of GPS time, week 0
+var gpsEpoch = time.Date(1980, 1, 6, 0, 0, 0, 0, time.UTC)
+
+// Returns the GPS time of a UTC time as a calendar time, the leap seconds table ends in 2017
+func UTCToGPS(t time.Time) time.Time {
+	t = t.UTC()
+	offset := 0
+	for _, leap := range leapSeconds {
+		if !t.Before(leap.since) {
+			offset = leap.seconds
+		}
+	}
+	return t.Add(time.Duration(offset) * time.Second)
+}
+
+func (s SP3) version() byte {
+	if s.Version == 0 {
+		return VersionD
+	}
+	return s.Version
+}
+
+func (s SP3) timeSystem() string {
+	if s.TimeSystem == "" {
+		return TimeSystemGPS
+	}
+	return s.TimeSystem
+}
+
+// fileTime converts a UTC epoch to the time system of the file
+func (s SP3) fileTime(t time.Time) time.Time {
+	if s.timeSystem() == TimeSystemGPS {
+		return UTCToGPS(t)
+	}
+	return t.UTC()
+}
+
+func validID(id string) bool {
+	return len(id) == 3 && id[0] >= 'A' && id[0] <= 'Z' && id[1] >= '0' && id[1] <= '9' && id[2] >= '0' && id[2] <= '9'
+}
+
+func (s SP3) validate() error {
+	version := s.version()
+	limit, ok := maxSatellites[version]
+	if !ok {
+		return fmt.Errorf("%w: version %q", ErrInvalidSP3, version)
+	}
+	if system := s.timeSystem(); system != TimeSystemGPS && system != TimeSystemUTC {
+		return fmt.Errorf("%w: time system %q", ErrInvalidSP3, system)
+	}
+	if len(s.Satellites) == 0 || len(s.Satellites) > limit {
+		return fmt.Errorf("%w: %d satellites, version %c holds 1 to %d", ErrInvalidSP3, len(s.Satellites), version, limit)
+	}
+	if len(s.Epochs) == 0 || s.Interval <= 0 {
+		return fmt.Errorf("%w: %d epochs every %v", ErrInvalidSP3, len(s.Epochs), s.Interval)
+	}
+	known := map[string]bool{}
+	for _, id := range s.Satellites {
+		if !validID(id) || known[id] {
+			return fmt.Errorf("%w: satellite id %q", ErrInvalidSP3, id)
+		}
+		known[id] = true
+	}
+	for i, epoch := range s.Epochs {
+		if i > 0 && epoch.Time.Sub(s.Epochs[i-1].Time) != s.Interval {
+			return fmt.Errorf("%w: epoch %d is not %v after the previous one", ErrInvalidSP3, i, s.Interval)
+		}
+		for _, record := range epoch.Records {
+			if !known[record.ID] {
+				return fmt.Errorf("%w: epoch %d: satellite %q is not in the header", ErrInvalidSP3, i, record.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// fileType is the system letter shared by every satellite, or M for mixed files
+func (s SP3) fileType() byte {
+	for _, id := range s.Satellites[1:] {
+		if id[0] != s.Satellites[0][0] {
+			return 'M'
+		}
+	}
+	return s.Satellites[0][0]
+}
+
+// seconds returns the seconds of the minute including the fraction
+func seconds(t time.Time) float64 {
+	return float64(t.Second()) + float64(t.Nanosecond())/1e9
+}
+
+// Writes the file. SP3-c files get exactly 5 satellite lines and 4 comment lines of 57 characters, SP3-d files as
+// many as needed with comments of up to 77 characters.
+func Write(w io.Writer, s SP3) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+	version := s.version()
+	bw := bufio.NewWriter(w)
+
+	first := s.fileTime(s.Epochs[0].Time)
+	mode := byte('P')
+	if s.Velocities {
+		mode = 'V'
+	}
+	fmt.Fprintf(bw, "#%c%c%4d %2d %2d %2d %2d %11.8f %7d %-5.5s %-5.5s %-3.3s %-4.4s\n",
+		version, mode, first.Year(), first.Month(), first.Day(), first.Hour(), first.Minute(), seconds(first),
+		len(s.Epochs), s.DataUsed, s.CoordinateSystem, s.OrbitType, s.Agency)
+
+	sinceGPSEpoch := first.Sub(gpsEpoch)
+	week := int(sinceGPSEpoch / (7 * 24 * time.Hour))
+	secondsOfWeek := (sinceGPSEpoch - time.Duration(week)*7*24*time.Hour).Seconds()
+	// the modified julian day 44244 is the start of GPS time
+	days := int(sinceGPSEpoch / (24 * time.Hour))
+	fraction := (sinceGPSEpoch - time.Duration(days)*24*time.Hour).Seconds() / 86400
+	fmt.Fprintf(bw, "## %4d %15.8f %14.8f %5d %15.13f\n", week, secondsOfWeek, s.Interval.Seconds(), 44244+days, fraction)
+
+	// satellite ids then accuracy exponents, 17 per line in at least 5 lines
+	lines := (len(s.Satellites) + 16) / 17
+	if lines < 5 {
+		lines = 5
+	}
+	for line := 0; line < lines; line++ {
+		if line == 0 {
+			fmt.Fprintf(bw, "+  %3d   ", len(s.Satellites))
+		} else {
+			fmt.Fprint(bw, "+        ")
+		}
+		for i := line * 17; i < (line+1)*17; i++ {
+			if i < len(s.Satellites) {
+				fmt.Fprint(bw, s.Satellites[i])
+			} else {
+				fmt.Fprint(bw, "  0")
+			}
+		}
+		fmt.Fprintln(bw)
+	}
+	for line := 0; line < lines; line++ {
+		fmt.Fprintf(bw, "++       %s\n", strings.Repeat("  0", 17))
+	}
+
+	fmt.Fprintf(bw, "%%c %c  cc %-3s ccc cccc cccc cccc cccc ccccc ccccc ccccc ccccc\n", s.fileType(), s.timeSystem())
+	bw.WriteString("%c cc cc ccc ccc cccc cccc cccc cccc ccccc ccccc ccccc ccccc\n")
+	// base of the accuracy exponents
+	bw.WriteString("%f  1.2500000  1.025000000  0.00000000000  0.000000000000000\n")
+	bw.WriteString("%f  0.0000000  0.000000000  0.00000000000  0.000000000000000\n")
+	bw.WriteString("%i    0    0    0    0      0      0      0      0         0\n")
+	bw.WriteString("%i    0    0    0    0      0      0      0      0         0\n")
+
+	comments := s.Comments
+	width := 77
+	if version == VersionC {
+		width = 57
+		if len(comments) > 4 {
+			comments = comments[:4]
+		}
+	}
+	for i := 0; i < len(comments) || i < 4; i++ {
+		comment := ""
+		if i < len(comments) {
+			comment = comments[i]
+		}
+		if len(comment) > width {
+			comment = comment[:width]
+		}
+		fmt.Fprintf(bw, "/* %s\n", comment)
+	}
+
+	for _, epoch := range s.Epochs {
+		t := s.fileTime(epoch.Time)
+		fmt.Fprintf(bw, "*  %4d %2d %2d %2d %2d %11.8f\n", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), seconds(t))
+		for _, r := range epoch.Records {
+			fmt.Fprintf(bw, "P%s%14.6f%14.6f%14.6f%14.6f\n", r.ID, r.Position.X, r.Position.Y, r.Position.Z, badClock)
+			if s.Velocities {
+				// dm/s
+				v := r.Velocity.Scale(1e4)
+				fmt.Fprintf(bw, "V%s%14.6f%14.6f%14.6f%14.6f\n", r.ID, v.X, v.Y, v.Z, badClock)
+			}
+		}
+	}
+	fmt.Fprintln(bw, "EOF")
+	return bw.Flush()
+}
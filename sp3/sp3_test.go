@@ -0,0 +1,183 @@
+package sp3
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+	"github.com/infostellarinc/go-satellite/internal/testsat"
+)
+
+var testStart = time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+
+var testEOP = satellite.ConstantOrientation{X: 0.1, Y: 0.4, UT1MinusUTC: -0.2}
+
+func TestUTCToGPS(t *testing.T) {
+	tests := []struct {
+		utc  time.Time
+		want time.Duration
+	}{
+		{time.Date(1980, 1, 6, 0, 0, 0, 0, time.UTC), 0},
+		{time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC), 17 * time.Second},
+		{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), 18 * time.Second},
+		{testStart, 18 * time.Second},
+	}
+	for _, test := range tests {
+		if got := UTCToGPS(test.utc).Sub(test.utc); got != test.want {
+			t.Errorf("GPS-UTC at %v is %v, want %v", test.utc, got, test.want)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	sat := testsat.ISS(t, satellite.TLEToSat, satellite.GravityWGS72)
+	for _, version := range []byte{VersionC, VersionD} {
+		t.Run(string(version), func(t *testing.T) {
+			s, err := Sample([]Satellite{{ID: "L01", Propagator: sat}, {ID: "L02", Propagator: sat}}, SampleSettings{
+				Start:            testStart,
+				Stop:             testStart.Add(10*time.Minute + 30*time.Second),
+				Step:             time.Minute,
+				Version:          version,
+				Comments:         []string{"ISS (ZARYA) " + strings.Repeat("x", 80)},
+				Velocities:       true,
+				EarthOrientation: testEOP,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := Write(&buf, s); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+
+			width := 80
+			if version == VersionC {
+				width = 60
+			}
+			for i, line := range lines {
+				if len(line) > width {
+					t.Errorf("line %d is %d characters: %q", i+1, len(line), line)
+				}
+			}
+			header := []string{
+				fmt.Sprintf("#%cV2020  5 23  0  0 18.00000000      11 ORBIT ITRF  EXT XXX ", version),
+				"## 2106 518418.00000000    60.00000000 58992 0.0002083333333",
+				"+    2   L01L02  0  0  0  0  0  0  0  0  0  0  0  0  0  0  0",
+				"%c L  cc GPS ccc cccc cccc cccc cccc ccccc ccccc ccccc ccccc",
+			}
+			for i, line := range []int{0, 1, 2, 12} {
+				if lines[line] != header[i] {
+					t.Errorf("expected line %d %q, got %q", line+1, header[i], lines[line])
+				}
+			}
+			if !strings.HasPrefix(lines[18], "/* ISS (ZARYA) xxx") || lines[21] != "/* " {
+				t.Errorf("expected four comment lines, got %q", lines[18:22])
+			}
+			if lines[22] != "*  2020  5 23  0  0 18.00000000" || lines[len(lines)-1] != "EOF" {
+				t.Errorf("unexpected first epoch %q or end %q", lines[22], lines[len(lines)-1])
+			}
+			if len(lines) != 22+11*5+1 {
+				t.Fatalf("expected 11 epochs of two satellites, got %d lines", len(lines))
+			}
+
+			position := parseRecord(t, lines[len(lines)-3])
+			velocity := parseRecord(t, lines[len(lines)-2])
+			at := testStart.Add(10 * time.Minute)
+			pos, vel, err := satellite.Propagate(sat, at)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			pos, vel, err = satellite.TEMEToITRF(pos, vel, at, testEOP)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !position.EqualsWithin(pos, 1e-6) || !velocity.EqualsWithin(vel.Scale(1e4), 1e-6) {
+				t.Errorf("last records are %v %v, want %v %v", position, velocity, pos, vel.Scale(1e4))
+			}
+		})
+	}
+}
+
+func parseRecord(t *testing.T, line string) satellite.Vector3 {
+	t.Helper()
+	if len(line) != 60 || line[1:4] != "L02" {
+		t.Fatalf("unexpected record %q", line)
+	}
+	var values [4]float64
+	for i := range values {
+		v, err := strconv.ParseFloat(strings.TrimSpace(line[4+14*i:18+14*i]), 64)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		values[i] = v
+	}
+	if values[3] != badClock {
+		t.Errorf("expected an unknown clock, got %f", values[3])
+	}
+	return satellite.Vector3{X: values[0], Y: values[1], Z: values[2]}
+}
+
+func TestWriteUTC(t *testing.T) {
+	sat := testsat.ISS(t, satellite.TLEToSat, satellite.GravityWGS72)
+	s, err := Sample([]Satellite{{ID: "L01", Propagator: sat}}, SampleSettings{
+		Start:            testStart,
+		Stop:             testStart.Add(time.Hour),
+		Step:             time.Minute,
+		TimeSystem:       TimeSystemUTC,
+		EarthOrientation: testEOP,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "#dP2020  5 23  0  0  0.00000000      61 ") {
+		t.Errorf("unexpected first line %q", strings.SplitN(buf.String(), "\n", 2)[0])
+	}
+	if !strings.Contains(buf.String(), "\n%c L  cc UTC ccc") || strings.Contains(buf.String(), "\nVL01") {
+		t.Errorf("expected a UTC file without velocities:\n%s", buf.String())
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	sat := testsat.ISS(t, satellite.TLEToSat, satellite.GravityWGS72)
+	settings := SampleSettings{Start: testStart, Stop: testStart.Add(time.Hour), Step: time.Minute}
+	if _, err := Sample([]Satellite{{ID: "L01", Propagator: sat}}, settings); !errors.Is(err, satellite.ErrNoEOP) {
+		t.Errorf("expected ErrNoEOP, got %v", err)
+	}
+	settings.EarthOrientation = testEOP
+	for _, id := range []string{"ISS", "L1", "l01"} {
+		if _, err := Sample([]Satellite{{ID: id, Propagator: sat}}, settings); !errors.Is(err, ErrInvalidSP3) {
+			t.Errorf("expected ErrInvalidSP3 for %q, got %v", id, err)
+		}
+	}
+	if _, err := Sample([]Satellite{{ID: "L01", Propagator: sat}, {ID: "L01", Propagator: sat}}, settings); !errors.Is(err, ErrInvalidSP3) {
+		t.Errorf("expected ErrInvalidSP3 for duplicate ids, got %v", err)
+	}
+
+	var many []Satellite
+	for i := 1; i <= 86; i++ {
+		many = append(many, Satellite{ID: fmt.Sprintf("L%02d", i), Propagator: sat})
+	}
+	settings.Version = VersionC
+	if _, err := Sample(many, settings); !errors.Is(err, ErrInvalidSP3) {
+		t.Errorf("expected ErrInvalidSP3 for 86 satellites in SP3-c, got %v", err)
+	}
+	settings.Version = 0
+	settings.TimeSystem = "TAI"
+	if _, err := Sample(many[:1], settings); !errors.Is(err, ErrInvalidSP3) {
+		t.Errorf("expected ErrInvalidSP3 for TAI, got %v", err)
+	}
+	settings.Step = 0
+	if _, err := Sample(many[:1], settings); !errors.Is(err, satellite.ErrInvalidSampling) {
+		t.Errorf("expected ErrInvalidSampling, got %v", err)
+	}
+}
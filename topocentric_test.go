@@ -0,0 +1,104 @@
+package satellite
+
+import (
+	"testing"
+)
+
+func testStation(t *testing.T) (Coordinates, GravConst) {
+	t.Helper()
+	grav, err := getGravConst(GravityWGS84)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return Geodetic(35.6762, 139.6503, 0.04), grav
+}
+
+func TestTopocentricRoundTrip(t *testing.T) {
+	station, grav := testStation(t)
+	pos := Vector3{X: -3500, Y: 4200, Z: 4100}
+	vel := Vector3{X: 1.2, Y: -6.1, Z: 3.3}
+
+	for _, frame := range []TopocentricFrame{FrameENU, FrameNED, FrameSEZ} {
+		t.Run(frame.String(), func(t *testing.T) {
+			topoPos, topoVel, err := ECEFToTopocentric(frame, station, grav, pos, vel)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			gotPos, gotVel, err := TopocentricToECEF(frame, station, grav, topoPos, topoVel)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !gotPos.EqualsWithin(pos, 1e-9) || !gotVel.EqualsWithin(vel, 1e-12) {
+				t.Errorf("round trip gave %v %v, want %v %v", gotPos, gotVel, pos, vel)
+			}
+		})
+	}
+}
+
+func TestTopocentricFramesAgree(t *testing.T) {
+	station, grav := testStation(t)
+	pos := Vector3{X: -3500, Y: 4200, Z: 4100}
+
+	enu, _, err := ECEFToTopocentric(FrameENU, station, grav, pos, Vector3{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ned, _, err := ECEFToTopocentric(FrameNED, station, grav, pos, Vector3{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sez, _, err := ECEFToTopocentric(FrameSEZ, station, grav, pos, Vector3{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ned.EqualsWithin(Vector3{X: enu.Y, Y: enu.X, Z: -enu.Z}, 1e-9) {
+		t.Errorf("NED %v does not match ENU %v", ned, enu)
+	}
+	if !sez.EqualsWithin(Vector3{X: -enu.Y, Y: enu.X, Z: enu.Z}, 1e-9) {
+		t.Errorf("SEZ %v does not match ENU %v", sez, enu)
+	}
+
+	for frame, topo := range map[TopocentricFrame]Vector3{FrameENU: enu, FrameNED: ned, FrameSEZ: sez} {
+		look, err := TopocentricToLookAngles(frame, topo)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !LookAnglesToENU(look).EqualsWithin(enu, 1e-9) {
+			t.Errorf("%v look angles %v do not give ENU %v", frame, look, enu)
+		}
+	}
+}
+
+func TestLookAnglesToECI(t *testing.T) {
+	station, grav := testStation(t)
+	jday := JDay(2024, 6, 1, 12, 0, 0)
+	target := Vector3{X: 2000, Y: -5800, Z: 3900}
+
+	look := ECIToLookAngles(target, station, jday, grav)
+	if got := LookAnglesToECI(look, station, jday, grav); !got.EqualsWithin(target, 1e-8) {
+		t.Errorf("LookAnglesToECI() = %v, want %v", got, target)
+	}
+}
+
+func TestECEFStateRotatesWithEarth(t *testing.T) {
+	station, grav := testStation(t)
+	gmst := 1.3
+	ecef := GeodeticToECEF(station, grav)
+
+	pos, vel := ECEFToECIState(ecef, Vector3{}, gmst)
+	omega := Vector3{Z: EARTH_ANGULAR_VELOCITY}
+	if !vel.EqualsWithin(omega.Cross(pos), 1e-12) {
+		t.Errorf("inertial velocity %v of a fixed point, want %v", vel, omega.Cross(pos))
+	}
+	gotPos, gotVel := ECIToECEFState(pos, vel, gmst)
+	if !gotPos.EqualsWithin(ecef, 1e-9) || gotVel.Norm() > 1e-12 {
+		t.Errorf("ECIToECEFState() = %v %v, want %v at rest", gotPos, gotVel, ecef)
+	}
+}
+
+func TestTopocentricUnknownFrame(t *testing.T) {
+	station, _ := testStation(t)
+	if _, err := TopocentricRotation(TopocentricFrame(9), station); err == nil {
+		t.Errorf("expected an error for an unknown frame")
+	}
+}
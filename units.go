@@ -0,0 +1,70 @@
+package satellite
+
+import (
+	"fmt"
+	"math"
+)
+
+// Angle is a plane angle, made with Radians or Degrees so the unit is always explicit
+type Angle struct {
+	radians float64
+}
+
+func Radians(radians float64) Angle {
+	return Angle{radians}
+}
+
+func Degrees(degrees float64) Angle {
+	return Angle{degrees * DEG2RAD}
+}
+
+func (a Angle) Radians() float64 {
+	return a.radians
+}
+
+func (a Angle) Degrees() float64 {
+	return a.radians * RAD2DEG
+}
+
+func (a Angle) String() string {
+	return fmt.Sprintf("%g°", a.Degrees())
+}
+
+// Distance is a length, made with Kilometers or Meters so the unit is always explicit
+type Distance struct {
+	km float64
+}
+
+func Kilometers(km float64) Distance {
+	return Distance{km}
+}
+
+func Meters(m float64) Distance {
+	return Distance{m / 1000}
+}
+
+func (d Distance) Kilometers() float64 {
+	return d.km
+}
+
+func (d Distance) Meters() float64 {
+	return d.km * 1000
+}
+
+func (d Distance) String() string {
+	return fmt.Sprintf("%gkm", d.km)
+}
+
+// Returns geodetic coordinates from a latitude and longitude in degrees and an altitude above the ellipsoid in km
+func Geodetic(latitudeDegrees, longitudeDegrees, altitudeKm float64) Coordinates {
+	return Coordinates{
+		Latitude:  Degrees(latitudeDegrees),
+		Longitude: Degrees(longitudeDegrees),
+		Altitude:  Kilometers(altitudeKm),
+	}
+}
+
+// wrapPi returns an angle in -pi to pi
+func wrapPi(angle float64) float64 {
+	return math.Remainder(angle, TWOPI)
+}
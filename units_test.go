@@ -0,0 +1,77 @@
+package satellite
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestAngleConversions(t *testing.T) {
+	tests := []struct {
+		name        string
+		angle       Angle
+		wantRadians float64
+		wantDegrees float64
+	}{
+		{name: "degrees", angle: Degrees(180), wantRadians: math.Pi, wantDegrees: 180},
+		{name: "radians", angle: Radians(math.Pi / 2), wantRadians: math.Pi / 2, wantDegrees: 90},
+		{name: "negative", angle: Degrees(-45), wantRadians: -math.Pi / 4, wantDegrees: -45},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !closeFloatWithin(tt.angle.Radians(), tt.wantRadians, 1e-12) {
+				t.Errorf("Radians() = %v, want %v", tt.angle.Radians(), tt.wantRadians)
+			}
+			if !closeFloatWithin(tt.angle.Degrees(), tt.wantDegrees, 1e-12) {
+				t.Errorf("Degrees() = %v, want %v", tt.angle.Degrees(), tt.wantDegrees)
+			}
+		})
+	}
+}
+
+func TestDistanceConversions(t *testing.T) {
+	d := Meters(1500)
+	if d.Kilometers() != 1.5 || d.Meters() != 1500 {
+		t.Errorf("expected 1.5 km, got %v km %v m", d.Kilometers(), d.Meters())
+	}
+	if Kilometers(2).Meters() != 2000 {
+		t.Errorf("expected 2000 m, got %v", Kilometers(2).Meters())
+	}
+}
+
+func TestGeodeticRoundTrip(t *testing.T) {
+	grav, err := getGravConst(GravityWGS84)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	jday := JDay(2024, 3, 20, 6, 30, 0)
+	observer := Geodetic(-33.8688, 151.2093, 0.1)
+	pos := LLAToECI(observer, jday, grav)
+	_, coords := ECIToLLA(pos, ThetaGJD(jday))
+	lla, err := LatLongDeg(coords)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !closeFloatWithin(lla.Latitude, -33.8688, 1e-6) {
+		t.Errorf("latitude = %v, want -33.8688", lla.Latitude)
+	}
+	if !closeFloatWithin(lla.Longitude, 151.2093, 1e-6) {
+		t.Errorf("longitude = %v, want 151.2093", lla.Longitude)
+	}
+	if !closeFloatWithin(lla.Altitude, 0.1, 1e-6) {
+		t.Errorf("altitude = %v, want 0.1 km", lla.Altitude)
+	}
+}
+
+func TestLatLongDeg(t *testing.T) {
+	coords, err := LatLongDeg(Geodetic(10, 200, 0.5))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !closeFloatWithin(coords.Latitude, 10, 1e-9) || !closeFloatWithin(coords.Longitude, -160, 1e-9) || coords.Altitude != 0.5 {
+		t.Errorf("LatLongDeg() = %+v, want 10 -160 0.5", coords)
+	}
+	if _, err := LatLongDeg(Coordinates{Latitude: Degrees(91)}); !errors.Is(err, ErrInvalidLatitude) {
+		t.Errorf("expected ErrInvalidLatitude, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package satellite
+
+import (
+	"math"
+)
+
+type Vector3 struct {
+	X, Y, Z float64
+}
+
+// Reports whether every component is neither NaN nor infinite
+func (v Vector3) IsFinite() bool {
+	return isFinite(v.X) && isFinite(v.Y) && isFinite(v.Z)
+}
+
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// Reports whether every component is within 1e-4 of the other vector's
+func (v Vector3) Equals(v2 Vector3) bool {
+	return closeFloat(v.X, v2.X) && closeFloat(v.Y, v2.Y) && closeFloat(v.Z, v2.Z)
+}
+
+// Reports whether every component is within tolerance of the other vector's
+func (v Vector3) EqualsWithin(v2 Vector3, tolerance float64) bool {
+	return closeFloatWithin(v.X, v2.X, tolerance) && closeFloatWithin(v.Y, v2.Y, tolerance) && closeFloatWithin(v.Z, v2.Z, tolerance)
+}
+
+func closeFloat(a, b float64) bool {
+	return closeFloatWithin(a, b, 1e-4)
+}
+
+func closeFloatWithin(a, b, tolerance float64) bool {
+	return math.Abs(a-b) < tolerance
+}
+
+func (v Vector3) Add(v2 Vector3) Vector3 {
+	return Vector3{X: v.X + v2.X, Y: v.Y + v2.Y, Z: v.Z + v2.Z}
+}
+
+func (v Vector3) Sub(v2 Vector3) Vector3 {
+	return Vector3{X: v.X - v2.X, Y: v.Y - v2.Y, Z: v.Z - v2.Z}
+}
+
+func (v Vector3) Scale(s float64) Vector3 {
+	return Vector3{X: v.X * s, Y: v.Y * s, Z: v.Z * s}
+}
+
+func (v Vector3) Dot(v2 Vector3) float64 {
+	return v.X*v2.X + v.Y*v2.Y + v.Z*v2.Z
+}
+
+func (v Vector3) Cross(v2 Vector3) Vector3 {
+	return Vector3{X: v.Y*v2.Z - v.Z*v2.Y, Y: v.Z*v2.X - v.X*v2.Z, Z: v.X*v2.Y - v.Y*v2.X}
+}
+
+// Euclidean length
+func (v Vector3) Norm() float64 {
+	return math.Sqrt(v.Dot(v))
+}
+
+// Returns the vector scaled to unit length, the zero vector stays zero
+func (v Vector3) Unit() Vector3 {
+	n := v.Norm()
+	if n == 0 {
+		return v
+	}
+	return v.Scale(1 / n)
+}
+
+// Angle between two vectors in radians, 0 to pi
+func (v Vector3) Angle(v2 Vector3) float64 {
+	return math.Atan2(v.Cross(v2).Norm(), v.Dot(v2))
+}
+
+// Matrix3 is a 3x3 matrix indexed [row][column]
+type Matrix3 [3][3]float64
+
+func IdentityMatrix3() Matrix3 {
+	return Matrix3{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+}
+
+// Returns the matrix vector product m v
+func (m Matrix3) MulVec(v Vector3) Vector3 {
+	return Vector3{
+		X: m[0][0]*v.X + m[0][1]*v.Y + m[0][2]*v.Z,
+		Y: m[1][0]*v.X + m[1][1]*v.Y + m[1][2]*v.Z,
+		Z: m[2][0]*v.X + m[2][1]*v.Y + m[2][2]*v.Z,
+	}
+}
+
+// Returns the matrix product m m2
+func (m Matrix3) Mul(m2 Matrix3) Matrix3 {
+	var result Matrix3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				result[i][j] += m[i][k] * m2[k][j]
+			}
+		}
+	}
+	return result
+}
+
+func (m Matrix3) Transpose() Matrix3 {
+	return Matrix3{
+		{m[0][0], m[1][0], m[2][0]},
+		{m[0][1], m[1][1], m[2][1]},
+		{m[0][2], m[1][2], m[2][2]},
+	}
+}
+
+// Rotation of the coordinate frame by angle radians about the x axis
+// Reference: Vallado, Fundamentals of Astrodynamics and Applications, equation 3-15.
+func R1(angle float64) Matrix3 {
+	c, s := math.Cos(angle), math.Sin(angle)
+	return Matrix3{{1, 0, 0}, {0, c, s}, {0, -s, c}}
+}
+
+// Rotation of the coordinate frame by angle radians about the y axis
+func R2(angle float64) Matrix3 {
+	c, s := math.Cos(angle), math.Sin(angle)
+	return Matrix3{{c, 0, -s}, {0, 1, 0}, {s, 0, c}}
+}
+
+// Rotation of the coordinate frame by angle radians about the z axis
+func R3(angle float64) Matrix3 {
+	c, s := math.Cos(angle), math.Sin(angle)
+	return Matrix3{{c, s, 0}, {-s, c, 0}, {0, 0, 1}}
+}
@@ -0,0 +1,82 @@
+package satellite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVector3(t *testing.T) {
+	a := Vector3{X: 1, Y: 2, Z: 3}
+	b := Vector3{X: -2, Y: 0.5, Z: 4}
+
+	if got := a.Add(b); got != (Vector3{X: -1, Y: 2.5, Z: 7}) {
+		t.Errorf("Add: got %v", got)
+	}
+	if got := a.Sub(b); got != (Vector3{X: 3, Y: 1.5, Z: -1}) {
+		t.Errorf("Sub: got %v", got)
+	}
+	if got := a.Scale(2); got != (Vector3{X: 2, Y: 4, Z: 6}) {
+		t.Errorf("Scale: got %v", got)
+	}
+	if got := a.Dot(b); got != 11 {
+		t.Errorf("Dot: got %v", got)
+	}
+	c := a.Cross(b)
+	if c != (Vector3{X: 6.5, Y: -10, Z: 4.5}) || c.Dot(a) != 0 || c.Dot(b) != 0 {
+		t.Errorf("Cross: got %v", c)
+	}
+	if got := a.Norm(); math.Abs(got-math.Sqrt(14)) > 1e-15 {
+		t.Errorf("Norm: got %v", got)
+	}
+	if got := a.Unit().Norm(); math.Abs(got-1) > 1e-15 {
+		t.Errorf("Unit: got length %v", got)
+	}
+	if got := (Vector3{}).Unit(); got != (Vector3{}) {
+		t.Errorf("Unit of zero: got %v", got)
+	}
+	if got := (Vector3{X: 1}).Angle(Vector3{X: 1, Y: 1}); math.Abs(got-math.Pi/4) > 1e-15 {
+		t.Errorf("Angle: got %v", got)
+	}
+
+	if !a.EqualsWithin(a.Add(Vector3{Z: 1e-7}), 1e-6) || a.EqualsWithin(a.Add(Vector3{Z: 1e-5}), 1e-6) {
+		t.Errorf("EqualsWithin does not respect the tolerance")
+	}
+}
+
+func TestMatrix3(t *testing.T) {
+	angle := 30 * DEG2RAD
+	tests := []struct {
+		name     string
+		rotation Matrix3
+		axis     Vector3
+		in       Vector3
+		want     Vector3
+	}{
+		// rotating the frame by +90 degrees moves fixed vectors by -90 degrees
+		{"R1", R1(math.Pi / 2), Vector3{X: 1}, Vector3{Y: 1}, Vector3{Z: -1}},
+		{"R2", R2(math.Pi / 2), Vector3{Y: 1}, Vector3{Z: 1}, Vector3{X: -1}},
+		{"R3", R3(math.Pi / 2), Vector3{Z: 1}, Vector3{X: 1}, Vector3{Y: -1}},
+	}
+	for _, test := range tests {
+		if got := test.rotation.MulVec(test.in); !got.EqualsWithin(test.want, 1e-15) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+		if got := test.rotation.MulVec(test.axis); !got.EqualsWithin(test.axis, 1e-15) {
+			t.Errorf("%s: axis moved to %v", test.name, got)
+		}
+	}
+
+	m := R1(angle).Mul(R2(2 * angle)).Mul(R3(-angle))
+	identity := m.Mul(m.Transpose())
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if math.Abs(identity[i][j]-IdentityMatrix3()[i][j]) > 1e-15 {
+				t.Fatalf("rotation times its transpose is not the identity: %v", identity)
+			}
+		}
+	}
+	x := Vector3{X: 1}
+	if !R3(angle).Mul(R3(angle)).MulVec(x).EqualsWithin(R3(2*angle).MulVec(x), 1e-15) {
+		t.Errorf("R3 rotations do not compose")
+	}
+}